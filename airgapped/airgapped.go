@@ -18,6 +18,7 @@ import (
 	"github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
 	"github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
 	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/fsm/types/responses"
 	"github.com/lidofinance/dc4bc/qr"
 	"github.com/syndtr/goleveldb/leveldb"
 )
@@ -41,6 +42,8 @@ type Machine struct {
 
 	qrProcessor qr.Processor
 	db          *leveldb.DB
+
+	signingPolicy *SigningPolicy
 }
 
 func NewMachine(dbPath string) (*Machine, error) {
@@ -180,6 +183,35 @@ func (am *Machine) ProcessOperation(operation client.Operation, storeOperation b
 	return qrPath, nil
 }
 
+// DeclineOperation declines a participation in the DKG round or in the signing the operation invites to.
+// A declined operation is not stored to the operation log, cause there is nothing to replay for it
+func (am *Machine) DeclineOperation(operation client.Operation, reason string) (string, error) {
+	var err error
+	switch fsm.State(operation.Type) {
+	case signature_proposal_fsm.StateAwaitParticipantsConfirmations:
+		err = am.handleDeclineParticipantsConfirmations(&operation, reason)
+	case signing_proposal_fsm.StateSigningAwaitConfirmations:
+		err = am.handleDeclineSigningConfirmations(&operation, reason)
+	default:
+		err = fmt.Errorf("operation type %s cannot be declined", operation.Type)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to decline operation %s: %w", operation.ID, err)
+	}
+
+	operationBz, err := json.Marshal(operation)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal operation: %w", err)
+	}
+
+	qrPath := filepath.Join(am.ResultQRFolder, fmt.Sprintf("dc4bc_qr_%s-response.gif", operation.ID))
+	if err = am.qrProcessor.WriteQR(qrPath, operationBz); err != nil {
+		return "", fmt.Errorf("failed to write QR: %w", err)
+	}
+
+	return qrPath, nil
+}
+
 func (am *Machine) DropOperationsLog(dkgIdentifier string) error {
 	return am.dropRoundOperationLog(dkgIdentifier)
 }
@@ -276,11 +308,24 @@ func (am *Machine) writeErrorRequestToOperation(o *client.Operation, handlerErro
 	if err != nil {
 		return fmt.Errorf("failed to get participant id: %w", err)
 	}
-	req := requests.DKGProposalConfirmationErrorRequest{
+	var req interface{} = requests.DKGProposalConfirmationErrorRequest{
 		Error:         requests.NewFSMError(handlerError),
 		ParticipantId: pid,
 		CreatedAt:     o.CreatedAt,
 	}
+	// signing errors are applied to a certain signing session
+	if fsm.State(o.Type) == signing_proposal_fsm.StateSigningAwaitPartialSigns {
+		var payload responses.SigningPartialSignsParticipantInvitationsResponse
+		if err = json.Unmarshal(o.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+		req = requests.SigningProposalConfirmationErrorRequest{
+			SigningId:     payload.SigningId,
+			Error:         requests.NewFSMError(handlerError),
+			ParticipantId: pid,
+			CreatedAt:     o.CreatedAt,
+		}
+	}
 	errorEvent := eventToErrorMap[fsm.State(o.Type)]
 	reqBz, err := json.Marshal(req)
 	if err != nil {
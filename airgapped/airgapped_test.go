@@ -662,3 +662,60 @@ func runStep(transport *Transport, cb func(n *Node, wg *sync.WaitGroup)) {
 	}
 	wg.Wait()
 }
+
+func TestAirgappedMachine_DeclineOperation(t *testing.T) {
+	testDir := "/tmp/airgapped_test"
+
+	am, err := NewMachine(fmt.Sprintf("%s/%s-decline", testDir, testDB))
+	if err != nil {
+		t.Fatalf("failed to create airgapped machine: %v", err)
+	}
+	am.SetEncryptionKey([]byte(testDB))
+	if err = am.InitKeys(); err != nil {
+		t.Fatalf(err.Error())
+	}
+	am.SetResultQRFolder(testDir)
+	defer os.RemoveAll(testDir)
+
+	pubKey, err := am.pubKey.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal dkg pubkey: %v", err)
+	}
+	initReq := responses.SignatureProposalParticipantInvitationsResponse{
+		&responses.SignatureProposalParticipantInvitationEntry{
+			ParticipantId: 3,
+			Username:      "Participant#3",
+			Threshold:     2,
+			DkgPubKey:     pubKey,
+		},
+	}
+	op := createOperation(t, string(signature_proposal_fsm.StateAwaitParticipantsConfirmations), "", initReq)
+
+	if err = am.handleDeclineParticipantsConfirmations(&op, "not ready"); err != nil {
+		t.Fatalf("failed to decline operation: %v", err)
+	}
+	require.Equal(t, signature_proposal_fsm.EventDeclineProposal, op.Event)
+	require.Len(t, op.ResultMsgs, 1)
+
+	var req requests.SignatureProposalParticipantRequest
+	if err = json.Unmarshal(op.ResultMsgs[0].Data, &req); err != nil {
+		t.Fatalf("failed to unmarshal decline request: %v", err)
+	}
+	require.Equal(t, 3, req.ParticipantId)
+	require.Equal(t, "not ready", req.Reason)
+
+	// a declined DKG round is not initialized on the machine
+	_, ok := am.dkgInstances[DKGIdentifier]
+	require.False(t, ok)
+
+	qrPath, err := am.DeclineOperation(op, "not ready")
+	if err != nil {
+		t.Fatalf("failed to decline operation: %v", err)
+	}
+	_, err = os.Stat(qrPath)
+	require.NoError(t, err)
+
+	op = createOperation(t, string(dkg_proposal_fsm.StateDkgCommitsAwaitConfirmations), "", initReq)
+	_, err = am.DeclineOperation(op, "not ready")
+	require.Error(t, err)
+}
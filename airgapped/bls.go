@@ -12,7 +12,8 @@ import (
 	"github.com/lidofinance/dc4bc/fsm/types/responses"
 )
 
-// handleStateSigningAwaitConfirmations returns a confirmation of participation to create a threshold signature for a data
+// handleStateSigningAwaitConfirmations returns a confirmation of participation to create a threshold signature for a data,
+// the signing is declined if it violates the signing policy
 func (am *Machine) handleStateSigningAwaitConfirmations(o *client.Operation) error {
 	var (
 		payload responses.SigningProposalParticipantInvitationsResponse
@@ -23,6 +24,11 @@ func (am *Machine) handleStateSigningAwaitConfirmations(o *client.Operation) err
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
+	if err = am.checkSigningPolicy("confirmation", o.DKGIdentifier, payload.SigningId, payload.SrcPayload,
+		payload.SigningObject); err != nil {
+		return am.handleDeclineSigningConfirmations(o, err.Error())
+	}
+
 	participantID, err := am.getParticipantID(o.DKGIdentifier)
 	if err != nil {
 		return fmt.Errorf("failed to get paricipant id: %w", err)
@@ -42,7 +48,35 @@ func (am *Machine) handleStateSigningAwaitConfirmations(o *client.Operation) err
 	return nil
 }
 
-// handleStateSigningAwaitPartialSigns takes a data to sign as payload and returns a partial sign for the data to broadcast
+// handleDeclineSigningConfirmations returns a decline of participation in the signing
+func (am *Machine) handleDeclineSigningConfirmations(o *client.Operation, reason string) error {
+	var payload responses.SigningProposalParticipantInvitationsResponse
+	if err := json.Unmarshal(o.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	participantID, err := am.getParticipantID(o.DKGIdentifier)
+	if err != nil {
+		return fmt.Errorf("failed to get paricipant id: %w", err)
+	}
+	req := requests.SigningProposalParticipantRequest{
+		SigningId:     payload.SigningId,
+		ParticipantId: participantID,
+		Reason:        reason,
+		CreatedAt:     o.CreatedAt,
+	}
+	reqBz, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to generate fsm request: %w", err)
+	}
+
+	o.Event = signing_proposal_fsm.EventDeclineSigningConfirmation
+	o.ResultMsgs = append(o.ResultMsgs, createMessage(*o, reqBz))
+	return nil
+}
+
+// handleStateSigningAwaitPartialSigns takes a data to sign as payload and returns a partial sign for the data to broadcast,
+// signings which violate the signing policy and slashable Eth2 objects are refused
 func (am *Machine) handleStateSigningAwaitPartialSigns(o *client.Operation) error {
 	var (
 		payload responses.SigningPartialSignsParticipantInvitationsResponse
@@ -53,6 +87,17 @@ func (am *Machine) handleStateSigningAwaitPartialSigns(o *client.Operation) erro
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
+	if err = am.checkSigningPolicy("partial sign", o.DKGIdentifier, payload.SigningId, payload.SrcPayload,
+		payload.SigningObject); err != nil {
+		return err
+	}
+	if err = am.checkSlashingProtection(o.DKGIdentifier, payload.SrcPayload, payload.SigningObject); err != nil {
+		return fmt.Errorf("slashing protection refused to sign: %w", err)
+	}
+	if err = am.recordPolicySigning(o.DKGIdentifier, payload.SigningId); err != nil {
+		return fmt.Errorf("failed to record signing: %w", err)
+	}
+
 	partialSign, err := am.createPartialSign(payload.SrcPayload, o.DKGIdentifier)
 	if err != nil {
 		return fmt.Errorf("failed to create partialSign for msg: %w", err)
@@ -43,19 +43,9 @@ func (am *Machine) handleStateAwaitParticipantsConfirmations(o *client.Operation
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
-	pid := -1
-	for _, r := range payload {
-		pubkey := am.baseSuite.Point()
-		if err := pubkey.UnmarshalBinary(r.DkgPubKey); err != nil {
-			return fmt.Errorf("failed to unmarshal dkg pubkey: %w", err)
-		}
-		if am.pubKey.Equal(pubkey) {
-			pid = r.ParticipantId
-			break
-		}
-	}
-	if pid < 0 {
-		return fmt.Errorf("failed to determine participant id for DKG #%s", o.DKGIdentifier)
+	pid, err := am.getInvitedParticipantID(o.DKGIdentifier, payload)
+	if err != nil {
+		return err
 	}
 
 	if _, ok := am.dkgInstances[o.DKGIdentifier]; ok {
@@ -87,6 +77,48 @@ func (am *Machine) handleStateAwaitParticipantsConfirmations(o *client.Operation
 	return nil
 }
 
+// handleDeclineParticipantsConfirmations returns a decline of participation in the DKG round
+func (am *Machine) handleDeclineParticipantsConfirmations(o *client.Operation, reason string) error {
+	var payload responses.SignatureProposalParticipantInvitationsResponse
+	if err := json.Unmarshal(o.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	pid, err := am.getInvitedParticipantID(o.DKGIdentifier, payload)
+	if err != nil {
+		return err
+	}
+
+	req := requests.SignatureProposalParticipantRequest{
+		ParticipantId: pid,
+		Reason:        reason,
+		CreatedAt:     o.CreatedAt,
+	}
+	reqBz, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to generate fsm request: %w", err)
+	}
+
+	o.Event = signature_proposal_fsm.EventDeclineProposal
+	o.ResultMsgs = append(o.ResultMsgs, createMessage(*o, reqBz))
+	return nil
+}
+
+// getInvitedParticipantID finds our participant id among the invitations by our DKG public key
+func (am *Machine) getInvitedParticipantID(dkgIdentifier string,
+	payload responses.SignatureProposalParticipantInvitationsResponse) (int, error) {
+	for _, r := range payload {
+		pubkey := am.baseSuite.Point()
+		if err := pubkey.UnmarshalBinary(r.DkgPubKey); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal dkg pubkey: %w", err)
+		}
+		if am.pubKey.Equal(pubkey) {
+			return r.ParticipantId, nil
+		}
+	}
+	return 0, fmt.Errorf("failed to determine participant id for DKG #%s", dkgIdentifier)
+}
+
 func (am *Machine) GetPubKey() kyber.Point {
 	return am.pubKey
 }
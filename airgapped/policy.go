@@ -0,0 +1,355 @@
+package airgapped
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	signingPolicyPrefix         = "signing_policy_signing_"
+	signingPolicyDecisionPrefix = "signing_policy_decision_"
+
+	// PolicyObjectRaw is the object type of signings proposed without a signing object
+	PolicyObjectRaw = "RAW"
+
+	// PayloadFormatSigningRoot is a 32 bytes long raw payload, e.g. a root of an Eth2 object
+	PayloadFormatSigningRoot = "signing_root"
+	// PayloadFormatUTF8 is a raw payload which is a valid UTF-8 text
+	PayloadFormatUTF8 = "utf8"
+	// PayloadFormatJSON is a raw payload which is a valid JSON document
+	PayloadFormatJSON = "json"
+)
+
+// Rules of the signing policy named after the fields of SigningPolicy, a signing refused because of a wrong
+// signing object is refused by PolicyRuleSigningObject
+const (
+	PolicyRuleDKGRounds              = "dkg_rounds"
+	PolicyRuleObjectTypes            = "object_types"
+	PolicyRuleForkVersions           = "fork_versions"
+	PolicyRuleGenesisValidatorsRoots = "genesis_validators_roots"
+	PolicyRuleRawPayloadFormats      = "raw_payload_formats"
+	PolicyRuleMaxSignings            = "max_signings"
+	PolicyRuleSigningObject          = "signing_object"
+)
+
+// SigningPolicyDecision is a record of a signing policy check, the records are kept in the DB forever
+type SigningPolicyDecision struct {
+	Time       time.Time `json:"time"`
+	DKGRoundID string    `json:"dkg_round_id"`
+	SigningID  string    `json:"signing_id"`
+	Step       string    `json:"step"`
+	Allowed    bool      `json:"allowed"`
+	// Rule is the rule which refused the signing, it's empty if the signing is allowed
+	Rule   string `json:"rule,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// policyViolation is an error of the signing policy rule which refused the signing
+type policyViolation struct {
+	rule string
+	err  error
+}
+
+func (v *policyViolation) Error() string {
+	return v.err.Error()
+}
+
+func violation(rule string, err error) error {
+	return &policyViolation{rule: rule, err: err}
+}
+
+// SigningPolicy restricts what the machine signs, signings which violate it are declined or answered with an error.
+// Empty lists allow anything
+type SigningPolicy struct {
+	// DKGRounds are identifiers of the DKG rounds whose keys may sign
+	DKGRounds []string `json:"dkg_rounds"`
+	// ObjectTypes are the allowed types of signing objects, RAW allows signings without an object
+	ObjectTypes []string `json:"object_types"`
+	// ForkVersions and GenesisValidatorsRoots are the allowed signing domains of signing objects. Deposits and
+	// BLS to execution changes are signed with the genesis fork version, deposits are not checked by
+	// GenesisValidatorsRoots since they are signed with the empty root
+	ForkVersions           []eth2.Version `json:"fork_versions"`
+	GenesisValidatorsRoots []eth2.Root    `json:"genesis_validators_roots"`
+	// RawPayloadFormats are the formats a payload signed without an object must have one of
+	RawPayloadFormats []string `json:"raw_payload_formats"`
+	// MaxSignings limits the number of signings of a DKG round per Period, zero disables the limit
+	MaxSignings int    `json:"max_signings"`
+	Period      string `json:"period"`
+
+	period time.Duration
+}
+
+// LoadSigningPolicy reads the JSON encoded signing policy from the file and validates it
+func LoadSigningPolicy(path string) (*SigningPolicy, error) {
+	policyBz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing policy: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(policyBz))
+	decoder.DisallowUnknownFields()
+
+	var policy SigningPolicy
+	if err = decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to decode signing policy: %w", err)
+	}
+	if err = policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks the object types, the payload formats and the signings limit of the policy
+func (p *SigningPolicy) Validate() error {
+	for _, objectType := range p.ObjectTypes {
+		switch objectType {
+		case PolicyObjectRaw, eth2.SigningObjectBlock, eth2.SigningObjectAttestation, eth2.SigningObjectDeposit,
+			eth2.SigningObjectVoluntaryExit, eth2.SigningObjectBLSToExecutionChange:
+		default:
+			return fmt.Errorf("unknown object type %q", objectType)
+		}
+	}
+	for _, format := range p.RawPayloadFormats {
+		switch format {
+		case PayloadFormatSigningRoot, PayloadFormatUTF8, PayloadFormatJSON:
+		default:
+			return fmt.Errorf("unknown payload format %q", format)
+		}
+	}
+	if p.MaxSignings < 0 {
+		return errors.New("max signings must not be negative")
+	}
+	if p.MaxSignings > 0 {
+		period, err := time.ParseDuration(p.Period)
+		if err != nil {
+			return fmt.Errorf("failed to parse period: %w", err)
+		}
+		if period <= 0 {
+			return errors.New("period must be positive")
+		}
+		p.period = period
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVersion(list []eth2.Version, v eth2.Version) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRoot(list []eth2.Root, r eth2.Root) bool {
+	for _, item := range list {
+		if item == r {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPayload checks the DKG round, the signing object or the format of the raw payload against the policy
+func (p *SigningPolicy) checkPayload(dkgIdentifier string, srcPayload []byte, signingObjectBz []byte) error {
+	if len(p.DKGRounds) != 0 && !containsString(p.DKGRounds, dkgIdentifier) {
+		return violation(PolicyRuleDKGRounds, fmt.Errorf("DKG round %s is not allowed", dkgIdentifier))
+	}
+
+	if len(signingObjectBz) == 0 {
+		if len(p.ObjectTypes) != 0 && !containsString(p.ObjectTypes, PolicyObjectRaw) {
+			return violation(PolicyRuleObjectTypes, errors.New("signings without a signing object are not allowed"))
+		}
+		if len(p.RawPayloadFormats) == 0 {
+			return nil
+		}
+		for _, format := range p.RawPayloadFormats {
+			switch {
+			case format == PayloadFormatSigningRoot && len(srcPayload) == 32,
+				format == PayloadFormatUTF8 && utf8.Valid(srcPayload),
+				format == PayloadFormatJSON && json.Valid(srcPayload):
+				return nil
+			}
+		}
+		return violation(PolicyRuleRawPayloadFormats,
+			fmt.Errorf("payload has none of the formats %s", strings.Join(p.RawPayloadFormats, ", ")))
+	}
+
+	signingObject, err := eth2.ParseSigningObject(signingObjectBz)
+	if err != nil {
+		return violation(PolicyRuleSigningObject, fmt.Errorf("failed to parse signing object: %w", err))
+	}
+	objectRoot, err := signingObject.SigningRoot()
+	if err != nil {
+		return violation(PolicyRuleSigningObject, fmt.Errorf("failed to compute signing root of the object: %w", err))
+	}
+	if !bytes.Equal(objectRoot[:], srcPayload) {
+		return violation(PolicyRuleSigningObject, errors.New("payload is not the signing root of the signing object"))
+	}
+	if len(p.ObjectTypes) != 0 && !containsString(p.ObjectTypes, signingObject.Type) {
+		return violation(PolicyRuleObjectTypes, fmt.Errorf("object type %s is not allowed", signingObject.Type))
+	}
+	if len(p.ForkVersions) != 0 && !containsVersion(p.ForkVersions, signingObject.ForkVersion) {
+		return violation(PolicyRuleForkVersions,
+			fmt.Errorf("fork version %x is not allowed", signingObject.ForkVersion))
+	}
+	// deposits are signed with the empty genesis validators root, so they are valid before the genesis
+	if len(p.GenesisValidatorsRoots) != 0 && signingObject.Type != eth2.SigningObjectDeposit &&
+		!containsRoot(p.GenesisValidatorsRoots, signingObject.GenesisValidatorsRoot) {
+		return violation(PolicyRuleGenesisValidatorsRoots,
+			fmt.Errorf("genesis validators root %x is not allowed", signingObject.GenesisValidatorsRoot))
+	}
+	return nil
+}
+
+// SetSigningPolicy sets the policy checked before every signing, nil disables the checks
+func (am *Machine) SetSigningPolicy(policy *SigningPolicy) {
+	am.signingPolicy = policy
+}
+
+func signingPolicyKey(dkgIdentifier, signingID string) []byte {
+	return []byte(fmt.Sprintf("%s%s_%s", signingPolicyPrefix, dkgIdentifier, signingID))
+}
+
+// checkSigningPolicy returns an error if the signing violates the signing policy, every decision is logged
+// and saved to the DB, a signing is refused if its decision is not saved
+func (am *Machine) checkSigningPolicy(step, dkgIdentifier, signingID string, srcPayload, signingObject []byte) error {
+	if am.signingPolicy == nil {
+		return nil
+	}
+
+	err := am.signingPolicy.checkPayload(dkgIdentifier, srcPayload, signingObject)
+	if err == nil {
+		err = am.checkSigningsLimit(dkgIdentifier, signingID)
+	}
+
+	decision := SigningPolicyDecision{
+		Time:       time.Now(),
+		DKGRoundID: dkgIdentifier,
+		SigningID:  signingID,
+		Step:       step,
+		Allowed:    err == nil,
+	}
+	if err != nil {
+		var policyErr *policyViolation
+		if errors.As(err, &policyErr) {
+			decision.Rule = policyErr.rule
+		}
+		decision.Reason = err.Error()
+		log.Printf("signing policy: refused %s of signing %s of DKG round %s: %v", step, signingID, dkgIdentifier, err)
+	} else {
+		log.Printf("signing policy: allowed %s of signing %s of DKG round %s", step, signingID, dkgIdentifier)
+	}
+	if saveErr := am.saveSigningPolicyDecision(decision); saveErr != nil {
+		return fmt.Errorf("signing policy: %w", saveErr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("signing policy: %w", err)
+	}
+	return nil
+}
+
+// saveSigningPolicyDecision appends the decision to the decisions saved in the DB, keys are ordered by time
+func (am *Machine) saveSigningPolicyDecision(decision SigningPolicyDecision) error {
+	decisionBz, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signing policy decision: %w", err)
+	}
+	key := fmt.Sprintf("%s%020d_%s_%s_%s", signingPolicyDecisionPrefix, decision.Time.UnixNano(),
+		decision.DKGRoundID, decision.SigningID, decision.Step)
+	if err = am.db.Put([]byte(key), decisionBz, nil); err != nil {
+		return fmt.Errorf("failed to save signing policy decision: %w", err)
+	}
+	return nil
+}
+
+// GetSigningPolicyDecisions returns all saved decisions of the signing policy, the oldest ones go first
+func (am *Machine) GetSigningPolicyDecisions() ([]SigningPolicyDecision, error) {
+	iter := am.db.NewIterator(util.BytesPrefix([]byte(signingPolicyDecisionPrefix)), nil)
+	defer iter.Release()
+
+	var decisions []SigningPolicyDecision
+	for iter.Next() {
+		var decision SigningPolicyDecision
+		if err := json.Unmarshal(iter.Value(), &decision); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal signing policy decision: %w", err)
+		}
+		decisions = append(decisions, decision)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to get signing policy decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+// checkSigningsLimit checks the number of signings of the DKG round in the last period,
+// the signing itself is not counted, so replayed operations pass
+func (am *Machine) checkSigningsLimit(dkgIdentifier, signingID string) error {
+	if am.signingPolicy.MaxSignings == 0 {
+		return nil
+	}
+
+	since := time.Now().Add(-am.signingPolicy.period)
+	signings := 0
+	prefix := fmt.Sprintf("%s%s_", signingPolicyPrefix, dkgIdentifier)
+	iter := am.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		if bytes.Equal(iter.Key(), signingPolicyKey(dkgIdentifier, signingID)) {
+			return nil
+		}
+		var signedAt time.Time
+		if err := json.Unmarshal(iter.Value(), &signedAt); err != nil {
+			return fmt.Errorf("failed to unmarshal signing time: %w", err)
+		}
+		if signedAt.After(since) {
+			signings++
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("failed to count signings: %w", err)
+	}
+	if signings >= am.signingPolicy.MaxSignings {
+		return violation(PolicyRuleMaxSignings, fmt.Errorf("%d signings of the DKG round in the last %s, the limit is %d",
+			signings, am.signingPolicy.Period, am.signingPolicy.MaxSignings))
+	}
+	return nil
+}
+
+// recordPolicySigning saves the time of the signing to count it against the signings limit
+func (am *Machine) recordPolicySigning(dkgIdentifier, signingID string) error {
+	if am.signingPolicy == nil || am.signingPolicy.MaxSignings == 0 {
+		return nil
+	}
+	key := signingPolicyKey(dkgIdentifier, signingID)
+	if ok, err := am.db.Has(key, nil); err != nil || ok {
+		return err
+	}
+	signedAtBz, err := json.Marshal(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to marshal signing time: %w", err)
+	}
+	if err = am.db.Put(key, signedAtBz, nil); err != nil {
+		return fmt.Errorf("failed to save signing time: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package airgapped
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	client "github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/dkg"
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/fsm/types/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSigningPolicy(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "dc4bc_signing_policy")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	load := func(policyJSON string) (*SigningPolicy, error) {
+		path := filepath.Join(dir, "policy.json")
+		req.NoError(ioutil.WriteFile(path, []byte(policyJSON), 0600))
+		return LoadSigningPolicy(path)
+	}
+
+	policy, err := load(`{
+		"object_types": ["RAW", "ATTESTATION", "DEPOSIT", "VOLUNTARY_EXIT", "BLS_TO_EXECUTION_CHANGE"],
+		"genesis_validators_roots": ["0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"],
+		"raw_payload_formats": ["signing_root"],
+		"max_signings": 2,
+		"period": "24h"
+	}`)
+	req.NoError(err)
+	req.Len(policy.GenesisValidatorsRoots, 1)
+
+	for _, policyJSON := range []string{
+		`{"object_types": ["EXIT"]}`,
+		`{"object_types": ["BLS_CHANGE"]}`,
+		`{"raw_payload_formats": ["xml"]}`,
+		`{"max_signings": 1}`,
+		`{"max_signings": 1, "period": "-1h"}`,
+		`{"dkg_round": ["typo"]}`,
+	} {
+		_, err = load(policyJSON)
+		req.Error(err, policyJSON)
+	}
+}
+
+func TestMachine_SigningPolicy(t *testing.T) {
+	req := require.New(t)
+
+	testDir := "/tmp/airgapped_signing_policy_test"
+	defer os.RemoveAll(testDir)
+	am := newSlashingProtectionMachine(t, testDir, "policy", nil)
+	am.dkgInstances[DKGIdentifier] = &dkg.DKG{ParticipantID: 1}
+
+	policy := &SigningPolicy{
+		DKGRounds:         []string{DKGIdentifier},
+		ObjectTypes:       []string{PolicyObjectRaw, eth2.SigningObjectAttestation},
+		RawPayloadFormats: []string{PayloadFormatSigningRoot},
+		MaxSignings:       2,
+		Period:            "1h",
+	}
+	req.NoError(policy.Validate())
+	am.SetSigningPolicy(policy)
+
+	confirm := func(signingID string, payload []byte, signingObject []byte) client.Operation {
+		op := createOperation(t, string(signing_proposal_fsm.StateSigningAwaitConfirmations), "",
+			responses.SigningProposalParticipantInvitationsResponse{
+				SigningId:     signingID,
+				SrcPayload:    payload,
+				SigningObject: signingObject,
+			})
+		req.NoError(am.handleStateSigningAwaitConfirmations(&op))
+		return op
+	}
+	root := bytes.Repeat([]byte{1}, 32)
+
+	op := confirm("signing_1", root, nil)
+	req.Equal(signing_proposal_fsm.EventConfirmSigningConfirmation, op.Event)
+
+	op = confirm("signing_2", []byte("not a signing root"), nil)
+	req.Equal(signing_proposal_fsm.EventDeclineSigningConfirmation, op.Event)
+	var decline requests.SigningProposalParticipantRequest
+	req.NoError(json.Unmarshal(op.ResultMsgs[0].Data, &decline))
+	req.Contains(decline.Reason, "signing policy")
+
+	block := eth2.NewBlockSigningObject(eth2.BeaconBlockHeader{Slot: 1}, eth2.ForkInfo{})
+	blockBz, err := json.Marshal(block)
+	req.NoError(err)
+	blockRoot, err := block.SigningRoot()
+	req.NoError(err)
+	op = confirm("signing_3", blockRoot[:], blockBz)
+	req.Equal(signing_proposal_fsm.EventDeclineSigningConfirmation, op.Event)
+
+	req.Error(policy.checkPayload("other_dkg_round", root, nil))
+	req.Error(policy.checkPayload(DKGIdentifier, make([]byte, 32), blockBz),
+		"payload must be the root of the signing object")
+
+	// objects of every type are checked by the signing domain
+	domainPolicy := &SigningPolicy{
+		ObjectTypes: []string{eth2.SigningObjectDeposit, eth2.SigningObjectVoluntaryExit,
+			eth2.SigningObjectBLSToExecutionChange},
+		ForkVersions:           []eth2.Version{{0x03}},
+		GenesisValidatorsRoots: []eth2.Root{{0x01}},
+	}
+	req.NoError(domainPolicy.Validate())
+	deposit := eth2.DepositMessage{PubKey: make([]byte, eth2.PubKeyLength),
+		WithdrawalCredentials: make([]byte, eth2.WithdrawalCredentialsLength), Amount: eth2.MinDepositAmount}
+	exit := eth2.VoluntaryExit{Epoch: 1, ValidatorIndex: 42}
+	change := eth2.BLSToExecutionChange{ValidatorIndex: 42, FromBLSPubKey: make([]byte, eth2.PubKeyLength),
+		ToExecutionAddress: make([]byte, eth2.ExecutionAddressLength)}
+	checkObject := func(object *eth2.SigningObject) error {
+		objectBz, err := json.Marshal(object)
+		req.NoError(err)
+		objectRoot, err := object.SigningRoot()
+		req.NoError(err)
+		return domainPolicy.checkPayload(DKGIdentifier, objectRoot[:], objectBz)
+	}
+	req.NoError(checkObject(eth2.NewDepositSigningObject(deposit, [4]byte{0x03})))
+	req.NoError(checkObject(eth2.NewVoluntaryExitSigningObject(exit, [4]byte{0x03}, [32]byte{0x01})))
+	req.NoError(checkObject(eth2.NewBLSToExecutionChangeSigningObject(change, [4]byte{0x03}, [32]byte{0x01})))
+	for _, object := range []*eth2.SigningObject{
+		eth2.NewDepositSigningObject(deposit, [4]byte{0x04}),
+		eth2.NewVoluntaryExitSigningObject(exit, [4]byte{0x04}, [32]byte{0x01}),
+		eth2.NewBLSToExecutionChangeSigningObject(change, [4]byte{0x04}, [32]byte{0x01}),
+	} {
+		var refused *policyViolation
+		req.True(errors.As(checkObject(object), &refused), object.Type)
+		req.Equal(PolicyRuleForkVersions, refused.rule, object.Type)
+	}
+	for _, object := range []*eth2.SigningObject{
+		eth2.NewVoluntaryExitSigningObject(exit, [4]byte{0x03}, [32]byte{0x02}),
+		eth2.NewBLSToExecutionChangeSigningObject(change, [4]byte{0x03}, [32]byte{0x02}),
+	} {
+		var refused *policyViolation
+		req.True(errors.As(checkObject(object), &refused), object.Type)
+		req.Equal(PolicyRuleGenesisValidatorsRoots, refused.rule, object.Type)
+	}
+
+	partialSign := func(signingID string) client.Operation {
+		op := createOperation(t, string(signing_proposal_fsm.StateSigningAwaitPartialSigns), "",
+			responses.SigningPartialSignsParticipantInvitationsResponse{
+				SigningId:  signingID,
+				SrcPayload: root,
+			})
+		result, err := am.GetOperationResult(op)
+		req.NoError(err)
+		return result
+	}
+	req.Equal(signing_proposal_fsm.EventSigningPartialSignReceived, partialSign("signing_1").Event)
+	req.Equal(signing_proposal_fsm.EventSigningPartialSignReceived, partialSign("signing_4").Event)
+	// a replayed signing is not counted again
+	req.Equal(signing_proposal_fsm.EventSigningPartialSignReceived, partialSign("signing_1").Event)
+	req.Equal(signing_proposal_fsm.EventSigningPartialSignError, partialSign("signing_5").Event,
+		"signings limit is reached")
+
+	// every decision is saved in the order it was made
+	decisions, err := am.GetSigningPolicyDecisions()
+	req.NoError(err)
+	expected := []SigningPolicyDecision{
+		{SigningID: "signing_1", Step: "confirmation", Allowed: true},
+		{SigningID: "signing_2", Step: "confirmation", Rule: PolicyRuleRawPayloadFormats},
+		{SigningID: "signing_3", Step: "confirmation", Rule: PolicyRuleObjectTypes},
+		{SigningID: "signing_1", Step: "partial sign", Allowed: true},
+		{SigningID: "signing_4", Step: "partial sign", Allowed: true},
+		{SigningID: "signing_1", Step: "partial sign", Allowed: true},
+		{SigningID: "signing_5", Step: "partial sign", Rule: PolicyRuleMaxSignings},
+	}
+	req.Len(decisions, len(expected))
+	for i, decision := range decisions {
+		req.Equal(DKGIdentifier, decision.DKGRoundID)
+		req.Equal(expected[i].SigningID, decision.SigningID)
+		req.Equal(expected[i].Step, decision.Step)
+		req.Equal(expected[i].Allowed, decision.Allowed)
+		req.Equal(expected[i].Rule, decision.Rule)
+		req.Equal(decision.Allowed, decision.Reason == "")
+		req.False(decision.Time.IsZero())
+		if i > 0 {
+			req.False(decision.Time.Before(decisions[i-1].Time))
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package airgapped
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	client "github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/responses"
+)
+
+// previewPayloadLimit is how many bytes of a payload are shown in a preview
+const previewPayloadLimit = 256
+
+// OperationPreview is a human-readable description of an operation, the operator checks it before
+// the operation is processed
+type OperationPreview struct {
+	Title  string
+	Fields []PreviewField
+}
+
+type PreviewField struct {
+	Name  string
+	Value string
+}
+
+func (p *OperationPreview) add(name string, format string, a ...interface{}) {
+	p.Fields = append(p.Fields, PreviewField{Name: name, Value: fmt.Sprintf(format, a...)})
+}
+
+// String formats the preview as a title followed by aligned fields
+func (p *OperationPreview) String() string {
+	width := 0
+	for _, field := range p.Fields {
+		if len(field.Name) > width {
+			width = len(field.Name)
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(p.Title + "\n")
+	for _, field := range p.Fields {
+		sb.WriteString(fmt.Sprintf("  %-*s  %s\n", width+1, field.Name+":", field.Value))
+	}
+	return sb.String()
+}
+
+// dkgSteps are the phases of the DKG operations
+var dkgSteps = map[fsm.State]string{
+	dkg_proposal_fsm.StateDkgCommitsAwaitConfirmations:   "step 1 of 4, send commits",
+	dkg_proposal_fsm.StateDkgDealsAwaitConfirmations:     "step 2 of 4, send deals to participants",
+	dkg_proposal_fsm.StateDkgResponsesAwaitConfirmations: "step 3 of 4, send responses to deals",
+	dkg_proposal_fsm.StateDkgMasterKeyAwaitConfirmations: "step 4 of 4, reconstruct the master key",
+}
+
+// PreviewOperation decodes the payload of the operation into a preview: the roster and the threshold
+// of invitations, the phase of DKG steps, the payload and the signing ID of signings
+func (am *Machine) PreviewOperation(operation client.Operation) (*OperationPreview, error) {
+	preview := &OperationPreview{}
+	preview.add("Operation ID", "%s", operation.ID)
+	preview.add("Operation type", "%s", operation.Type)
+	preview.add("DKG round", "%s", operation.DKGIdentifier)
+	preview.add("Created at", "%s", operation.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+
+	var err error
+	state := fsm.State(operation.Type)
+	switch state {
+	case signature_proposal_fsm.StateAwaitParticipantsConfirmations:
+		err = am.previewDKGInvitation(preview, operation.Payload)
+	case dkg_proposal_fsm.StateDkgCommitsAwaitConfirmations, dkg_proposal_fsm.StateDkgDealsAwaitConfirmations,
+		dkg_proposal_fsm.StateDkgResponsesAwaitConfirmations, dkg_proposal_fsm.StateDkgMasterKeyAwaitConfirmations:
+		preview.Title = "DKG round " + dkgSteps[state]
+		preview.add("Phase", "%s", dkgSteps[state])
+		err = previewDKGStep(preview, operation.Payload)
+	case signing_proposal_fsm.StateSigningAwaitConfirmations:
+		preview.Title = "Invitation to a signing"
+		var payload responses.SigningProposalParticipantInvitationsResponse
+		if err = json.Unmarshal(operation.Payload, &payload); err != nil {
+			break
+		}
+		preview.add("Signing ID", "%s", payload.SigningId)
+		usernames := make([]string, 0, len(payload.Participants))
+		for _, participant := range payload.Participants {
+			if participant.ParticipantId == payload.InitiatorId {
+				preview.add("Initiator", "%s (#%d)", participant.Username, participant.ParticipantId)
+			}
+			usernames = append(usernames, fmt.Sprintf("%s (#%d)", participant.Username, participant.ParticipantId))
+		}
+		preview.add("Participants", "%d: %s", len(payload.Participants), strings.Join(usernames, ", "))
+		previewPayload(preview, payload.SrcPayload, payload.SigningObject)
+	case signing_proposal_fsm.StateSigningAwaitPartialSigns:
+		preview.Title = "Partial signature of a signing"
+		var payload responses.SigningPartialSignsParticipantInvitationsResponse
+		if err = json.Unmarshal(operation.Payload, &payload); err != nil {
+			break
+		}
+		preview.add("Signing ID", "%s", payload.SigningId)
+		preview.add("Initiator", "#%d", payload.InitiatorId)
+		previewPayload(preview, payload.SrcPayload, payload.SigningObject)
+	case signing_proposal_fsm.StateSigningPartialSignsCollected:
+		preview.Title = "Reconstruction of a threshold signature"
+		var payload responses.SigningProcessParticipantResponse
+		if err = json.Unmarshal(operation.Payload, &payload); err != nil {
+			break
+		}
+		preview.add("Signing ID", "%s", payload.SigningId)
+		usernames := make([]string, 0, len(payload.Participants))
+		for _, participant := range payload.Participants {
+			usernames = append(usernames, participant.Username)
+		}
+		preview.add("Partial signs", "%d from %s", len(payload.Participants), strings.Join(usernames, ", "))
+		previewPayload(preview, payload.SrcPayload, nil)
+	default:
+		return nil, fmt.Errorf("invalid operation type: %s", operation.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return preview, nil
+}
+
+// RawOperationPreview describes the operation without decoding its payload, it's shown when the operation
+// can't be previewed, so the operator still sees what is going to be processed
+func RawOperationPreview(operation client.Operation, previewErr error) *OperationPreview {
+	payloadHash := sha256.Sum256(operation.Payload)
+	preview := &OperationPreview{Title: "Operation that can't be previewed"}
+	preview.add("Operation ID", "%s", operation.ID)
+	preview.add("Operation type", "%s", operation.Type)
+	preview.add("DKG round", "%s", operation.DKGIdentifier)
+	preview.add("Payload size", "%d bytes", len(operation.Payload))
+	preview.add("Payload SHA-256", "%x", payloadHash)
+	preview.add("Preview error", "%v", previewErr)
+	return preview
+}
+
+func (am *Machine) previewDKGInvitation(preview *OperationPreview, payloadBz []byte) error {
+	preview.Title = "Invitation to a DKG round"
+	var payload responses.SignatureProposalParticipantInvitationsResponse
+	if err := json.Unmarshal(payloadBz, &payload); err != nil {
+		return err
+	}
+	if len(payload) != 0 {
+		preview.add("Threshold", "%d of %d", payload[0].Threshold, len(payload))
+	}
+
+	var ownDKGPubKey []byte
+	if am.pubKey != nil {
+		ownDKGPubKey, _ = am.pubKey.MarshalBinary()
+	}
+	for _, participant := range payload {
+		dkgPubKeyHash := sha256.Sum256(participant.DkgPubKey)
+		value := fmt.Sprintf("%s, DKG pub key SHA-256 %x", participant.Username, dkgPubKeyHash[:8])
+		if len(ownDKGPubKey) != 0 && bytes.Equal(participant.DkgPubKey, ownDKGPubKey) {
+			value += " (you)"
+		}
+		preview.add(fmt.Sprintf("Participant #%d", participant.ParticipantId), "%s", value)
+	}
+	return nil
+}
+
+// previewDKGStep lists the participants of a DKG step, the payloads of all steps are lists of participants
+func previewDKGStep(preview *OperationPreview, payloadBz []byte) error {
+	var payload []struct {
+		ParticipantId int
+		Username      string
+	}
+	if err := json.Unmarshal(payloadBz, &payload); err != nil {
+		return err
+	}
+	usernames := make([]string, 0, len(payload))
+	for _, participant := range payload {
+		usernames = append(usernames, fmt.Sprintf("%s (#%d)", participant.Username, participant.ParticipantId))
+	}
+	preview.add("Participants", "%d: %s", len(payload), strings.Join(usernames, ", "))
+	return nil
+}
+
+// previewPayload shows the payload as text if it's printable and as hex otherwise, the signing object
+// is shown with its fields
+func previewPayload(preview *OperationPreview, payload []byte, signingObjectBz []byte) {
+	payloadHash := sha256.Sum256(payload)
+	preview.add("Payload size", "%d bytes", len(payload))
+	preview.add("Payload SHA-256", "%x", payloadHash)
+
+	shown, truncated := payload, ""
+	if len(shown) > previewPayloadLimit {
+		shown, truncated = shown[:previewPayloadLimit], fmt.Sprintf(" (first %d bytes)", previewPayloadLimit)
+	}
+	if isPrintable(shown) {
+		preview.add("Payload", "%q%s", shown, truncated)
+	} else {
+		preview.add("Payload", "0x%s%s", hex.EncodeToString(shown), truncated)
+	}
+
+	if len(signingObjectBz) == 0 {
+		return
+	}
+	signingObject, err := eth2.ParseSigningObject(signingObjectBz)
+	if err != nil {
+		preview.add("Signing object", "invalid: %v", err)
+		return
+	}
+	preview.add("Signing object", "%s", signingObject.Type)
+	preview.add("Fork version", "%x", signingObject.ForkVersion)
+	preview.add("Genesis validators root", "%x", signingObject.GenesisValidatorsRoot)
+	if signingObject.Block != nil {
+		preview.add("Slot", "%d", signingObject.Block.Slot)
+		preview.add("Proposer index", "%d", signingObject.Block.ProposerIndex)
+		preview.add("Body root", "%x", signingObject.Block.BodyRoot)
+	}
+	if signingObject.Attestation != nil {
+		preview.add("Slot", "%d", signingObject.Attestation.Slot)
+		preview.add("Committee index", "%d", signingObject.Attestation.Index)
+		preview.add("Head block root", "%x", signingObject.Attestation.BeaconBlockRoot)
+		preview.add("Source -> target epoch", "%d -> %d", signingObject.Attestation.Source.Epoch,
+			signingObject.Attestation.Target.Epoch)
+	}
+	if signingObject.Deposit != nil {
+		preview.add("Validator public key", "%x", []byte(signingObject.Deposit.PubKey))
+		preview.add("Withdrawal credentials", "%x", []byte(signingObject.Deposit.WithdrawalCredentials))
+		preview.add("Amount", "%d Gwei", signingObject.Deposit.Amount)
+	}
+	if signingObject.VoluntaryExit != nil {
+		preview.add("Validator index", "%d", signingObject.VoluntaryExit.ValidatorIndex)
+		preview.add("Exit epoch", "%d", signingObject.VoluntaryExit.Epoch)
+	}
+	if signingObject.BLSToExecutionChange != nil {
+		preview.add("Validator index", "%d", signingObject.BLSToExecutionChange.ValidatorIndex)
+		preview.add("From BLS public key", "%x", []byte(signingObject.BLSToExecutionChange.FromBLSPubKey))
+		preview.add("To execution address", "%x", []byte(signingObject.BLSToExecutionChange.ToExecutionAddress))
+	}
+	if root, err := signingObject.SigningRoot(); err != nil || !bytes.Equal(root[:], payload) {
+		preview.add("WARNING", "payload is not the signing root of the signing object")
+	}
+}
+
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
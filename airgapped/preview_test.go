@@ -0,0 +1,130 @@
+package airgapped
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/responses"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMachine_PreviewOperation(t *testing.T) {
+	req := require.New(t)
+
+	testDir := "/tmp/airgapped_preview_test"
+	defer os.RemoveAll(testDir)
+	am, err := NewMachine(fmt.Sprintf("%s/%s", testDir, testDB))
+	req.NoError(err)
+	am.SetEncryptionKey([]byte(testDB))
+	req.NoError(am.InitKeys())
+	ownDKGPubKey, err := am.pubKey.MarshalBinary()
+	req.NoError(err)
+
+	preview := func(opType string, payload interface{}) string {
+		op := createOperation(t, opType, "", payload)
+		operationPreview, err := am.PreviewOperation(op)
+		req.NoError(err)
+		text := operationPreview.String()
+		req.Contains(text, op.ID)
+		req.Contains(text, DKGIdentifier)
+		return text
+	}
+
+	text := preview(string(signature_proposal_fsm.StateAwaitParticipantsConfirmations),
+		responses.SignatureProposalParticipantInvitationsResponse{
+			{ParticipantId: 0, Username: "alice", Threshold: 2, DkgPubKey: []byte("alice key")},
+			{ParticipantId: 1, Username: "bob", Threshold: 2, DkgPubKey: ownDKGPubKey},
+			{ParticipantId: 2, Username: "carol", Threshold: 2, DkgPubKey: []byte("carol key")},
+		})
+	req.Contains(text, "Invitation to a DKG round")
+	req.Contains(text, "2 of 3")
+	req.Contains(text, "Participant #0:")
+	req.Regexp(`bob, DKG pub key SHA-256 [0-9a-f]{16} \(you\)`, text)
+	req.NotContains(text, "alice key")
+
+	text = preview(string(dkg_proposal_fsm.StateDkgDealsAwaitConfirmations),
+		responses.DKGProposalCommitParticipantResponse{
+			{ParticipantId: 0, Username: "alice", DkgCommit: []byte("commit")},
+			{ParticipantId: 1, Username: "bob", DkgCommit: []byte("commit")},
+		})
+	req.Contains(text, "step 2 of 4")
+	req.Contains(text, "alice (#0), bob (#1)")
+
+	message := []byte("the message to sign")
+	messageHash := sha256.Sum256(message)
+	text = preview(string(signing_proposal_fsm.StateSigningAwaitConfirmations),
+		responses.SigningProposalParticipantInvitationsResponse{
+			SigningId:   "signing_id",
+			InitiatorId: 1,
+			SrcPayload:  message,
+			Participants: []*responses.SigningProposalParticipantInvitationEntry{
+				{ParticipantId: 0, Username: "alice"},
+				{ParticipantId: 1, Username: "bob"},
+			},
+		})
+	req.Contains(text, "signing_id")
+	req.Contains(text, "bob (#1)")
+	req.Contains(text, `"the message to sign"`)
+	req.Contains(text, hex.EncodeToString(messageHash[:]))
+
+	attestation := eth2.NewAttestationSigningObject(eth2.AttestationData{
+		Slot:   64,
+		Source: eth2.Checkpoint{Epoch: 1},
+		Target: eth2.Checkpoint{Epoch: 2},
+	}, eth2.ForkInfo{})
+	attestationBz, err := json.Marshal(attestation)
+	req.NoError(err)
+	root, err := attestation.SigningRoot()
+	req.NoError(err)
+	text = preview(string(signing_proposal_fsm.StateSigningAwaitPartialSigns),
+		responses.SigningPartialSignsParticipantInvitationsResponse{
+			SigningId:     "signing_id",
+			SrcPayload:    root[:],
+			SigningObject: attestationBz,
+		})
+	req.Contains(text, "0x"+hex.EncodeToString(root[:]))
+	req.Contains(text, "ATTESTATION")
+	req.Contains(text, "1 -> 2")
+	req.NotContains(text, "WARNING")
+
+	text = preview(string(signing_proposal_fsm.StateSigningAwaitPartialSigns),
+		responses.SigningPartialSignsParticipantInvitationsResponse{
+			SigningId:     "signing_id",
+			SrcPayload:    make([]byte, 32),
+			SigningObject: attestationBz,
+		})
+	req.Contains(text, "WARNING")
+
+	text = preview(string(signing_proposal_fsm.StateSigningPartialSignsCollected),
+		responses.SigningProcessParticipantResponse{
+			SigningId:  "signing_id",
+			SrcPayload: []byte(strings.Repeat("a", 300)),
+			Participants: []*responses.SigningProcessParticipantEntry{
+				{ParticipantId: 0, Username: "alice"},
+				{ParticipantId: 1, Username: "bob"},
+			},
+		})
+	req.Contains(text, "2 from alice, bob")
+	req.Contains(text, "first 256 bytes")
+
+	unknown := createOperation(t, "unknown_state", "", nil)
+	_, err = am.PreviewOperation(unknown)
+	req.Error(err)
+
+	// the raw preview is shown instead, so the operator can still decide
+	payloadHash := sha256.Sum256(unknown.Payload)
+	text = RawOperationPreview(unknown, err).String()
+	req.Contains(text, unknown.ID)
+	req.Contains(text, "unknown_state")
+	req.Contains(text, hex.EncodeToString(payloadHash[:]))
+	req.Contains(text, err.Error())
+}
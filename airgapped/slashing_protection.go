@@ -0,0 +1,386 @@
+package airgapped
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	slashingProtectionGVRDBKey          = "slashing_protection_genesis_validators_root"
+	slashingProtectionBlockPrefix       = "slashing_protection_block_"
+	slashingProtectionAttestationPrefix = "slashing_protection_attestation_"
+)
+
+// Slashing protection keeps blocks and attestations signed with master keys of DKG rounds and refuses
+// to create partial signs of slashable objects with the rules of EIP-3076:
+//   - a block is refused if another block of the same slot is signed, or if its slot is not greater
+//     than the lowest signed slot;
+//   - an attestation is refused if another attestation of the same target epoch is signed, if it surrounds
+//     a signed attestation or is surrounded by one, if its source epoch is lower than the lowest signed source
+//     epoch or its target epoch is not greater than the lowest signed target epoch.
+// Signing the same object again is allowed, so operations can be replayed. Records without a signing root,
+// e.g. imported ones, never match an object, so nothing is signed again for their slots and target epochs.
+
+// slashingProtectionKey makes a key of a record of the public key ordered by the slot or the epochs
+func slashingProtectionKey(prefix, pubKey string, slotOrEpochs ...uint64) []byte {
+	key := prefix + pubKey
+	for _, slotOrEpoch := range slotOrEpochs {
+		key += fmt.Sprintf("_%020d", slotOrEpoch)
+	}
+	return []byte(key)
+}
+
+// checkSlashingProtection checks that the signing root is the root of the signing object, then checks that
+// a block or an attestation is not slashable and records it before it's signed. Signings without an object
+// and objects which can't be slashed, e.g. deposits and voluntary exits, are not recorded
+func (am *Machine) checkSlashingProtection(dkgIdentifier string, signingRoot []byte, signingObjectBz []byte) error {
+	if len(signingObjectBz) == 0 {
+		return nil
+	}
+	signingObject, err := eth2.ParseSigningObject(signingObjectBz)
+	if err != nil {
+		return fmt.Errorf("failed to parse signing object: %w", err)
+	}
+	objectRoot, err := signingObject.SigningRoot()
+	if err != nil {
+		return fmt.Errorf("failed to compute signing root of the object: %w", err)
+	}
+	if !bytes.Equal(objectRoot[:], signingRoot) {
+		return fmt.Errorf("signing root %x is not the root %x of the signing object", signingRoot, objectRoot)
+	}
+	if !signingObject.Slashable() {
+		return nil
+	}
+
+	pubKey, err := am.masterPubKey(dkgIdentifier)
+	if err != nil {
+		return err
+	}
+
+	if err = am.checkGenesisValidatorsRoot(signingObject.GenesisValidatorsRoot); err != nil {
+		return err
+	}
+	root := eth2.Root(objectRoot)
+	if signingObject.Block != nil {
+		return am.checkBlock(pubKey, eth2.SignedBlock{Slot: signingObject.Block.Slot, SigningRoot: &root})
+	}
+	return am.checkAttestation(pubKey, eth2.SignedAttestation{
+		SourceEpoch: signingObject.Attestation.Source.Epoch,
+		TargetEpoch: signingObject.Attestation.Target.Epoch,
+		SigningRoot: &root,
+	})
+}
+
+// masterPubKey returns the encoded master key of the DKG round, slashing protection records are kept by it
+func (am *Machine) masterPubKey(dkgIdentifier string) (string, error) {
+	blsKeyring, err := am.loadBLSKeyring(dkgIdentifier)
+	if err != nil {
+		return "", fmt.Errorf("failed to load blsKeyring: %w", err)
+	}
+	pubKey, err := blsKeyring.PubPoly.Commit().MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal master key: %w", err)
+	}
+	return eth2.EncodePubKey(pubKey), nil
+}
+
+// checkGenesisValidatorsRoot checks that the records are kept for a single network, the genesis validators
+// root of the first record is saved
+func (am *Machine) checkGenesisValidatorsRoot(genesisValidatorsRoot eth2.Root) error {
+	gvr, err := am.db.Get([]byte(slashingProtectionGVRDBKey), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		if err = am.db.Put([]byte(slashingProtectionGVRDBKey), genesisValidatorsRoot[:], nil); err != nil {
+			return fmt.Errorf("failed to save genesis validators root: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get genesis validators root: %w", err)
+	}
+	if !bytes.Equal(gvr, genesisValidatorsRoot[:]) {
+		return fmt.Errorf("genesis validators root %x differs from %x of the slashing protection database",
+			genesisValidatorsRoot, gvr)
+	}
+	return nil
+}
+
+func sameSigningRoot(a, b *eth2.Root) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+func (am *Machine) checkBlock(pubKey string, block eth2.SignedBlock) error {
+	blocks, err := am.getSignedBlocks(pubKey)
+	if err != nil {
+		return err
+	}
+	for i, signed := range blocks {
+		if signed.Slot == block.Slot {
+			if sameSigningRoot(signed.SigningRoot, block.SigningRoot) {
+				return nil
+			}
+			return fmt.Errorf("double proposal: another block of slot %d is signed", block.Slot)
+		}
+		// blocks are ordered by slot, the first one is the lowest
+		if i == 0 && block.Slot <= signed.Slot {
+			return fmt.Errorf("slot %d is not greater than the lowest signed slot %d", block.Slot, signed.Slot)
+		}
+	}
+	return am.putSignedBlock(pubKey, block)
+}
+
+func (am *Machine) checkAttestation(pubKey string, attestation eth2.SignedAttestation) error {
+	if attestation.SourceEpoch > attestation.TargetEpoch {
+		return fmt.Errorf("source epoch %d is greater than target epoch %d", attestation.SourceEpoch,
+			attestation.TargetEpoch)
+	}
+	attestations, err := am.getSignedAttestations(pubKey)
+	if err != nil {
+		return err
+	}
+	if len(attestations) == 0 {
+		return am.putSignedAttestation(pubKey, attestation)
+	}
+
+	repeated := false
+	minSourceEpoch := attestations[0].SourceEpoch
+	for _, signed := range attestations {
+		if signed.TargetEpoch == attestation.TargetEpoch {
+			if !sameSigningRoot(signed.SigningRoot, attestation.SigningRoot) {
+				return fmt.Errorf("double vote: another attestation of target epoch %d is signed",
+					attestation.TargetEpoch)
+			}
+			repeated = true
+		}
+		if signed.SourceEpoch < attestation.SourceEpoch && attestation.TargetEpoch < signed.TargetEpoch {
+			return fmt.Errorf("surrounded vote: the attestation is surrounded by the signed one %d->%d",
+				signed.SourceEpoch, signed.TargetEpoch)
+		}
+		if attestation.SourceEpoch < signed.SourceEpoch && signed.TargetEpoch < attestation.TargetEpoch {
+			return fmt.Errorf("surrounding vote: the attestation surrounds the signed one %d->%d",
+				signed.SourceEpoch, signed.TargetEpoch)
+		}
+		if signed.SourceEpoch < minSourceEpoch {
+			minSourceEpoch = signed.SourceEpoch
+		}
+	}
+	if repeated {
+		return nil
+	}
+	if attestation.SourceEpoch < minSourceEpoch {
+		return fmt.Errorf("source epoch %d is lower than the lowest signed source epoch %d",
+			attestation.SourceEpoch, minSourceEpoch)
+	}
+	// attestations are ordered by target epoch, the first one is the lowest
+	if attestation.TargetEpoch <= attestations[0].TargetEpoch {
+		return fmt.Errorf("target epoch %d is not greater than the lowest signed target epoch %d",
+			attestation.TargetEpoch, attestations[0].TargetEpoch)
+	}
+	return am.putSignedAttestation(pubKey, attestation)
+}
+
+func (am *Machine) putSignedBlock(pubKey string, block eth2.SignedBlock) error {
+	blockBz, err := json.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signed block: %w", err)
+	}
+	key := slashingProtectionKey(slashingProtectionBlockPrefix, pubKey, block.Slot)
+	if err = am.db.Put(key, blockBz, nil); err != nil {
+		return fmt.Errorf("failed to save signed block: %w", err)
+	}
+	return nil
+}
+
+func (am *Machine) putSignedAttestation(pubKey string, attestation eth2.SignedAttestation) error {
+	attestationBz, err := json.Marshal(attestation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signed attestation: %w", err)
+	}
+	key := slashingProtectionKey(slashingProtectionAttestationPrefix, pubKey, attestation.TargetEpoch,
+		attestation.SourceEpoch)
+	if err = am.db.Put(key, attestationBz, nil); err != nil {
+		return fmt.Errorf("failed to save signed attestation: %w", err)
+	}
+	return nil
+}
+
+// iterateSlashingProtection calls fn for every record with the prefix ordered by public key and slot or epoch,
+// only records of the public key are iterated if it's not empty
+func (am *Machine) iterateSlashingProtection(prefix, pubKey string, fn func(pubKey string, value []byte) error) error {
+	keyPrefix := prefix
+	if pubKey != "" {
+		keyPrefix += pubKey + "_"
+	}
+	iter := am.db.NewIterator(util.BytesPrefix([]byte(keyPrefix)), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		key := strings.TrimPrefix(string(iter.Key()), prefix)
+		pubKey := key[:strings.Index(key, "_")]
+		if err := fn(pubKey, iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (am *Machine) getSignedBlocks(pubKey string) ([]eth2.SignedBlock, error) {
+	var blocks []eth2.SignedBlock
+	err := am.iterateSlashingProtection(slashingProtectionBlockPrefix, pubKey, func(_ string, value []byte) error {
+		var block eth2.SignedBlock
+		if err := json.Unmarshal(value, &block); err != nil {
+			return fmt.Errorf("failed to unmarshal signed block: %w", err)
+		}
+		blocks = append(blocks, block)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signed blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+func (am *Machine) getSignedAttestations(pubKey string) ([]eth2.SignedAttestation, error) {
+	var attestations []eth2.SignedAttestation
+	err := am.iterateSlashingProtection(slashingProtectionAttestationPrefix, pubKey, func(_ string, value []byte) error {
+		var attestation eth2.SignedAttestation
+		if err := json.Unmarshal(value, &attestation); err != nil {
+			return fmt.Errorf("failed to unmarshal signed attestation: %w", err)
+		}
+		attestations = append(attestations, attestation)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signed attestations: %w", err)
+	}
+	return attestations, nil
+}
+
+// ExportSlashingProtection returns the slashing protection history of all master keys in the EIP-3076
+// interchange format
+func (am *Machine) ExportSlashingProtection() (*eth2.Interchange, error) {
+	gvr, err := am.db.Get([]byte(slashingProtectionGVRDBKey), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, errors.New("slashing protection database is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genesis validators root: %w", err)
+	}
+
+	interchange := &eth2.Interchange{
+		Metadata: eth2.InterchangeMetadata{InterchangeFormatVersion: eth2.InterchangeFormatVersion},
+	}
+	copy(interchange.Metadata.GenesisValidatorsRoot[:], gvr)
+
+	data := make(map[string]*eth2.InterchangeData)
+	dataOf := func(pubKey string) *eth2.InterchangeData {
+		if _, ok := data[pubKey]; !ok {
+			data[pubKey] = &eth2.InterchangeData{
+				PubKey:             pubKey,
+				SignedBlocks:       []eth2.SignedBlock{},
+				SignedAttestations: []eth2.SignedAttestation{},
+			}
+		}
+		return data[pubKey]
+	}
+	err = am.iterateSlashingProtection(slashingProtectionBlockPrefix, "", func(pubKey string, value []byte) error {
+		var block eth2.SignedBlock
+		if err := json.Unmarshal(value, &block); err != nil {
+			return fmt.Errorf("failed to unmarshal signed block: %w", err)
+		}
+		dataOf(pubKey).SignedBlocks = append(dataOf(pubKey).SignedBlocks, block)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export signed blocks: %w", err)
+	}
+	err = am.iterateSlashingProtection(slashingProtectionAttestationPrefix, "", func(pubKey string, value []byte) error {
+		var attestation eth2.SignedAttestation
+		if err := json.Unmarshal(value, &attestation); err != nil {
+			return fmt.Errorf("failed to unmarshal signed attestation: %w", err)
+		}
+		dataOf(pubKey).SignedAttestations = append(dataOf(pubKey).SignedAttestations, attestation)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export signed attestations: %w", err)
+	}
+
+	interchange.Data = make([]eth2.InterchangeData, 0, len(data))
+	for _, keyData := range data {
+		interchange.Data = append(interchange.Data, *keyData)
+	}
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return interchange.Data[i].PubKey < interchange.Data[j].PubKey
+	})
+	return interchange, nil
+}
+
+// ImportSlashingProtection merges the EIP-3076 interchange into the slashing protection database,
+// the interchange must be of the same network as the database. If a record of the interchange conflicts
+// with a record of the database, the signing root is dropped from the record, so the slot or the target epoch
+// can't be signed anymore
+func (am *Machine) ImportSlashingProtection(interchange *eth2.Interchange) error {
+	if interchange.Metadata.InterchangeFormatVersion != eth2.InterchangeFormatVersion {
+		return fmt.Errorf("unsupported interchange format version %q",
+			interchange.Metadata.InterchangeFormatVersion)
+	}
+
+	if err := am.checkGenesisValidatorsRoot(interchange.Metadata.GenesisValidatorsRoot); err != nil {
+		return err
+	}
+	for _, data := range interchange.Data {
+		for _, block := range data.SignedBlocks {
+			var signed eth2.SignedBlock
+			key := slashingProtectionKey(slashingProtectionBlockPrefix, data.PubKey, block.Slot)
+			found, err := am.getRecord(key, &signed)
+			if err != nil {
+				return fmt.Errorf("failed to get signed block: %w", err)
+			}
+			if found && !sameSigningRoot(signed.SigningRoot, block.SigningRoot) {
+				block.SigningRoot = nil
+			}
+			if err = am.putSignedBlock(data.PubKey, block); err != nil {
+				return err
+			}
+		}
+		for _, attestation := range data.SignedAttestations {
+			var signed eth2.SignedAttestation
+			key := slashingProtectionKey(slashingProtectionAttestationPrefix, data.PubKey, attestation.TargetEpoch,
+				attestation.SourceEpoch)
+			found, err := am.getRecord(key, &signed)
+			if err != nil {
+				return fmt.Errorf("failed to get signed attestation: %w", err)
+			}
+			if found && !sameSigningRoot(signed.SigningRoot, attestation.SigningRoot) {
+				attestation.SigningRoot = nil
+			}
+			if err = am.putSignedAttestation(data.PubKey, attestation); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getRecord unmarshals the record with the key, it returns false if there is no such record
+func (am *Machine) getRecord(key []byte, record interface{}) (bool, error) {
+	recordBz, err := am.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err = json.Unmarshal(recordBz, record); err != nil {
+		return false, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	return true, nil
+}
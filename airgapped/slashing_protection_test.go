@@ -0,0 +1,141 @@
+package airgapped
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/corestario/kyber/share"
+	"github.com/lidofinance/dc4bc/dkg"
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/stretchr/testify/require"
+)
+
+func newSlashingProtectionMachine(t *testing.T, dir string, name string, keyring *dkg.BLSKeyring) *Machine {
+	am, err := NewMachine(fmt.Sprintf("%s/%s", dir, name))
+	require.NoError(t, err)
+	am.SetEncryptionKey([]byte(testDB))
+	require.NoError(t, am.InitKeys())
+	if keyring == nil {
+		priPoly := share.NewPriPoly(am.baseSuite, 2, nil, am.baseSuite.RandomStream())
+		keyring = &dkg.BLSKeyring{
+			PubPoly: priPoly.Commit(nil),
+			Share:   priPoly.Shares(3)[0],
+		}
+	}
+	require.NoError(t, am.saveBLSKeyring(DKGIdentifier, keyring))
+	return am
+}
+
+func TestMachine_SlashingProtection(t *testing.T) {
+	req := require.New(t)
+
+	testDir := "/tmp/airgapped_slashing_protection_test"
+	defer os.RemoveAll(testDir)
+	am := newSlashingProtectionMachine(t, testDir, "first", nil)
+
+	forkInfo := eth2.ForkInfo{
+		Fork: eth2.Fork{
+			PreviousVersion: eth2.Version{0x02},
+			CurrentVersion:  eth2.Version{0x03},
+			Epoch:           100,
+		},
+		GenesisValidatorsRoot: eth2.Root{0x4b, 0x36},
+	}
+	sign := func(am *Machine, object *eth2.SigningObject) error {
+		objectBz, err := json.Marshal(object)
+		req.NoError(err)
+		root, err := object.SigningRoot()
+		req.NoError(err)
+		return am.checkSlashingProtection(DKGIdentifier, root[:], objectBz)
+	}
+	block := func(slot uint64, body byte) *eth2.SigningObject {
+		return eth2.NewBlockSigningObject(eth2.BeaconBlockHeader{Slot: slot, BodyRoot: eth2.Root{body}}, forkInfo)
+	}
+	attestation := func(source, target uint64, head byte) *eth2.SigningObject {
+		return eth2.NewAttestationSigningObject(eth2.AttestationData{
+			Slot:            target * eth2.SlotsPerEpoch,
+			BeaconBlockRoot: eth2.Root{head},
+			Source:          eth2.Checkpoint{Epoch: source},
+			Target:          eth2.Checkpoint{Epoch: target},
+		}, forkInfo)
+	}
+
+	// signings without an object are not checked
+	req.NoError(am.checkSlashingProtection(DKGIdentifier, []byte("message"), nil))
+
+	req.NoError(sign(am, block(10, 1)))
+	req.NoError(sign(am, block(10, 1)), "the same block can be signed again")
+	req.Error(sign(am, block(10, 2)), "double proposal")
+	req.Error(sign(am, block(5, 1)), "slot is lower than the lowest signed one")
+	req.NoError(sign(am, block(11, 1)))
+
+	req.NoError(sign(am, attestation(1, 2, 1)))
+	req.NoError(sign(am, attestation(1, 2, 1)), "the same attestation can be signed again")
+	req.Error(sign(am, attestation(1, 2, 2)), "double vote")
+	req.NoError(sign(am, attestation(2, 3, 1)))
+	req.NoError(sign(am, attestation(2, 6, 1)))
+	req.Error(sign(am, attestation(3, 5, 1)), "surrounded by 2->6")
+	req.Error(sign(am, attestation(1, 7, 1)), "surrounds 2->3")
+	req.Error(sign(am, attestation(0, 8, 1)), "source is lower than the lowest signed one")
+	req.NoError(sign(am, attestation(6, 8, 1)))
+
+	// signings without an object, e.g. of 32-byte files, are not checked
+	req.NoError(am.checkSlashingProtection(DKGIdentifier, make([]byte, 32), nil))
+
+	// objects which can't be slashed are signed if the root is the root of the object, nothing is recorded
+	exit := eth2.NewVoluntaryExitSigningObject(eth2.VoluntaryExit{Epoch: 1, ValidatorIndex: 42}, [4]byte{0x03},
+		[32]byte{0x02})
+	exitRoot, err := exit.SigningRoot()
+	req.NoError(err)
+	exitBz, err := json.Marshal(exit)
+	req.NoError(err)
+	req.Error(am.checkSlashingProtection(DKGIdentifier, make([]byte, 32), exitBz),
+		"signing root must be the root of the exit")
+	req.NoError(am.checkSlashingProtection(DKGIdentifier, exitRoot[:], exitBz))
+	req.NoError(am.checkSlashingProtection(DKGIdentifier, exitRoot[:], exitBz))
+
+	wrongRoot := block(12, 1)
+	wrongRootBz, err := json.Marshal(wrongRoot)
+	req.NoError(err)
+	req.Error(am.checkSlashingProtection(DKGIdentifier, make([]byte, 32), wrongRootBz),
+		"signing root must be the root of the object")
+
+	otherNetwork := block(12, 1)
+	otherNetwork.GenesisValidatorsRoot = eth2.Root{0x01}
+	req.Error(sign(am, otherNetwork))
+
+	interchange, err := am.ExportSlashingProtection()
+	req.NoError(err)
+	req.Equal(forkInfo.GenesisValidatorsRoot, interchange.Metadata.GenesisValidatorsRoot)
+	req.Len(interchange.Data, 1)
+	req.Len(interchange.Data[0].SignedBlocks, 2)
+	req.Len(interchange.Data[0].SignedAttestations, 4)
+
+	// the history moves to another machine with the same key
+	keyring, err := am.loadBLSKeyring(DKGIdentifier)
+	req.NoError(err)
+	other := newSlashingProtectionMachine(t, testDir, "second", keyring)
+	interchangeBz, err := json.Marshal(interchange)
+	req.NoError(err)
+	var decoded eth2.Interchange
+	req.NoError(json.Unmarshal(interchangeBz, &decoded))
+	req.NoError(other.ImportSlashingProtection(&decoded))
+	req.NoError(sign(other, block(11, 1)))
+	req.Error(sign(other, block(11, 2)))
+	req.Error(sign(other, attestation(3, 5, 1)))
+
+	// a conflicting record blocks its slot for good
+	conflicting := *interchange
+	conflicting.Data = []eth2.InterchangeData{{
+		PubKey:       interchange.Data[0].PubKey,
+		SignedBlocks: []eth2.SignedBlock{{Slot: 11}},
+	}}
+	req.NoError(other.ImportSlashingProtection(&conflicting))
+	req.Error(sign(other, block(11, 1)))
+	req.NoError(sign(other, block(12, 1)))
+
+	conflicting.Metadata.GenesisValidatorsRoot = eth2.Root{0x01}
+	req.Error(other.ImportSlashingProtection(&conflicting))
+}
@@ -0,0 +1,465 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/lidofinance/dc4bc/transcript"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	maxEventSize   = 16 * 1024 * 1024
+)
+
+// Error is returned when the node responds with an error
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("dc4bc_d API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Client is a typed client of the dc4bc_d HTTP API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+}
+
+// NewClient creates a client for the node with the given base URL, e.g. "http://localhost:8080".
+// A base URL without a scheme is treated as a plain HTTP address
+func NewClient(baseURL string) *Client {
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// SetHTTPClient replaces the underlying HTTP client, e.g. to set up TLS or another timeout
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetToken sets a bearer token which is sent with every request
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+func (c *Client) GetUsername(ctx context.Context) (string, error) {
+	var username string
+	err := c.get(ctx, EndpointGetUsername, nil, &username)
+	return username, err
+}
+
+func (c *Client) GetPubKey(ctx context.Context) (ed25519.PublicKey, error) {
+	var pubKey ed25519.PublicKey
+	err := c.get(ctx, EndpointGetPubKey, nil, &pubKey)
+	return pubKey, err
+}
+
+func (c *Client) GetOperations(ctx context.Context) (map[string]*types.Operation, error) {
+	var operations map[string]*types.Operation
+	err := c.get(ctx, EndpointGetOperations, nil, &operations)
+	return operations, err
+}
+
+// GetOperationJSON returns the operation exactly as it should be passed to the airgapped machine
+func (c *Client) GetOperationJSON(ctx context.Context, operationID string) ([]byte, error) {
+	var operationJSON []byte
+	err := c.get(ctx, EndpointGetOperation, url.Values{"operationID": {operationID}}, &operationJSON)
+	return operationJSON, err
+}
+
+func (c *Client) GetOperation(ctx context.Context, operationID string) (*types.Operation, error) {
+	operationJSON, err := c.GetOperationJSON(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	var operation types.Operation
+	if err = json.Unmarshal(operationJSON, &operation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal operation: %w", err)
+	}
+	return &operation, nil
+}
+
+// GetOperationQRPath makes the node save the operation QR GIF and returns the path to it
+func (c *Client) GetOperationQRPath(ctx context.Context, operationID string) (string, error) {
+	var qrPath string
+	err := c.get(ctx, EndpointGetOperationQRPath, url.Values{"operationID": {operationID}}, &qrPath)
+	return qrPath, err
+}
+
+// GetOperationQR returns the PNG image of the operation QR code
+func (c *Client) GetOperationQR(ctx context.Context, operationID string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, EndpointGetOperationQR, url.Values{"operationID": {operationID}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// HandleProcessedOperation sends the operation processed by the airgapped machine to the node
+func (c *Client) HandleProcessedOperation(ctx context.Context, operation types.Operation) error {
+	return c.post(ctx, EndpointHandleProcessedOp, operation, nil)
+}
+
+// StartDKG sends the DKG proposal and returns the identifier of the new DKG round
+func (c *Client) StartDKG(ctx context.Context, proposal []byte) (string, error) {
+	if err := c.postRaw(ctx, EndpointStartDKG, proposal, nil); err != nil {
+		return "", err
+	}
+	// the node identifies a DKG round by the hash of the proposal
+	dkgID := md5.Sum(proposal)
+	return hex.EncodeToString(dkgID[:]), nil
+}
+
+// ProposeSign proposes to sign the data with the key of the DKG round, dkgID is hex-encoded
+func (c *Client) ProposeSign(ctx context.Context, dkgID string, data []byte) error {
+	rawDKGID, err := hex.DecodeString(dkgID)
+	if err != nil {
+		return fmt.Errorf("failed to decode dkgID: %w", err)
+	}
+	return c.post(ctx, EndpointProposeSignMessage, ProposeSignRequest{DKGID: rawDKGID, Data: data}, nil)
+}
+
+// ProposeSignObject proposes to sign the signing root of the JSON encoded eth2.SigningObject with the key
+// of the DKG round, dkgID is hex-encoded
+func (c *Client) ProposeSignObject(ctx context.Context, dkgID string, signingRoot []byte, signingObject []byte) error {
+	rawDKGID, err := hex.DecodeString(dkgID)
+	if err != nil {
+		return fmt.Errorf("failed to decode dkgID: %w", err)
+	}
+	return c.post(ctx, EndpointProposeSignMessage, ProposeSignRequest{DKGID: rawDKGID, Data: signingRoot,
+		SigningObject: signingObject}, nil)
+}
+
+func (c *Client) DeclineDKG(ctx context.Context, dkgID, reason string) error {
+	return c.sendDecision(ctx, EndpointDeclineDKG, dkgID, "", reason)
+}
+
+func (c *Client) AbortDKG(ctx context.Context, dkgID, reason string) error {
+	return c.sendDecision(ctx, EndpointAbortDKG, dkgID, "", reason)
+}
+
+func (c *Client) DeclineSigning(ctx context.Context, dkgID, signingID, reason string) error {
+	return c.sendDecision(ctx, EndpointDeclineSigning, dkgID, signingID, reason)
+}
+
+func (c *Client) AbortSigning(ctx context.Context, dkgID, signingID, reason string) error {
+	return c.sendDecision(ctx, EndpointAbortSigning, dkgID, signingID, reason)
+}
+
+func (c *Client) sendDecision(ctx context.Context, endpoint, dkgID, signingID, reason string) error {
+	rawDKGID, err := hex.DecodeString(dkgID)
+	if err != nil {
+		return fmt.Errorf("failed to decode dkgID: %w", err)
+	}
+	req := ParticipantDecisionRequest{
+		DKGID:     rawDKGID,
+		SigningID: signingID,
+		Reason:    reason,
+	}
+	return c.post(ctx, endpoint, req, nil)
+}
+
+// SendMessage sends the message to the storage on behalf of the node
+func (c *Client) SendMessage(ctx context.Context, message storage.Message) error {
+	return c.post(ctx, EndpointSendMessage, message, nil)
+}
+
+func (c *Client) SaveOffset(ctx context.Context, offset uint64) error {
+	return c.post(ctx, EndpointSaveOffset, SaveOffsetRequest{Offset: &offset}, nil)
+}
+
+func (c *Client) GetOffset(ctx context.Context) (uint64, error) {
+	var offset uint64
+	err := c.get(ctx, EndpointGetOffset, nil, &offset)
+	return offset, err
+}
+
+// GetSignatures returns verified reconstructed signatures of the DKG round grouped by signing ID
+func (c *Client) GetSignatures(ctx context.Context, dkgID string) (map[string][]types.ReconstructedSignature, error) {
+	var signatures map[string][]types.ReconstructedSignature
+	err := c.get(ctx, EndpointGetSignatures, url.Values{"dkgID": {dkgID}}, &signatures)
+	return signatures, err
+}
+
+func (c *Client) GetSignatureByID(ctx context.Context, dkgID, signingID string) ([]types.ReconstructedSignature, error) {
+	var signatures []types.ReconstructedSignature
+	err := c.get(ctx, EndpointGetSignatureByID, url.Values{"dkgID": {dkgID}, "id": {signingID}}, &signatures)
+	return signatures, err
+}
+
+// GetRejectedSignatures returns broadcasted signatures which failed the verification
+func (c *Client) GetRejectedSignatures(ctx context.Context, dkgID string) (map[string][]types.ReconstructedSignature, error) {
+	var signatures map[string][]types.ReconstructedSignature
+	err := c.get(ctx, EndpointGetRejectedSignatures, url.Values{"dkgID": {dkgID}}, &signatures)
+	return signatures, err
+}
+
+func (c *Client) GetFSMDump(ctx context.Context, dkgID string) (*state_machines.FSMDump, error) {
+	var dump state_machines.FSMDump
+	if err := c.get(ctx, EndpointGetFSMDump, url.Values{"dkgID": {dkgID}}, &dump); err != nil {
+		return nil, err
+	}
+	return &dump, nil
+}
+
+// GetFSMList returns states of all DKG rounds served by the node
+func (c *Client) GetFSMList(ctx context.Context) (map[string]string, error) {
+	var fsmList map[string]string
+	err := c.get(ctx, EndpointGetFSMList, nil, &fsmList)
+	return fsmList, err
+}
+
+func (c *Client) GetSigningQueue(ctx context.Context, dkgID string) ([]requests.SigningProposalStartRequest, error) {
+	var queue []requests.SigningProposalStartRequest
+	err := c.get(ctx, EndpointGetSigningQueue, url.Values{"dkgID": {dkgID}}, &queue)
+	return queue, err
+}
+
+// GetRoundStatus returns summaries of the DKG round and its signings, or of all DKG rounds if dkgID is empty
+func (c *Client) GetRoundStatus(ctx context.Context, dkgID string) ([]RoundStatus, error) {
+	var query url.Values
+	if dkgID != "" {
+		query = url.Values{"dkgID": {dkgID}}
+	}
+	var statuses []RoundStatus
+	err := c.get(ctx, EndpointGetRoundStatus, query, &statuses)
+	return statuses, err
+}
+
+// GetDeadLetters returns the messages which the node failed to process
+func (c *Client) GetDeadLetters(ctx context.Context) ([]types.DeadLetter, error) {
+	var letters []types.DeadLetter
+	err := c.get(ctx, EndpointGetDeadLetters, nil, &letters)
+	return letters, err
+}
+
+// RetryDeadLetter processes the failed message again
+func (c *Client) RetryDeadLetter(ctx context.Context, messageID string) error {
+	return c.post(ctx, EndpointRetryDeadLetter, DeadLetterRequest{MessageID: messageID}, nil)
+}
+
+// GetLogCheckpoints returns the head hash of the log processed by the node and the head hashes
+// published by participants
+func (c *Client) GetLogCheckpoints(ctx context.Context) (*LogCheckpoints, error) {
+	var checkpoints LogCheckpoints
+	if err := c.get(ctx, EndpointGetLogCheckpoints, nil, &checkpoints); err != nil {
+		return nil, err
+	}
+	return &checkpoints, nil
+}
+
+// PublishLogCheckpoint sends the head hash of the log processed by the node to the log
+// on behalf of a participant of the DKG round
+func (c *Client) PublishLogCheckpoint(ctx context.Context, dkgID string) error {
+	rawDKGID, err := hex.DecodeString(dkgID)
+	if err != nil {
+		return fmt.Errorf("failed to decode dkgID: %w", err)
+	}
+	return c.post(ctx, EndpointPublishLogCheckpoint, LogCheckpointRequest{DKGID: rawDKGID}, nil)
+}
+
+// GetEquivocations returns evidences of participants who have signed different messages for the same step
+// of a DKG round
+func (c *Client) GetEquivocations(ctx context.Context) ([]types.Equivocation, error) {
+	var equivocations []types.Equivocation
+	err := c.get(ctx, EndpointGetEquivocations, nil, &equivocations)
+	return equivocations, err
+}
+
+// ExportTranscript returns the archive of the DKG round signed by the node
+func (c *Client) ExportTranscript(ctx context.Context, dkgID string) (*transcript.Archive, error) {
+	var archive transcript.Archive
+	if err := c.get(ctx, EndpointExportTranscript, url.Values{"dkgID": {dkgID}}, &archive); err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// DiscardDeadLetter removes the failed message from the dead-letter list without processing it
+func (c *Client) DiscardDeadLetter(ctx context.Context, messageID string) error {
+	return c.post(ctx, EndpointDiscardDeadLetter, DeadLetterRequest{MessageID: messageID}, nil)
+}
+
+// WatchEvents streams events of the node and calls the handler for every event until the context is done,
+// the handler returns an error or the connection is closed. An empty dkgID means events of all DKG rounds
+func (c *Client) WatchEvents(ctx context.Context, dkgID string, handler func(Event) error) error {
+	var query url.Values
+	if dkgID != "" {
+		query = url.Values{"dkgID": {dkgID}}
+	}
+
+	// the stream lasts until it's cancelled, so the timeout of the HTTP client must not be applied to it
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+	resp, err := c.doWith(ctx, &streamClient, http.MethodGet, EndpointWatchEvents, query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read body: %w", err)
+		}
+		return decodeError(resp.StatusCode, body)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	// events with operations can be large
+	scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+	var data []byte
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+		case line == "" && len(data) > 0:
+			var event Event
+			if err = json.Unmarshal(data, &event); err != nil {
+				return fmt.Errorf("failed to unmarshal event: %w", err)
+			}
+			data = data[:0]
+			if err = handler(event); err != nil {
+				return err
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values, result interface{}) error {
+	resp, err := c.do(ctx, http.MethodGet, endpoint, query, nil)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, result)
+}
+
+func (c *Client) post(ctx context.Context, endpoint string, request interface{}, result interface{}) error {
+	requestBz, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.postRaw(ctx, endpoint, requestBz, result)
+}
+
+func (c *Client) postRaw(ctx context.Context, endpoint string, body []byte, result interface{}) error {
+	resp, err := c.do(ctx, http.MethodPost, endpoint, nil, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, result)
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, query url.Values, body io.Reader) (*http.Response, error) {
+	return c.doWith(ctx, c.httpClient, method, endpoint, query, body)
+}
+
+func (c *Client) doWith(ctx context.Context, httpClient *http.Client, method, endpoint string, query url.Values,
+	body io.Reader) (*http.Response, error) {
+	requestURL := c.baseURL + endpoint
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", endpoint, err)
+	}
+	return resp, nil
+}
+
+// decodeResponse decodes the result of the response envelope, result may be nil if the result is not needed
+func decodeResponse(resp *http.Response, result interface{}) error {
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp.StatusCode, body)
+	}
+
+	var envelope struct {
+		ErrorMessage string          `json:"error_message,omitempty"`
+		Result       json.RawMessage `json:"result"`
+	}
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if envelope.ErrorMessage != "" {
+		return &Error{StatusCode: resp.StatusCode, Message: envelope.ErrorMessage}
+	}
+	if result == nil || len(envelope.Result) == 0 {
+		return nil
+	}
+	if err = json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+	return nil
+}
+
+func decodeError(statusCode int, body []byte) error {
+	var envelope Response
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.ErrorMessage != "" {
+		return &Error{StatusCode: statusCode, Message: envelope.ErrorMessage}
+	}
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return &Error{StatusCode: statusCode, Message: message}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/stretchr/testify/require"
+)
+
+func writeResponse(w http.ResponseWriter, statusCode int, resp Response) {
+	w.WriteHeader(statusCode)
+	respBz, _ := json.Marshal(resp)
+	_, _ = w.Write(respBz)
+}
+
+func TestClient_Get(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			writeResponse(w, http.StatusUnauthorized, Response{ErrorMessage: "missing or invalid credentials"})
+			return
+		}
+		switch r.URL.Path {
+		case EndpointGetOperations:
+			writeResponse(w, http.StatusOK, Response{Result: map[string]*types.Operation{
+				"op": {ID: "op", DKGIdentifier: "dkg"},
+			}})
+		case EndpointGetOffset:
+			writeResponse(w, http.StatusOK, Response{Result: uint64(1) << 60})
+		case EndpointGetSignatureByID:
+			req.Equal("dkg", r.URL.Query().Get("dkgID"))
+			req.Equal("signing", r.URL.Query().Get("id"))
+			writeResponse(w, http.StatusOK, Response{Result: []types.ReconstructedSignature{{Username: "alice"}}})
+		default:
+			writeResponse(w, http.StatusOK, Response{ErrorMessage: "unknown endpoint"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.GetOperations(context.Background())
+	var apiErr *Error
+	req.True(errors.As(err, &apiErr))
+	req.Equal(http.StatusUnauthorized, apiErr.StatusCode)
+	req.Equal("missing or invalid credentials", apiErr.Message)
+
+	client.SetToken("secret")
+	operations, err := client.GetOperations(context.Background())
+	req.NoError(err)
+	req.Equal("dkg", operations["op"].DKGIdentifier)
+
+	offset, err := client.GetOffset(context.Background())
+	req.NoError(err)
+	req.Equal(uint64(1)<<60, offset)
+
+	signatures, err := client.GetSignatureByID(context.Background(), "dkg", "signing")
+	req.NoError(err)
+	req.Len(signatures, 1)
+	req.Equal("alice", signatures[0].Username)
+
+	_, err = client.GetUsername(context.Background())
+	req.True(errors.As(err, &apiErr))
+	req.Equal("unknown endpoint", apiErr.Message)
+}
+
+func TestClient_Post(t *testing.T) {
+	req := require.New(t)
+
+	var decision ParticipantDecisionRequest
+	var proposal []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req.Equal(http.MethodPost, r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		req.NoError(err)
+		switch r.URL.Path {
+		case EndpointStartDKG:
+			proposal = body
+		case EndpointAbortSigning:
+			req.NoError(json.Unmarshal(body, &decision))
+		default:
+			writeResponse(w, http.StatusBadRequest, Response{ErrorMessage: "Wrong HTTP method"})
+			return
+		}
+		writeResponse(w, http.StatusOK, Response{Result: "ok"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	dkgID, err := client.StartDKG(context.Background(), []byte(`{"threshold":2}`))
+	req.NoError(err)
+	req.Equal(`{"threshold":2}`, string(proposal))
+	req.Equal("da36d3a627ecd4ae4d20bfb503232dc5", dkgID)
+
+	err = client.AbortSigning(context.Background(), dkgID, "signing", "wrong data")
+	req.NoError(err)
+	req.Equal(dkgID, hex.EncodeToString(decision.DKGID))
+	req.Equal("signing", decision.SigningID)
+	req.Equal("wrong data", decision.Reason)
+
+	err = client.AbortSigning(context.Background(), "not hex", "signing", "")
+	req.Error(err)
+
+	var apiErr *Error
+	err = client.SaveOffset(context.Background(), 1)
+	req.True(errors.As(err, &apiErr))
+	req.Equal(http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func TestNewClient(t *testing.T) {
+	require.Equal(t, "http://localhost:8080", NewClient("localhost:8080").baseURL)
+	require.Equal(t, "https://localhost:8080", NewClient("https://localhost:8080/").baseURL)
+}
+
+func TestClient_WatchEvents(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req.Equal(EndpointWatchEvents, r.URL.Path)
+		req.Equal("dkg", r.URL.Query().Get("dkgID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, eventType := range []EventType{EventFSMStateChanged, EventMessageRejected, EventNewOperation} {
+			eventBz, err := json.Marshal(Event{Type: eventType, DKGRoundID: "dkg"})
+			req.NoError(err)
+			_, err = w.Write([]byte("event: " + string(eventType) + "\ndata: " + string(eventBz) + "\n\n"))
+			req.NoError(err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	var events []Event
+	stop := errors.New("stop")
+	err := client.WatchEvents(context.Background(), "dkg", func(event Event) error {
+		events = append(events, event)
+		if event.Type == EventMessageRejected {
+			return stop
+		}
+		return nil
+	})
+	req.Equal(stop, err)
+	req.Len(events, 2)
+	req.Equal(EventFSMStateChanged, events[0].Type)
+	req.Equal("dkg", events[1].DKGRoundID)
+}
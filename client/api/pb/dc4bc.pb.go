@@ -0,0 +1,2612 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: dc4bc.proto
+
+package pb
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type Event_Type int32
+
+const (
+	Event_UNKNOWN                 Event_Type = 0
+	Event_FSM_STATE_CHANGED       Event_Type = 1
+	Event_NEW_OPERATION           Event_Type = 2
+	Event_SIGNATURE_RECONSTRUCTED Event_Type = 3
+	Event_MESSAGE_REJECTED        Event_Type = 4
+	Event_SIGNING_REJECTED        Event_Type = 5
+	Event_DEADLINE_APPROACHING    Event_Type = 6
+	Event_LOG_FORK_DETECTED       Event_Type = 7
+	Event_EQUIVOCATION_DETECTED   Event_Type = 8
+	Event_LOG_UNVERIFIED          Event_Type = 9
+)
+
+// Enum value maps for Event_Type.
+var (
+	Event_Type_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "FSM_STATE_CHANGED",
+		2: "NEW_OPERATION",
+		3: "SIGNATURE_RECONSTRUCTED",
+		4: "MESSAGE_REJECTED",
+		5: "SIGNING_REJECTED",
+		6: "DEADLINE_APPROACHING",
+		7: "LOG_FORK_DETECTED",
+		8: "EQUIVOCATION_DETECTED",
+		9: "LOG_UNVERIFIED",
+	}
+	Event_Type_value = map[string]int32{
+		"UNKNOWN":                 0,
+		"FSM_STATE_CHANGED":       1,
+		"NEW_OPERATION":           2,
+		"SIGNATURE_RECONSTRUCTED": 3,
+		"MESSAGE_REJECTED":        4,
+		"SIGNING_REJECTED":        5,
+		"DEADLINE_APPROACHING":    6,
+		"LOG_FORK_DETECTED":       7,
+		"EQUIVOCATION_DETECTED":   8,
+		"LOG_UNVERIFIED":          9,
+	}
+)
+
+func (x Event_Type) Enum() *Event_Type {
+	p := new(Event_Type)
+	*p = x
+	return p
+}
+
+func (x Event_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Event_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_dc4bc_proto_enumTypes[0].Descriptor()
+}
+
+func (Event_Type) Type() protoreflect.EnumType {
+	return &file_dc4bc_proto_enumTypes[0]
+}
+
+func (x Event_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Event_Type.Descriptor instead.
+func (Event_Type) EnumDescriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{21, 0}
+}
+
+type Empty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{0}
+}
+
+type GetUsernameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+}
+
+func (x *GetUsernameResponse) Reset() {
+	*x = GetUsernameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUsernameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsernameResponse) ProtoMessage() {}
+
+func (x *GetUsernameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsernameResponse.ProtoReflect.Descriptor instead.
+func (*GetUsernameResponse) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetUsernameResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type GetPubKeyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+}
+
+func (x *GetPubKeyResponse) Reset() {
+	*x = GetPubKeyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPubKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPubKeyResponse) ProtoMessage() {}
+
+func (x *GetPubKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPubKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetPubKeyResponse) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetPubKeyResponse) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+type Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DkgRoundId string `protobuf:"bytes,2,opt,name=dkg_round_id,json=dkgRoundId,proto3" json:"dkg_round_id,omitempty"`
+	Offset     uint64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Event      string `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`
+	Data       []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	Signature  []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	Sender     string `protobuf:"bytes,7,opt,name=sender,proto3" json:"sender,omitempty"`
+	Recipient  string `protobuf:"bytes,8,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	// prev_offset and prev_hash commit the message to the prefix of the append-only log the sender has seen
+	PrevOffset uint64 `protobuf:"varint,9,opt,name=prev_offset,json=prevOffset,proto3" json:"prev_offset,omitempty"`
+	PrevHash   []byte `protobuf:"bytes,10,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Message) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Message) GetDkgRoundId() string {
+	if x != nil {
+		return x.DkgRoundId
+	}
+	return ""
+}
+
+func (x *Message) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *Message) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *Message) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Message) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Message) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *Message) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Message) GetPrevOffset() uint64 {
+	if x != nil {
+		return x.PrevOffset
+	}
+	return 0
+}
+
+func (x *Message) GetPrevHash() []byte {
+	if x != nil {
+		return x.PrevHash
+	}
+	return nil
+}
+
+type Operation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type       string     `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Payload    []byte     `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	ResultMsgs []*Message `protobuf:"bytes,4,rep,name=result_msgs,json=resultMsgs,proto3" json:"result_msgs,omitempty"`
+	// created_at is a Unix time in nanoseconds
+	CreatedAt     int64  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DkgIdentifier string `protobuf:"bytes,6,opt,name=dkg_identifier,json=dkgIdentifier,proto3" json:"dkg_identifier,omitempty"`
+	To            string `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	Event         string `protobuf:"bytes,8,opt,name=event,proto3" json:"event,omitempty"`
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Operation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Operation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Operation) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Operation) GetResultMsgs() []*Message {
+	if x != nil {
+		return x.ResultMsgs
+	}
+	return nil
+}
+
+func (x *Operation) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Operation) GetDkgIdentifier() string {
+	if x != nil {
+		return x.DkgIdentifier
+	}
+	return ""
+}
+
+func (x *Operation) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Operation) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+type GetOperationsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Operations []*Operation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+}
+
+func (x *GetOperationsResponse) Reset() {
+	*x = GetOperationsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOperationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationsResponse) ProtoMessage() {}
+
+func (x *GetOperationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationsResponse.ProtoReflect.Descriptor instead.
+func (*GetOperationsResponse) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetOperationsResponse) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type GetOperationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OperationId string `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+}
+
+func (x *GetOperationRequest) Reset() {
+	*x = GetOperationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationRequest) ProtoMessage() {}
+
+func (x *GetOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetOperationRequest) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetOperationRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type StartDKGRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// proposal is a JSON-encoded SignatureProposalParticipantsListRequest
+	Proposal []byte `protobuf:"bytes,1,opt,name=proposal,proto3" json:"proposal,omitempty"`
+}
+
+func (x *StartDKGRequest) Reset() {
+	*x = StartDKGRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartDKGRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartDKGRequest) ProtoMessage() {}
+
+func (x *StartDKGRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartDKGRequest.ProtoReflect.Descriptor instead.
+func (*StartDKGRequest) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StartDKGRequest) GetProposal() []byte {
+	if x != nil {
+		return x.Proposal
+	}
+	return nil
+}
+
+type StartDKGResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DkgId string `protobuf:"bytes,1,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+}
+
+func (x *StartDKGResponse) Reset() {
+	*x = StartDKGResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartDKGResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartDKGResponse) ProtoMessage() {}
+
+func (x *StartDKGResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartDKGResponse.ProtoReflect.Descriptor instead.
+func (*StartDKGResponse) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartDKGResponse) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+type ProposeSignRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dkg_id is a hex-encoded DKG round identifier
+	DkgId string `protobuf:"bytes,1,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+	Data  []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// signing_object is an optional JSON-encoded eth2.SigningObject which data is the signing root of
+	SigningObject []byte `protobuf:"bytes,3,opt,name=signing_object,json=signingObject,proto3" json:"signing_object,omitempty"`
+}
+
+func (x *ProposeSignRequest) Reset() {
+	*x = ProposeSignRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProposeSignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposeSignRequest) ProtoMessage() {}
+
+func (x *ProposeSignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposeSignRequest.ProtoReflect.Descriptor instead.
+func (*ProposeSignRequest) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ProposeSignRequest) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+func (x *ProposeSignRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ProposeSignRequest) GetSigningObject() []byte {
+	if x != nil {
+		return x.SigningObject
+	}
+	return nil
+}
+
+type ProposeSignResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SigningId string `protobuf:"bytes,1,opt,name=signing_id,json=signingId,proto3" json:"signing_id,omitempty"`
+}
+
+func (x *ProposeSignResponse) Reset() {
+	*x = ProposeSignResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProposeSignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposeSignResponse) ProtoMessage() {}
+
+func (x *ProposeSignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposeSignResponse.ProtoReflect.Descriptor instead.
+func (*ProposeSignResponse) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProposeSignResponse) GetSigningId() string {
+	if x != nil {
+		return x.SigningId
+	}
+	return ""
+}
+
+type Signature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SigningId  string `protobuf:"bytes,1,opt,name=signing_id,json=signingId,proto3" json:"signing_id,omitempty"`
+	SrcPayload []byte `protobuf:"bytes,2,opt,name=src_payload,json=srcPayload,proto3" json:"src_payload,omitempty"`
+	Signature  []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Username   string `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	DkgRoundId string `protobuf:"bytes,5,opt,name=dkg_round_id,json=dkgRoundId,proto3" json:"dkg_round_id,omitempty"`
+}
+
+func (x *Signature) Reset() {
+	*x = Signature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Signature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signature) ProtoMessage() {}
+
+func (x *Signature) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Signature.ProtoReflect.Descriptor instead.
+func (*Signature) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Signature) GetSigningId() string {
+	if x != nil {
+		return x.SigningId
+	}
+	return ""
+}
+
+func (x *Signature) GetSrcPayload() []byte {
+	if x != nil {
+		return x.SrcPayload
+	}
+	return nil
+}
+
+func (x *Signature) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Signature) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Signature) GetDkgRoundId() string {
+	if x != nil {
+		return x.DkgRoundId
+	}
+	return ""
+}
+
+type GetSignaturesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DkgId string `protobuf:"bytes,1,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+}
+
+func (x *GetSignaturesRequest) Reset() {
+	*x = GetSignaturesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSignaturesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignaturesRequest) ProtoMessage() {}
+
+func (x *GetSignaturesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignaturesRequest.ProtoReflect.Descriptor instead.
+func (*GetSignaturesRequest) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSignaturesRequest) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+type GetSignatureByIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DkgId     string `protobuf:"bytes,1,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+	SigningId string `protobuf:"bytes,2,opt,name=signing_id,json=signingId,proto3" json:"signing_id,omitempty"`
+}
+
+func (x *GetSignatureByIDRequest) Reset() {
+	*x = GetSignatureByIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSignatureByIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignatureByIDRequest) ProtoMessage() {}
+
+func (x *GetSignatureByIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignatureByIDRequest.ProtoReflect.Descriptor instead.
+func (*GetSignatureByIDRequest) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetSignatureByIDRequest) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+func (x *GetSignatureByIDRequest) GetSigningId() string {
+	if x != nil {
+		return x.SigningId
+	}
+	return ""
+}
+
+type GetSignaturesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signatures []*Signature `protobuf:"bytes,1,rep,name=signatures,proto3" json:"signatures,omitempty"`
+}
+
+func (x *GetSignaturesResponse) Reset() {
+	*x = GetSignaturesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSignaturesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignaturesResponse) ProtoMessage() {}
+
+func (x *GetSignaturesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignaturesResponse.ProtoReflect.Descriptor instead.
+func (*GetSignaturesResponse) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetSignaturesResponse) GetSignatures() []*Signature {
+	if x != nil {
+		return x.Signatures
+	}
+	return nil
+}
+
+type SigningStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SigningId string `protobuf:"bytes,1,opt,name=signing_id,json=signingId,proto3" json:"signing_id,omitempty"`
+	State     string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *SigningStatus) Reset() {
+	*x = SigningStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SigningStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SigningStatus) ProtoMessage() {}
+
+func (x *SigningStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SigningStatus.ProtoReflect.Descriptor instead.
+func (*SigningStatus) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SigningStatus) GetSigningId() string {
+	if x != nil {
+		return x.SigningId
+	}
+	return ""
+}
+
+func (x *SigningStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type FSMStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DkgId    string           `protobuf:"bytes,1,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+	State    string           `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Signings []*SigningStatus `protobuf:"bytes,3,rep,name=signings,proto3" json:"signings,omitempty"`
+	// dump is a JSON-encoded FSM dump with participants and their statuses
+	Dump []byte `protobuf:"bytes,4,opt,name=dump,proto3" json:"dump,omitempty"`
+}
+
+func (x *FSMStatus) Reset() {
+	*x = FSMStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FSMStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FSMStatus) ProtoMessage() {}
+
+func (x *FSMStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FSMStatus.ProtoReflect.Descriptor instead.
+func (*FSMStatus) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FSMStatus) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+func (x *FSMStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *FSMStatus) GetSignings() []*SigningStatus {
+	if x != nil {
+		return x.Signings
+	}
+	return nil
+}
+
+func (x *FSMStatus) GetDump() []byte {
+	if x != nil {
+		return x.Dump
+	}
+	return nil
+}
+
+type GetFSMStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DkgId string `protobuf:"bytes,1,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+}
+
+func (x *GetFSMStatusRequest) Reset() {
+	*x = GetFSMStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFSMStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFSMStatusRequest) ProtoMessage() {}
+
+func (x *GetFSMStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFSMStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetFSMStatusRequest) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetFSMStatusRequest) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+type GetFSMListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// states maps a DKG round identifier to the state of its FSM
+	States map[string]string `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *GetFSMListResponse) Reset() {
+	*x = GetFSMListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFSMListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFSMListResponse) ProtoMessage() {}
+
+func (x *GetFSMListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFSMListResponse.ProtoReflect.Descriptor instead.
+func (*GetFSMListResponse) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetFSMListResponse) GetStates() map[string]string {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+type Offset struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Offset uint64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (x *Offset) Reset() {
+	*x = Offset{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Offset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Offset) ProtoMessage() {}
+
+func (x *Offset) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Offset.ProtoReflect.Descriptor instead.
+func (*Offset) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Offset) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// dkg_id filters events of one DKG round, empty for all rounds
+	DkgId string `protobuf:"bytes,1,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchEventsRequest) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type  Event_Type `protobuf:"varint,1,opt,name=type,proto3,enum=dc4bc.Event_Type" json:"type,omitempty"`
+	DkgId string     `protobuf:"bytes,2,opt,name=dkg_id,json=dkgId,proto3" json:"dkg_id,omitempty"`
+	// state is the new state of the DKG round FSM or of the signing session
+	State     string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	SigningId string `protobuf:"bytes,4,opt,name=signing_id,json=signingId,proto3" json:"signing_id,omitempty"`
+	// operation is set for NEW_OPERATION events
+	Operation *Operation `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	// time is a Unix time in nanoseconds
+	Time int64 `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+	// message_event, message_offset and sender describe the message from the append-only log which caused the event
+	MessageEvent  string `protobuf:"bytes,7,opt,name=message_event,json=messageEvent,proto3" json:"message_event,omitempty"`
+	MessageOffset uint64 `protobuf:"varint,8,opt,name=message_offset,json=messageOffset,proto3" json:"message_offset,omitempty"`
+	Sender        string `protobuf:"bytes,9,opt,name=sender,proto3" json:"sender,omitempty"`
+	// signature is set for SIGNATURE_RECONSTRUCTED events and for rejected signatures
+	Signature *Signature `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"`
+	// error is a reason of the message or the signing rejection, or a description of the fork or the equivocation
+	Error string `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dc4bc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_dc4bc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_dc4bc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Event) GetType() Event_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Event_UNKNOWN
+}
+
+func (x *Event) GetDkgId() string {
+	if x != nil {
+		return x.DkgId
+	}
+	return ""
+}
+
+func (x *Event) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Event) GetSigningId() string {
+	if x != nil {
+		return x.SigningId
+	}
+	return ""
+}
+
+func (x *Event) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *Event) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Event) GetMessageEvent() string {
+	if x != nil {
+		return x.MessageEvent
+	}
+	return ""
+}
+
+func (x *Event) GetMessageOffset() uint64 {
+	if x != nil {
+		return x.MessageOffset
+	}
+	return 0
+}
+
+func (x *Event) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *Event) GetSignature() *Signature {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Event) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_dc4bc_proto protoreflect.FileDescriptor
+
+var file_dc4bc_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x64,
+	0x63, 0x34, 0x62, 0x63, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x31, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x2c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0x8f,
+	0x02, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x64, 0x6b,
+	0x67, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x64, 0x6b, 0x67, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x76, 0x4f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76, 0x48, 0x61, 0x73, 0x68,
+	0x22, 0xe6, 0x01, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2f, 0x0a, 0x0b,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x6d, 0x73, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x73, 0x67, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x25, 0x0a, 0x0e,
+	0x64, 0x6b, 0x67, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x64, 0x6b, 0x67, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x74, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x49, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x30, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x38, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x2d,
+	0x0a, 0x0f, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x4b, 0x47, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x22, 0x29, 0x0a,
+	0x10, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x4b, 0x47, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x6b, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x64, 0x6b, 0x67, 0x49, 0x64, 0x22, 0x66, 0x0a, 0x12, 0x50, 0x72, 0x6f, 0x70,
+	0x6f, 0x73, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15,
+	0x0a, 0x06, 0x64, 0x6b, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x64, 0x6b, 0x67, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x22, 0x34, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x69,
+	0x6e, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x49, 0x64, 0x22, 0xa7, 0x01, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e,
+	0x67, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x72, 0x63, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x72, 0x63, 0x50, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20,
+	0x0a, 0x0c, 0x64, 0x6b, 0x67, 0x5f, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6b, 0x67, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x49, 0x64,
+	0x22, 0x2d, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x6b, 0x67, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x64, 0x6b, 0x67, 0x49, 0x64, 0x22,
+	0x4f, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42,
+	0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x6b,
+	0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x64, 0x6b, 0x67, 0x49,
+	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x49, 0x64,
+	0x22, 0x49, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x0a, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52,
+	0x0a, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x44, 0x0a, 0x0d, 0x53,
+	0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x22, 0x7e, 0x0a, 0x09, 0x46, 0x53, 0x4d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x15,
+	0x0a, 0x06, 0x64, 0x6b, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x64, 0x6b, 0x67, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x73,
+	0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x75, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x75, 0x6d,
+	0x70, 0x22, 0x2c, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x46, 0x53, 0x4d, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x6b, 0x67, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x64, 0x6b, 0x67, 0x49, 0x64, 0x22,
+	0x8e, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x46, 0x53, 0x4d, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x46, 0x53, 0x4d, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x20, 0x0a, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x22, 0x2b, 0x0a, 0x12, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x6b, 0x67, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x64, 0x6b, 0x67, 0x49, 0x64, 0x22,
+	0xd1, 0x04, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x15, 0x0a, 0x06, 0x64, 0x6b, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x64, 0x6b, 0x67, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x09,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e,
+	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xe6, 0x01, 0x0a, 0x04, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
+	0x12, 0x15, 0x0a, 0x11, 0x46, 0x53, 0x4d, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x48,
+	0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x45, 0x57, 0x5f, 0x4f,
+	0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x49,
+	0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x52, 0x45, 0x43, 0x4f, 0x4e, 0x53, 0x54, 0x52,
+	0x55, 0x43, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x53, 0x53, 0x41,
+	0x47, 0x45, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x14, 0x0a,
+	0x10, 0x53, 0x49, 0x47, 0x4e, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45,
+	0x44, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x44, 0x45, 0x41, 0x44, 0x4c, 0x49, 0x4e, 0x45, 0x5f,
+	0x41, 0x50, 0x50, 0x52, 0x4f, 0x41, 0x43, 0x48, 0x49, 0x4e, 0x47, 0x10, 0x06, 0x12, 0x15, 0x0a,
+	0x11, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4b, 0x5f, 0x44, 0x45, 0x54, 0x45, 0x43, 0x54,
+	0x45, 0x44, 0x10, 0x07, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x51, 0x55, 0x49, 0x56, 0x4f, 0x43, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x54, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x08, 0x12,
+	0x12, 0x0a, 0x0e, 0x4c, 0x4f, 0x47, 0x5f, 0x55, 0x4e, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x09, 0x32, 0xd1, 0x06, 0x0a, 0x05, 0x44, 0x43, 0x34, 0x42, 0x43, 0x12, 0x37, 0x0a,
+	0x0b, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0c, 0x2e, 0x64,
+	0x63, 0x34, 0x62, 0x63, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x64, 0x63, 0x34,
+	0x62, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x50, 0x75, 0x62,
+	0x4b, 0x65, 0x79, 0x12, 0x0c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x18, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x75, 0x62,
+	0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0d, 0x47,
+	0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x0c, 0x2e, 0x64,
+	0x63, 0x34, 0x62, 0x63, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1c, 0x2e, 0x64, 0x63, 0x34,
+	0x62, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63,
+	0x2e, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x18, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x10, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x3b, 0x0a, 0x08, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x4b, 0x47, 0x12, 0x16,
+	0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x4b, 0x47, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x44, 0x4b, 0x47, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x44, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x19,
+	0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x53, 0x69,
+	0x67, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x63, 0x34, 0x62,
+	0x63, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x50, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x42, 0x79, 0x49, 0x44, 0x12, 0x1e, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x46, 0x53, 0x4d, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x1a, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x46,
+	0x53, 0x4d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x10, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x46, 0x53, 0x4d, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x35, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x46, 0x53, 0x4d, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x0c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x19, 0x2e,
+	0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x53, 0x4d, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x4f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x0c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x0d, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x4f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x12, 0x29, 0x0a, 0x0a, 0x53, 0x61, 0x76, 0x65, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x0d, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x1a,
+	0x0c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x38, 0x0a,
+	0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x19, 0x2e, 0x64,
+	0x63, 0x34, 0x62, 0x63, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x64, 0x6f, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x2f, 0x64, 0x63, 0x34, 0x62, 0x63, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_dc4bc_proto_rawDescOnce sync.Once
+	file_dc4bc_proto_rawDescData = file_dc4bc_proto_rawDesc
+)
+
+func file_dc4bc_proto_rawDescGZIP() []byte {
+	file_dc4bc_proto_rawDescOnce.Do(func() {
+		file_dc4bc_proto_rawDescData = protoimpl.X.CompressGZIP(file_dc4bc_proto_rawDescData)
+	})
+	return file_dc4bc_proto_rawDescData
+}
+
+var file_dc4bc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_dc4bc_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_dc4bc_proto_goTypes = []interface{}{
+	(Event_Type)(0),                 // 0: dc4bc.Event.Type
+	(*Empty)(nil),                   // 1: dc4bc.Empty
+	(*GetUsernameResponse)(nil),     // 2: dc4bc.GetUsernameResponse
+	(*GetPubKeyResponse)(nil),       // 3: dc4bc.GetPubKeyResponse
+	(*Message)(nil),                 // 4: dc4bc.Message
+	(*Operation)(nil),               // 5: dc4bc.Operation
+	(*GetOperationsResponse)(nil),   // 6: dc4bc.GetOperationsResponse
+	(*GetOperationRequest)(nil),     // 7: dc4bc.GetOperationRequest
+	(*StartDKGRequest)(nil),         // 8: dc4bc.StartDKGRequest
+	(*StartDKGResponse)(nil),        // 9: dc4bc.StartDKGResponse
+	(*ProposeSignRequest)(nil),      // 10: dc4bc.ProposeSignRequest
+	(*ProposeSignResponse)(nil),     // 11: dc4bc.ProposeSignResponse
+	(*Signature)(nil),               // 12: dc4bc.Signature
+	(*GetSignaturesRequest)(nil),    // 13: dc4bc.GetSignaturesRequest
+	(*GetSignatureByIDRequest)(nil), // 14: dc4bc.GetSignatureByIDRequest
+	(*GetSignaturesResponse)(nil),   // 15: dc4bc.GetSignaturesResponse
+	(*SigningStatus)(nil),           // 16: dc4bc.SigningStatus
+	(*FSMStatus)(nil),               // 17: dc4bc.FSMStatus
+	(*GetFSMStatusRequest)(nil),     // 18: dc4bc.GetFSMStatusRequest
+	(*GetFSMListResponse)(nil),      // 19: dc4bc.GetFSMListResponse
+	(*Offset)(nil),                  // 20: dc4bc.Offset
+	(*WatchEventsRequest)(nil),      // 21: dc4bc.WatchEventsRequest
+	(*Event)(nil),                   // 22: dc4bc.Event
+	nil,                             // 23: dc4bc.GetFSMListResponse.StatesEntry
+}
+var file_dc4bc_proto_depIdxs = []int32{
+	4,  // 0: dc4bc.Operation.result_msgs:type_name -> dc4bc.Message
+	5,  // 1: dc4bc.GetOperationsResponse.operations:type_name -> dc4bc.Operation
+	12, // 2: dc4bc.GetSignaturesResponse.signatures:type_name -> dc4bc.Signature
+	16, // 3: dc4bc.FSMStatus.signings:type_name -> dc4bc.SigningStatus
+	23, // 4: dc4bc.GetFSMListResponse.states:type_name -> dc4bc.GetFSMListResponse.StatesEntry
+	0,  // 5: dc4bc.Event.type:type_name -> dc4bc.Event.Type
+	5,  // 6: dc4bc.Event.operation:type_name -> dc4bc.Operation
+	12, // 7: dc4bc.Event.signature:type_name -> dc4bc.Signature
+	1,  // 8: dc4bc.DC4BC.GetUsername:input_type -> dc4bc.Empty
+	1,  // 9: dc4bc.DC4BC.GetPubKey:input_type -> dc4bc.Empty
+	1,  // 10: dc4bc.DC4BC.GetOperations:input_type -> dc4bc.Empty
+	7,  // 11: dc4bc.DC4BC.GetOperation:input_type -> dc4bc.GetOperationRequest
+	5,  // 12: dc4bc.DC4BC.HandleProcessedOperation:input_type -> dc4bc.Operation
+	8,  // 13: dc4bc.DC4BC.StartDKG:input_type -> dc4bc.StartDKGRequest
+	10, // 14: dc4bc.DC4BC.ProposeSign:input_type -> dc4bc.ProposeSignRequest
+	13, // 15: dc4bc.DC4BC.GetSignatures:input_type -> dc4bc.GetSignaturesRequest
+	14, // 16: dc4bc.DC4BC.GetSignatureByID:input_type -> dc4bc.GetSignatureByIDRequest
+	18, // 17: dc4bc.DC4BC.GetFSMStatus:input_type -> dc4bc.GetFSMStatusRequest
+	1,  // 18: dc4bc.DC4BC.GetFSMList:input_type -> dc4bc.Empty
+	1,  // 19: dc4bc.DC4BC.GetOffset:input_type -> dc4bc.Empty
+	20, // 20: dc4bc.DC4BC.SaveOffset:input_type -> dc4bc.Offset
+	21, // 21: dc4bc.DC4BC.WatchEvents:input_type -> dc4bc.WatchEventsRequest
+	2,  // 22: dc4bc.DC4BC.GetUsername:output_type -> dc4bc.GetUsernameResponse
+	3,  // 23: dc4bc.DC4BC.GetPubKey:output_type -> dc4bc.GetPubKeyResponse
+	6,  // 24: dc4bc.DC4BC.GetOperations:output_type -> dc4bc.GetOperationsResponse
+	5,  // 25: dc4bc.DC4BC.GetOperation:output_type -> dc4bc.Operation
+	1,  // 26: dc4bc.DC4BC.HandleProcessedOperation:output_type -> dc4bc.Empty
+	9,  // 27: dc4bc.DC4BC.StartDKG:output_type -> dc4bc.StartDKGResponse
+	11, // 28: dc4bc.DC4BC.ProposeSign:output_type -> dc4bc.ProposeSignResponse
+	15, // 29: dc4bc.DC4BC.GetSignatures:output_type -> dc4bc.GetSignaturesResponse
+	15, // 30: dc4bc.DC4BC.GetSignatureByID:output_type -> dc4bc.GetSignaturesResponse
+	17, // 31: dc4bc.DC4BC.GetFSMStatus:output_type -> dc4bc.FSMStatus
+	19, // 32: dc4bc.DC4BC.GetFSMList:output_type -> dc4bc.GetFSMListResponse
+	20, // 33: dc4bc.DC4BC.GetOffset:output_type -> dc4bc.Offset
+	1,  // 34: dc4bc.DC4BC.SaveOffset:output_type -> dc4bc.Empty
+	22, // 35: dc4bc.DC4BC.WatchEvents:output_type -> dc4bc.Event
+	22, // [22:36] is the sub-list for method output_type
+	8,  // [8:22] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_dc4bc_proto_init() }
+func file_dc4bc_proto_init() {
+	if File_dc4bc_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_dc4bc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUsernameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPubKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Operation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOperationsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOperationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartDKGRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartDKGResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProposeSignRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProposeSignResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Signature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSignaturesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSignatureByIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSignaturesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SigningStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FSMStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFSMStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFSMListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Offset); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dc4bc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_dc4bc_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_dc4bc_proto_goTypes,
+		DependencyIndexes: file_dc4bc_proto_depIdxs,
+		EnumInfos:         file_dc4bc_proto_enumTypes,
+		MessageInfos:      file_dc4bc_proto_msgTypes,
+	}.Build()
+	File_dc4bc_proto = out.File
+	file_dc4bc_proto_rawDesc = nil
+	file_dc4bc_proto_goTypes = nil
+	file_dc4bc_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// DC4BCClient is the client API for DC4BC service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DC4BCClient interface {
+	GetUsername(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetUsernameResponse, error)
+	GetPubKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetPubKeyResponse, error)
+	// GetOperations returns all operations that should be processed on the airgapped machine
+	GetOperations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetOperationsResponse, error)
+	GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*Operation, error)
+	// HandleProcessedOperation accepts the operation processed by the airgapped machine
+	HandleProcessedOperation(ctx context.Context, in *Operation, opts ...grpc.CallOption) (*Empty, error)
+	// StartDKG sends the DKG proposal, the proposal has the same JSON format as for the HTTP API
+	StartDKG(ctx context.Context, in *StartDKGRequest, opts ...grpc.CallOption) (*StartDKGResponse, error)
+	// ProposeSign returns the ID of the proposed signing
+	ProposeSign(ctx context.Context, in *ProposeSignRequest, opts ...grpc.CallOption) (*ProposeSignResponse, error)
+	GetSignatures(ctx context.Context, in *GetSignaturesRequest, opts ...grpc.CallOption) (*GetSignaturesResponse, error)
+	GetSignatureByID(ctx context.Context, in *GetSignatureByIDRequest, opts ...grpc.CallOption) (*GetSignaturesResponse, error)
+	GetFSMStatus(ctx context.Context, in *GetFSMStatusRequest, opts ...grpc.CallOption) (*FSMStatus, error)
+	GetFSMList(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetFSMListResponse, error)
+	GetOffset(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Offset, error)
+	SaveOffset(ctx context.Context, in *Offset, opts ...grpc.CallOption) (*Empty, error)
+	// WatchEvents streams FSM state changes, new operations, signatures and rejected messages as they happen
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (DC4BC_WatchEventsClient, error)
+}
+
+type dC4BCClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDC4BCClient(cc grpc.ClientConnInterface) DC4BCClient {
+	return &dC4BCClient{cc}
+}
+
+func (c *dC4BCClient) GetUsername(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetUsernameResponse, error) {
+	out := new(GetUsernameResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetUsername", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetPubKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetPubKeyResponse, error) {
+	out := new(GetPubKeyResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetPubKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetOperations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetOperationsResponse, error) {
+	out := new(GetOperationsResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) HandleProcessedOperation(ctx context.Context, in *Operation, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/HandleProcessedOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) StartDKG(ctx context.Context, in *StartDKGRequest, opts ...grpc.CallOption) (*StartDKGResponse, error) {
+	out := new(StartDKGResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/StartDKG", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) ProposeSign(ctx context.Context, in *ProposeSignRequest, opts ...grpc.CallOption) (*ProposeSignResponse, error) {
+	out := new(ProposeSignResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/ProposeSign", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetSignatures(ctx context.Context, in *GetSignaturesRequest, opts ...grpc.CallOption) (*GetSignaturesResponse, error) {
+	out := new(GetSignaturesResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetSignatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetSignatureByID(ctx context.Context, in *GetSignatureByIDRequest, opts ...grpc.CallOption) (*GetSignaturesResponse, error) {
+	out := new(GetSignaturesResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetSignatureByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetFSMStatus(ctx context.Context, in *GetFSMStatusRequest, opts ...grpc.CallOption) (*FSMStatus, error) {
+	out := new(FSMStatus)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetFSMStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetFSMList(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetFSMListResponse, error) {
+	out := new(GetFSMListResponse)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetFSMList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) GetOffset(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Offset, error) {
+	out := new(Offset)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/GetOffset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) SaveOffset(ctx context.Context, in *Offset, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/dc4bc.DC4BC/SaveOffset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dC4BCClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (DC4BC_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DC4BC_serviceDesc.Streams[0], "/dc4bc.DC4BC/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dC4BCWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DC4BC_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type dC4BCWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *dC4BCWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DC4BCServer is the server API for DC4BC service.
+type DC4BCServer interface {
+	GetUsername(context.Context, *Empty) (*GetUsernameResponse, error)
+	GetPubKey(context.Context, *Empty) (*GetPubKeyResponse, error)
+	// GetOperations returns all operations that should be processed on the airgapped machine
+	GetOperations(context.Context, *Empty) (*GetOperationsResponse, error)
+	GetOperation(context.Context, *GetOperationRequest) (*Operation, error)
+	// HandleProcessedOperation accepts the operation processed by the airgapped machine
+	HandleProcessedOperation(context.Context, *Operation) (*Empty, error)
+	// StartDKG sends the DKG proposal, the proposal has the same JSON format as for the HTTP API
+	StartDKG(context.Context, *StartDKGRequest) (*StartDKGResponse, error)
+	// ProposeSign returns the ID of the proposed signing
+	ProposeSign(context.Context, *ProposeSignRequest) (*ProposeSignResponse, error)
+	GetSignatures(context.Context, *GetSignaturesRequest) (*GetSignaturesResponse, error)
+	GetSignatureByID(context.Context, *GetSignatureByIDRequest) (*GetSignaturesResponse, error)
+	GetFSMStatus(context.Context, *GetFSMStatusRequest) (*FSMStatus, error)
+	GetFSMList(context.Context, *Empty) (*GetFSMListResponse, error)
+	GetOffset(context.Context, *Empty) (*Offset, error)
+	SaveOffset(context.Context, *Offset) (*Empty, error)
+	// WatchEvents streams FSM state changes, new operations, signatures and rejected messages as they happen
+	WatchEvents(*WatchEventsRequest, DC4BC_WatchEventsServer) error
+}
+
+// UnimplementedDC4BCServer can be embedded to have forward compatible implementations.
+type UnimplementedDC4BCServer struct {
+}
+
+func (*UnimplementedDC4BCServer) GetUsername(context.Context, *Empty) (*GetUsernameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsername not implemented")
+}
+func (*UnimplementedDC4BCServer) GetPubKey(context.Context, *Empty) (*GetPubKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPubKey not implemented")
+}
+func (*UnimplementedDC4BCServer) GetOperations(context.Context, *Empty) (*GetOperationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperations not implemented")
+}
+func (*UnimplementedDC4BCServer) GetOperation(context.Context, *GetOperationRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperation not implemented")
+}
+func (*UnimplementedDC4BCServer) HandleProcessedOperation(context.Context, *Operation) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleProcessedOperation not implemented")
+}
+func (*UnimplementedDC4BCServer) StartDKG(context.Context, *StartDKGRequest) (*StartDKGResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartDKG not implemented")
+}
+func (*UnimplementedDC4BCServer) ProposeSign(context.Context, *ProposeSignRequest) (*ProposeSignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProposeSign not implemented")
+}
+func (*UnimplementedDC4BCServer) GetSignatures(context.Context, *GetSignaturesRequest) (*GetSignaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSignatures not implemented")
+}
+func (*UnimplementedDC4BCServer) GetSignatureByID(context.Context, *GetSignatureByIDRequest) (*GetSignaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSignatureByID not implemented")
+}
+func (*UnimplementedDC4BCServer) GetFSMStatus(context.Context, *GetFSMStatusRequest) (*FSMStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFSMStatus not implemented")
+}
+func (*UnimplementedDC4BCServer) GetFSMList(context.Context, *Empty) (*GetFSMListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFSMList not implemented")
+}
+func (*UnimplementedDC4BCServer) GetOffset(context.Context, *Empty) (*Offset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOffset not implemented")
+}
+func (*UnimplementedDC4BCServer) SaveOffset(context.Context, *Offset) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveOffset not implemented")
+}
+func (*UnimplementedDC4BCServer) WatchEvents(*WatchEventsRequest, DC4BC_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+
+func RegisterDC4BCServer(s *grpc.Server, srv DC4BCServer) {
+	s.RegisterService(&_DC4BC_serviceDesc, srv)
+}
+
+func _DC4BC_GetUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetUsername(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetUsername",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetUsername(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetPubKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetPubKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetPubKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetPubKey(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetOperations(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetOperation(ctx, req.(*GetOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_HandleProcessedOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Operation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).HandleProcessedOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/HandleProcessedOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).HandleProcessedOperation(ctx, req.(*Operation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_StartDKG_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartDKGRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).StartDKG(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/StartDKG",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).StartDKG(ctx, req.(*StartDKGRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_ProposeSign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposeSignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).ProposeSign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/ProposeSign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).ProposeSign(ctx, req.(*ProposeSignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetSignatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSignaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetSignatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetSignatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetSignatures(ctx, req.(*GetSignaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetSignatureByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSignatureByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetSignatureByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetSignatureByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetSignatureByID(ctx, req.(*GetSignatureByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetFSMStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFSMStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetFSMStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetFSMStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetFSMStatus(ctx, req.(*GetFSMStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetFSMList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetFSMList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetFSMList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetFSMList(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_GetOffset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).GetOffset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/GetOffset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).GetOffset(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_SaveOffset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Offset)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DC4BCServer).SaveOffset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dc4bc.DC4BC/SaveOffset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DC4BCServer).SaveOffset(ctx, req.(*Offset))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DC4BC_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DC4BCServer).WatchEvents(m, &dC4BCWatchEventsServer{stream})
+}
+
+type DC4BC_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type dC4BCWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *dC4BCWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _DC4BC_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dc4bc.DC4BC",
+	HandlerType: (*DC4BCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUsername",
+			Handler:    _DC4BC_GetUsername_Handler,
+		},
+		{
+			MethodName: "GetPubKey",
+			Handler:    _DC4BC_GetPubKey_Handler,
+		},
+		{
+			MethodName: "GetOperations",
+			Handler:    _DC4BC_GetOperations_Handler,
+		},
+		{
+			MethodName: "GetOperation",
+			Handler:    _DC4BC_GetOperation_Handler,
+		},
+		{
+			MethodName: "HandleProcessedOperation",
+			Handler:    _DC4BC_HandleProcessedOperation_Handler,
+		},
+		{
+			MethodName: "StartDKG",
+			Handler:    _DC4BC_StartDKG_Handler,
+		},
+		{
+			MethodName: "ProposeSign",
+			Handler:    _DC4BC_ProposeSign_Handler,
+		},
+		{
+			MethodName: "GetSignatures",
+			Handler:    _DC4BC_GetSignatures_Handler,
+		},
+		{
+			MethodName: "GetSignatureByID",
+			Handler:    _DC4BC_GetSignatureByID_Handler,
+		},
+		{
+			MethodName: "GetFSMStatus",
+			Handler:    _DC4BC_GetFSMStatus_Handler,
+		},
+		{
+			MethodName: "GetFSMList",
+			Handler:    _DC4BC_GetFSMList_Handler,
+		},
+		{
+			MethodName: "GetOffset",
+			Handler:    _DC4BC_GetOffset_Handler,
+		},
+		{
+			MethodName: "SaveOffset",
+			Handler:    _DC4BC_SaveOffset_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _DC4BC_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dc4bc.proto",
+}
@@ -0,0 +1,211 @@
+// Package api contains the types of the dc4bc_d HTTP API and a typed client for it.
+// The same types are used by the server handlers, so the client and the server cannot drift apart.
+package api
+
+import (
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/types"
+)
+
+// HTTP API endpoints
+const (
+	EndpointGetUsername           = "/getUsername"
+	EndpointGetPubKey             = "/getPubKey"
+	EndpointSendMessage           = "/sendMessage"
+	EndpointGetOperations         = "/getOperations"
+	EndpointGetOperationQRPath    = "/getOperationQRPath"
+	EndpointGetSignatures         = "/getSignatures"
+	EndpointGetSignatureByID      = "/getSignatureByID"
+	EndpointGetRejectedSignatures = "/getRejectedSignatures"
+	EndpointGetOperationQR        = "/getOperationQR"
+	EndpointHandleProcessedOp     = "/handleProcessedOperationJSON"
+	EndpointGetOperation          = "/getOperation"
+	EndpointStartDKG              = "/startDKG"
+	EndpointProposeSignMessage    = "/proposeSignMessage"
+	EndpointDeclineDKG            = "/declineDKG"
+	EndpointAbortDKG              = "/abortDKG"
+	EndpointDeclineSigning        = "/declineSigning"
+	EndpointAbortSigning          = "/abortSigning"
+	EndpointSaveOffset            = "/saveOffset"
+	EndpointGetOffset             = "/getOffset"
+	EndpointGetFSMDump            = "/getFSMDump"
+	EndpointGetFSMList            = "/getFSMList"
+	EndpointGetSigningQueue       = "/getSigningQueue"
+	EndpointWatchEvents           = "/watchEvents"
+	EndpointGetRoundStatus        = "/getRoundStatus"
+	EndpointGetDeadLetters        = "/getDeadLetters"
+	EndpointRetryDeadLetter       = "/retryDeadLetter"
+	EndpointDiscardDeadLetter     = "/discardDeadLetter"
+	EndpointGetLogCheckpoints     = "/getLogCheckpoints"
+	EndpointPublishLogCheckpoint  = "/publishLogCheckpoint"
+	EndpointGetEquivocations      = "/getEquivocations"
+	EndpointExportTranscript      = "/exportTranscript"
+)
+
+// Response is an envelope of every JSON response of the HTTP API
+type Response struct {
+	ErrorMessage string      `json:"error_message,omitempty"`
+	Result       interface{} `json:"result"`
+}
+
+// ProposeSignRequest is a body of EndpointProposeSignMessage
+type ProposeSignRequest struct {
+	// DKGID is a raw (not hex-encoded) DKG round identifier
+	DKGID []byte `json:"dkgID"`
+	Data  []byte `json:"data"`
+	// SigningObject is an optional JSON encoded eth2.SigningObject which Data is the signing root of,
+	// signers check the root against the object
+	SigningObject []byte `json:"signingObject,omitempty"`
+}
+
+// ParticipantDecisionRequest is a body of EndpointDeclineDKG, EndpointAbortDKG, EndpointDeclineSigning
+// and EndpointAbortSigning. SigningID is ignored by the DKG endpoints
+type ParticipantDecisionRequest struct {
+	// DKGID is a raw (not hex-encoded) DKG round identifier
+	DKGID     []byte `json:"dkgID"`
+	SigningID string `json:"signingID,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// SaveOffsetRequest is a body of EndpointSaveOffset
+type SaveOffsetRequest struct {
+	Offset *uint64 `json:"offset"`
+}
+
+// DeadLetterRequest is a body of EndpointRetryDeadLetter and EndpointDiscardDeadLetter
+type DeadLetterRequest struct {
+	MessageID string `json:"messageID"`
+}
+
+// ParticipantProgress is a status of a participant in a phase of a DKG round or of a signing session
+type ParticipantProgress struct {
+	Username string `json:"username"`
+	// Status is a raw status of the participant from the FSM, e.g. CommitAwaitConfirmation
+	Status string `json:"status"`
+	// Acted is false while the phase waits for the participant
+	Acted bool `json:"acted"`
+	// Error is a decline reason or an error recorded for the participant
+	Error string `json:"error,omitempty"`
+}
+
+// PhaseStatus is a progress of participants in a phase of a DKG round or of a signing session
+type PhaseStatus struct {
+	Name     string    `json:"name"`
+	Deadline time.Time `json:"deadline"`
+	// TimeLeft is a time left until the deadline when the status was made, it's negative after the deadline
+	TimeLeft     time.Duration         `json:"time_left"`
+	Participants []ParticipantProgress `json:"participants"`
+}
+
+// Waiting returns usernames of the participants the phase waits for
+func (p PhaseStatus) Waiting() []string {
+	var usernames []string
+	for _, participant := range p.Participants {
+		if !participant.Acted {
+			usernames = append(usernames, participant.Username)
+		}
+	}
+	return usernames
+}
+
+// Acted returns a number of the participants who have acted in the phase
+func (p PhaseStatus) Acted() int {
+	return len(p.Participants) - len(p.Waiting())
+}
+
+// SigningStatus is a summary of a signing session
+type SigningStatus struct {
+	SigningID   string      `json:"signing_id"`
+	State       string      `json:"state"`
+	Data        []byte      `json:"data"`
+	CreatedAt   time.Time   `json:"created_at"`
+	Phase       PhaseStatus `json:"phase"`
+	AbortedBy   string      `json:"aborted_by,omitempty"`
+	AbortReason string      `json:"abort_reason,omitempty"`
+	// NextAction is what the local user must do next for the signing, empty if nothing
+	NextAction string `json:"next_action,omitempty"`
+}
+
+// RoundStatus is a summary of a DKG round and its signing sessions, EndpointGetRoundStatus returns them
+type RoundStatus struct {
+	DKGRoundID string    `json:"dkg_round_id"`
+	State      string    `json:"state"`
+	Threshold  int       `json:"threshold"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Phases are phases of the DKG round which have started, the last one is the current phase
+	Phases      []PhaseStatus   `json:"phases"`
+	AbortedBy   string          `json:"aborted_by,omitempty"`
+	AbortReason string          `json:"abort_reason,omitempty"`
+	Signings    []SigningStatus `json:"signings"`
+	// PendingOperations are IDs of the operations of the round waiting for the airgapped machine of the local user
+	PendingOperations []string `json:"pending_operations"`
+	// NextAction is what the local user must do next for the DKG round, empty if nothing
+	NextAction string `json:"next_action,omitempty"`
+}
+
+// LogCheckpointRequest is a body of EndpointPublishLogCheckpoint
+type LogCheckpointRequest struct {
+	// DKGID is a raw (not hex-encoded) DKG round identifier
+	DKGID []byte `json:"dkgID"`
+}
+
+// LogCheckpoints is a result of EndpointGetLogCheckpoints
+type LogCheckpoints struct {
+	// Offset and HeadHash are the head of the append-only log processed by the node
+	Offset   uint64 `json:"offset"`
+	HeadHash []byte `json:"head_hash"`
+	// Verified is false if the head hash is unknown, e.g. after the offset is moved by hand, new messages
+	// are not checked for forks then until the state is rebuilt
+	Verified    bool                  `json:"verified"`
+	Checkpoints []types.LogCheckpoint `json:"checkpoints"`
+}
+
+type EventType string
+
+const (
+	// EventFSMStateChanged is published when a message from the append-only log changes a state
+	// of a DKG round FSM or of a signing session
+	EventFSMStateChanged EventType = "fsm_state_changed"
+	// EventNewOperation is published when a new operation for the airgapped machine is added to the pool
+	EventNewOperation EventType = "new_operation"
+	// EventSignatureReconstructed is published when a verified reconstructed signature is received
+	EventSignatureReconstructed EventType = "signature_reconstructed"
+	// EventMessageRejected is published when a message from the append-only log fails to be processed
+	EventMessageRejected EventType = "message_rejected"
+	// EventSigningRejected is published when a queued signing proposal fails to start, it's removed from the queue
+	EventSigningRejected EventType = "signing_rejected"
+	// EventDeadlineApproaching is sent by webhooks when a deadline of a DKG round or of a signing session is close
+	EventDeadlineApproaching EventType = "deadline_approaching"
+	// EventLogForkDetected is published when a message shows that the append-only log was rewritten or forked.
+	// A message which commits to another log is put to the dead-letter list, the client stops polling only
+	// if the log skips messages or a processed message is rewritten
+	EventLogForkDetected EventType = "log_fork_detected"
+	// EventLogUnverified is published when a message commits to a log prefix whose head hash the client doesn't
+	// know, e.g. after the offset is moved by hand, so the message is processed without checking it for a fork
+	EventLogUnverified EventType = "log_unverified"
+	// EventEquivocationDetected is published when a participant is found to have signed different messages
+	// for the same step of a DKG round, or when a participant broadcasts such an evidence
+	EventEquivocationDetected EventType = "equivocation_detected"
+)
+
+// Event is a notification about a change of the node state, EndpointWatchEvents streams them
+// as Server-Sent Events with JSON-encoded data
+type Event struct {
+	Type       EventType `json:"type"`
+	DKGRoundID string    `json:"dkg_round_id"`
+	// State is a new state of the DKG round FSM or of the signing session
+	State     string `json:"state,omitempty"`
+	SigningID string `json:"signing_id,omitempty"`
+	// MessageEvent, MessageOffset and Sender describe the message from the append-only log which caused the event
+	MessageEvent  string                        `json:"message_event,omitempty"`
+	MessageOffset uint64                        `json:"message_offset"`
+	Sender        string                        `json:"sender,omitempty"`
+	Operation     *types.Operation              `json:"operation,omitempty"`
+	Signature     *types.ReconstructedSignature `json:"signature,omitempty"`
+	// Error is a reason of the message rejection
+	Error string `json:"error,omitempty"`
+	// Deadline is set for EventDeadlineApproaching
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Time     time.Time  `json:"time"`
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/corestario/kyber"
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/share"
+	vss "github.com/corestario/kyber/share/vss/pedersen"
+	"github.com/corestario/kyber/sign/bls"
+	"github.com/corestario/kyber/sign/tbls"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	"github.com/lidofinance/dc4bc/fsm/types/responses"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// savePubPolyCommitments sums up commitments broadcasted by every DKG participant, checks that the resulting
+// public polynomial matches the master public keys broadcasted by participants and saves it to the state
+func (c *BaseClient) savePubPolyCommitments(state State, dkgRoundID string,
+	fsmInstance *state_machines.FSMInstance) error {
+	// the suite is used to work with public DKG data only, so it does not need a seed
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+
+	payload := fsmInstance.FSMDump().Payload
+	if payload.DKGProposalPayload == nil {
+		return errors.New("DKG proposal payload is empty")
+	}
+
+	var commitments []kyber.Point
+	for _, participant := range payload.DKGProposalPayload.Quorum.GetOrderedParticipants() {
+		var commitsBz [][]byte
+		if err := json.Unmarshal(participant.DkgCommit, &commitsBz); err != nil {
+			return fmt.Errorf("failed to unmarshal commits of participant %s: %w", participant.Username, err)
+		}
+		if commitments != nil && len(commitsBz) != len(commitments) {
+			return fmt.Errorf("participant %s broadcasted %d commits, expected %d", participant.Username,
+				len(commitsBz), len(commitments))
+		}
+		for idx, commitBz := range commitsBz {
+			commit := blsSuite.Point()
+			if err := commit.UnmarshalBinary(commitBz); err != nil {
+				return fmt.Errorf("failed to unmarshal commit of participant %s: %w", participant.Username, err)
+			}
+			if len(commitments) <= idx {
+				commitments = append(commitments, commit)
+				continue
+			}
+			commitments[idx] = blsSuite.Point().Add(commitments[idx], commit)
+		}
+	}
+	if len(commitments) == 0 {
+		return errors.New("no commits were broadcasted")
+	}
+
+	masterPubKey := share.NewPubPoly(blsSuite, nil, commitments).Commit()
+	for _, participant := range payload.DKGProposalPayload.Quorum.GetOrderedParticipants() {
+		participantMasterPubKey := blsSuite.Point()
+		if err := participantMasterPubKey.UnmarshalBinary(participant.DkgMasterKey); err != nil {
+			return fmt.Errorf("failed to unmarshal master pub key of participant %s: %w", participant.Username, err)
+		}
+		if !masterPubKey.Equal(participantMasterPubKey) {
+			return fmt.Errorf("master pub key of participant %s does not match public polynomial",
+				participant.Username)
+		}
+	}
+
+	commitmentsBz := make([][]byte, 0, len(commitments))
+	for _, commitment := range commitments {
+		commitmentBz, err := commitment.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to marshal commitment: %w", err)
+		}
+		commitmentsBz = append(commitmentsBz, commitmentBz)
+	}
+
+	return state.SavePubPolyCommitments(dkgRoundID, commitmentsBz)
+}
+
+// loadPubPoly returns a stored public polynomial of the given DKG round
+func (c *BaseClient) loadPubPoly(state State, blsSuite vss.Suite, dkgRoundID string) (*share.PubPoly, error) {
+	commitmentsBz, err := state.LoadPubPolyCommitments(dkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to LoadPubPolyCommitments: %w", err)
+	}
+
+	commitments := make([]kyber.Point, 0, len(commitmentsBz))
+	for _, commitmentBz := range commitmentsBz {
+		commitment := blsSuite.Point()
+		if err := commitment.UnmarshalBinary(commitmentBz); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal commitment: %w", err)
+		}
+		commitments = append(commitments, commitment)
+	}
+
+	return share.NewPubPoly(blsSuite, nil, commitments), nil
+}
+
+// reconstructThresholdSignature recovers a full signature from broadcasted partial signs, verifies it against
+// the master public key of the DKG round and returns the message to broadcast it, nil if it's broadcasted already
+func (c *BaseClient) reconstructThresholdSignature(state State, dkgRoundID string, fsmInstance *state_machines.FSMInstance,
+	payload responses.SigningProcessParticipantResponse) (*storage.Message, error) {
+	// we could have broadcasted the signature already, e.g. if the log is being replayed
+	signatures, err := state.GetSignatures(dkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetSignatures: %w", err)
+	}
+	for _, signature := range signatures[payload.SigningId] {
+		if signature.Username == c.GetUsername() && len(signature.Signature) > 0 {
+			return nil, nil
+		}
+	}
+
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+	pubPoly, err := c.loadPubPoly(state, blsSuite, dkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load public polynomial: %w", err)
+	}
+
+	// a single corrupted partial sign must not prevent the reconstruction, so we skip invalid ones.
+	// A valid partial sign can be copied by another participant, so every share index is counted once
+	partialSignatures := make([][]byte, 0, len(payload.Participants))
+	shareIndexes := make(map[int]bool, len(payload.Participants))
+	for _, participant := range payload.Participants {
+		if err = tbls.Verify(blsSuite.(pairing.Suite), pubPoly, payload.SrcPayload, participant.PartialSign); err != nil {
+			c.Logger.Log("Partial sign of participant %s is invalid: %v", participant.Username, err)
+			continue
+		}
+		// the index is already parsed by tbls.Verify
+		shareIndex, _ := tbls.SigShare(participant.PartialSign).Index()
+		if shareIndexes[shareIndex] {
+			c.Logger.Log("Partial sign of participant %s duplicates another partial sign", participant.Username)
+			continue
+		}
+		shareIndexes[shareIndex] = true
+		partialSignatures = append(partialSignatures, participant.PartialSign)
+	}
+
+	dump := fsmInstance.FSMDump()
+	if len(partialSignatures) < dump.Payload.Threshold {
+		return nil, fmt.Errorf("not enough valid partial signs: %d, threshold is %d", len(partialSignatures),
+			dump.Payload.Threshold)
+	}
+	reconstructedSignature, err := tbls.Recover(blsSuite.(pairing.Suite), pubPoly, payload.SrcPayload,
+		partialSignatures, dump.Payload.Threshold, dump.Payload.DKGQuorumCount())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct full signature for msg: %w", err)
+	}
+
+	if err = bls.Verify(blsSuite.(pairing.Suite), pubPoly.Commit(), payload.SrcPayload,
+		reconstructedSignature); err != nil {
+		return nil, fmt.Errorf("failed to verify reconstructed signature: %w", err)
+	}
+
+	signature := types.ReconstructedSignature{
+		SigningID:  payload.SigningId,
+		SrcPayload: payload.SrcPayload,
+		Signature:  reconstructedSignature,
+		DKGRoundID: dkgRoundID,
+	}
+	signatureBz, err := json.Marshal(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal reconstructed signature: %w", err)
+	}
+
+	message, err := c.buildStateMessage(state, dkgRoundID, types.SignatureReconstructed, signatureBz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build message: %w", err)
+	}
+
+	return message, nil
+}
+
+// verifyReconstructedSignature checks a broadcasted signature against the master public key of the DKG round and
+// makes sure that it signs the payload of the signing session and does not conflict with other verified signatures
+func (c *BaseClient) verifyReconstructedSignature(state State, fsmInstance *state_machines.FSMInstance,
+	signature types.ReconstructedSignature) error {
+	if len(signature.Signature) == 0 {
+		return errors.New("signature is empty")
+	}
+
+	srcPayload, err := fsmInstance.SigningSrcPayload(signature.SigningID)
+	if err != nil {
+		return fmt.Errorf("failed to get signing payload: %w", err)
+	}
+	if !bytes.Equal(srcPayload, signature.SrcPayload) {
+		return errors.New("signed data conflicts with the payload of the signing proposal")
+	}
+
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+	pubPoly, err := c.loadPubPoly(state, blsSuite, signature.DKGRoundID)
+	if err != nil {
+		return fmt.Errorf("failed to load public polynomial: %w", err)
+	}
+
+	if err = bls.Verify(blsSuite.(pairing.Suite), pubPoly.Commit(), signature.SrcPayload,
+		signature.Signature); err != nil {
+		return fmt.Errorf("signature is invalid: %w", err)
+	}
+
+	signatures, err := state.GetSignatures(signature.DKGRoundID)
+	if err != nil {
+		return fmt.Errorf("failed to GetSignatures: %w", err)
+	}
+	for _, storedSignature := range signatures[signature.SigningID] {
+		// records without a signature, like the one saved on the signing start, have nothing to conflict with
+		if storedSignature.VerificationError != "" || len(storedSignature.Signature) == 0 {
+			continue
+		}
+		if !bytes.Equal(storedSignature.Signature, signature.Signature) {
+			return fmt.Errorf("signature conflicts with the signature broadcasted by %s", storedSignature.Username)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/share"
+	"github.com/corestario/kyber/sign/bls"
+	"github.com/corestario/kyber/sign/tbls"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/fsm/types/responses"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_ReconstructThresholdSignature(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_reconstruct")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+	state, stg := baseClient.state, round.storage
+
+	dkgRoundID := "dkg_round_id"
+	const threshold, participants = 2, 3
+	suite := bls12381.NewBLS12381Suite(nil)
+	priPoly := share.NewPriPoly(suite, threshold, nil, suite.RandomStream())
+	masterPubKey := suite.Point().Mul(priPoly.Secret(), nil)
+	_, commits := priPoly.Commit(suite.Point().Base()).Info()
+	commitsBz := make([][]byte, 0, len(commits))
+	for _, commit := range commits {
+		commitBz, err := commit.MarshalBinary()
+		req.NoError(err)
+		commitsBz = append(commitsBz, commitBz)
+	}
+	req.NoError(state.SavePubPolyCommitments(dkgRoundID, commitsBz))
+
+	dumpBz, err := json.Marshal(map[string]interface{}{
+		"TransactionId": dkgRoundID,
+		"State":         sipf.StateSigningIdle,
+		"Payload": map[string]interface{}{
+			"DkgId":     dkgRoundID,
+			"Threshold": threshold,
+			"DKGProposalPayload": map[string]interface{}{
+				"Quorum": map[string]interface{}{"0": struct{}{}, "1": struct{}{}, "2": struct{}{}},
+			},
+		},
+	})
+	req.NoError(err)
+	fsmInstance, err := state_machines.FromDump(dumpBz)
+	req.NoError(err)
+
+	srcPayload := []byte("message to sign")
+	shares := priPoly.Shares(participants)
+	partialSign := func(priShare *share.PriShare) []byte {
+		partialSign, err := tbls.Sign(suite.(pairing.Suite), priShare, srcPayload)
+		req.NoError(err)
+		return partialSign
+	}
+	validPartialSigns := make([][]byte, 0, participants)
+	for _, priShare := range shares {
+		validPartialSigns = append(validPartialSigns, partialSign(priShare))
+	}
+	// the partial sign of the second participant made with a wrong key share
+	invalidPartialSign := partialSign(&share.PriShare{I: 1, V: suite.Scalar().Pick(suite.RandomStream())})
+
+	reconstruct := func(signingID string, partialSigns ...[]byte) error {
+		response := responses.SigningProcessParticipantResponse{SigningId: signingID, SrcPayload: srcPayload}
+		for id, partialSign := range partialSigns {
+			response.Participants = append(response.Participants, &responses.SigningProcessParticipantEntry{
+				ParticipantId: id,
+				Username:      []string{"alice", "bob", "carol"}[id],
+				PartialSign:   partialSign,
+			})
+		}
+		message, err := baseClient.reconstructThresholdSignature(state, dkgRoundID, fsmInstance, response)
+		if err != nil {
+			return err
+		}
+		// the message is sent by the caller once the state is saved
+		req.NotNil(message)
+		return baseClient.SendMessage(*message)
+	}
+	broadcastedSignatures := func() []types.ReconstructedSignature {
+		messages, err := stg.GetMessages(0)
+		req.NoError(err)
+		signatures := make([]types.ReconstructedSignature, 0, len(messages))
+		for _, message := range messages {
+			req.Equal(string(types.SignatureReconstructed), message.Event)
+			var signature types.ReconstructedSignature
+			req.NoError(json.Unmarshal(message.Data, &signature))
+			signatures = append(signatures, signature)
+		}
+		return signatures
+	}
+
+	t.Run("test_invalid_partial_sign_is_skipped", func(t *testing.T) {
+		req.NoError(reconstruct("signing_1", validPartialSigns[0], invalidPartialSign, validPartialSigns[2]))
+
+		signatures := broadcastedSignatures()
+		req.Len(signatures, 1)
+		req.Equal("signing_1", signatures[0].SigningID)
+		req.NoError(bls.Verify(suite.(pairing.Suite), masterPubKey, srcPayload,
+			signatures[0].Signature))
+	})
+
+	t.Run("test_not_enough_valid_partial_signs", func(t *testing.T) {
+		err := reconstruct("signing_2", validPartialSigns[0], invalidPartialSign, nil)
+		req.Error(err)
+		req.Contains(err.Error(), "not enough valid partial signs: 1")
+		req.Len(broadcastedSignatures(), 1)
+	})
+
+	t.Run("test_duplicate_partial_sign_is_counted_once", func(t *testing.T) {
+		// bob broadcasts the partial sign of alice as his own
+		err := reconstruct("signing_3", validPartialSigns[0], validPartialSigns[0], invalidPartialSign)
+		req.Error(err)
+		req.Contains(err.Error(), "not enough valid partial signs: 1")
+		req.Len(broadcastedSignatures(), 1)
+
+		// the copy does not prevent the reconstruction from the partial signs of alice and carol either
+		req.NoError(reconstruct("signing_4", validPartialSigns[0], validPartialSigns[0], validPartialSigns[2]))
+		signatures := broadcastedSignatures()
+		req.Len(signatures, 2)
+		req.Equal("signing_4", signatures[1].SigningID)
+		req.NoError(bls.Verify(suite.(pairing.Suite), masterPubKey, srcPayload,
+			signatures[1].Signature))
+	})
+}
+
+func TestBaseClient_PubPolyFailureDoesNotStopSigningInit(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_pub_poly")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+	state := baseClient.state
+
+	// carol is the last to confirm the master key, but the commits can't be summed up into the public polynomial
+	// statuses of DKG participants of the internal package
+	const masterKeyAwaitConfirmation, masterKeyConfirmed = 9, 10
+	dkgRoundID := "dkg_round_id"
+	pubKeys, ids, quorum := map[string]interface{}{}, map[string]int{}, map[string]interface{}{}
+	for id, username := range []string{"alice", "bob", "carol"} {
+		pubKeys[username] = round.keyPair(username).Pub
+		ids[username] = id
+		status := masterKeyConfirmed
+		if username == "carol" {
+			status = masterKeyAwaitConfirmation
+		}
+		quorum[strconv.Itoa(id)] = map[string]interface{}{
+			"ParticipantID": id,
+			"Username":      username,
+			"DkgCommit":     []byte("not commits"),
+			"DkgMasterKey":  []byte("master key"),
+			"Status":        status,
+		}
+	}
+	dumpBz, err := json.Marshal(map[string]interface{}{
+		"TransactionId": dkgRoundID,
+		"State":         dpf.StateDkgMasterKeyAwaitConfirmations,
+		"Payload": map[string]interface{}{
+			"DkgId":     dkgRoundID,
+			"Threshold": 2,
+			"PubKeys":   pubKeys,
+			"IDs":       ids,
+			"DKGProposalPayload": map[string]interface{}{
+				"Quorum":    quorum,
+				"CreatedAt": time.Now(),
+				"UpdatedAt": time.Now(),
+				"ExpiresAt": time.Now().Add(time.Hour),
+			},
+		},
+	})
+	req.NoError(err)
+	fsmInstance, err := state_machines.FromDump(dumpBz)
+	req.NoError(err)
+	dump, err := fsmInstance.Dump()
+	req.NoError(err)
+	req.NoError(state.SaveFSM(dkgRoundID, dump))
+
+	confirmation, err := json.Marshal(requests.DKGProposalMasterKeyConfirmationRequest{
+		ParticipantId: ids["carol"],
+		MasterKey:     []byte("master key"),
+		CreatedAt:     time.Now(),
+	})
+	req.NoError(err)
+	message := storage.Message{
+		DkgRoundID: dkgRoundID,
+		Event:      string(dpf.EventDKGMasterKeyConfirmationReceived),
+		Data:       confirmation,
+		SenderAddr: "carol",
+	}
+	message.Signature = ed25519.Sign(round.keyPair("carol").Priv, message.Bytes())
+	req.NoError(baseClient.processMessageAtomically(message, nil))
+
+	fsmInstance, err = baseClient.getFSMInstance(state, dkgRoundID)
+	req.NoError(err)
+	req.Equal(sipf.StateSigningIdle, fsmInstance.FSMDump().State)
+	_, err = state.LoadPubPolyCommitments(dkgRoundID)
+	req.Error(err, "the public polynomial must not be saved")
+}
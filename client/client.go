@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"crypto/ed25519"
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,10 +14,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lidofinance/dc4bc/fsm/types/responses"
 
 	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
 
+	"github.com/lidofinance/dc4bc/client/api"
 	"github.com/lidofinance/dc4bc/client/types"
 	"github.com/lidofinance/dc4bc/fsm/types/requests"
 
@@ -44,7 +48,14 @@ type Client interface {
 	GetOperations() (map[string]*types.Operation, error)
 	GetOperationQRPath(operationID string) (string, error)
 	StartHTTPServer(listenAddr string) error
+	StartHTTPServerWithConfig(config HTTPServerConfig) error
+	StartGRPCServer(config GRPCServerConfig) error
+	StartWeb3SignerServer(config Web3SignerConfig) error
+	SubscribeEvents() (<-chan api.Event, func())
+	StartWebhooks(config WebhookConfig) error
 	SetSkipCommKeysVerification(bool)
+	SetBroadcastEquivocations(bool)
+	RebuildState(rebuilt State) (*RebuildReport, error)
 }
 
 type BaseClient struct {
@@ -58,6 +69,11 @@ type BaseClient struct {
 	keyStore                 KeyStore
 	qrProcessor              qr.Processor
 	SkipCommKeysVerification bool
+	// BroadcastEquivocations makes the client send the evidences of equivocations it finds to the log
+	BroadcastEquivocations bool
+	events                 *eventBus
+	// webhooks queues the events of processed messages for delivery, it's nil when webhooks are not started
+	webhooks *webhookSender
 }
 
 func NewClient(
@@ -73,15 +89,17 @@ func NewClient(
 		return nil, fmt.Errorf("failed to LoadKeys: %w", err)
 	}
 
+	clientLogger := newLogger(userName)
 	return &BaseClient{
 		ctx:         ctx,
-		Logger:      newLogger(userName),
+		Logger:      clientLogger,
 		userName:    userName,
 		pubKey:      keyPair.Pub,
 		state:       state,
 		storage:     storage,
 		keyStore:    keyStore,
 		qrProcessor: qrProcessor,
+		events:      newEventBus(clientLogger),
 	}, nil
 }
 
@@ -101,6 +119,16 @@ func (c *BaseClient) SetSkipCommKeysVerification(f bool) {
 	c.SkipCommKeysVerification = f
 }
 
+func (c *BaseClient) SetBroadcastEquivocations(f bool) {
+	c.BroadcastEquivocations = f
+}
+
+// SubscribeEvents returns a channel with events about FSM state changes, new operations, signatures and
+// rejected messages, the returned function must be called to unsubscribe
+func (c *BaseClient) SubscribeEvents() (<-chan api.Event, func()) {
+	return c.events.subscribe()
+}
+
 // Poll is a main client loop, which gets new messages from an append-only log and processes them
 func (c *BaseClient) Poll() error {
 	tk := time.NewTicker(pollingPeriod)
@@ -112,26 +140,14 @@ func (c *BaseClient) Poll() error {
 				return fmt.Errorf("failed to LoadOffset: %w", err)
 			}
 
-			messages, err := c.storage.GetMessages(offset)
+			messages, err := c.readLog(offset)
 			if err != nil {
-				return fmt.Errorf("failed to GetMessages: %w", err)
+				return fmt.Errorf("failed to read append-only log: %w", err)
 			}
 
 			for _, message := range messages {
-				c.Logger.Log("Handling message with offset %d, type %s", message.Offset, message.Event)
-				if message.RecipientAddr == "" || message.RecipientAddr == c.GetUsername() {
-					if err := c.ProcessMessage(message); err != nil {
-						c.Logger.Log("Failed to process message with offset %d: %v", message.Offset, err)
-					} else {
-						c.Logger.Log("Successfully processed message with offset %d, type %s",
-							message.Offset, message.Event)
-					}
-				} else {
-					c.Logger.Log("Message with offset %d, type %s is not intended for us, skip it",
-						message.Offset, message.Event)
-				}
-				if err := c.state.SaveOffset(message.Offset + 1); err != nil {
-					c.Logger.Log("Failed to save offset: %v", err)
+				if err = c.pollMessage(message); err != nil {
+					return fmt.Errorf("append-only log is refused: %w", err)
 				}
 			}
 		case <-c.ctx.Done():
@@ -141,6 +157,39 @@ func (c *BaseClient) Poll() error {
 	}
 }
 
+// handleMessage processes the message from the append-only log and moves the offset past it. A failed message
+// is put to the dead-letter list, a processed one could unblock the messages which arrived too early
+func (c *BaseClient) handleMessage(message storage.Message) {
+	c.Logger.Log("Handling message with offset %d, type %s", message.Offset, message.Event)
+	// deals for other participants are checked too, they are signed by the sender
+	c.checkEquivocation(message)
+	if message.RecipientAddr != "" && message.RecipientAddr != c.GetUsername() {
+		c.Logger.Log("Message with offset %d, type %s is not intended for us, skip it",
+			message.Offset, message.Event)
+		err := c.state.Atomic(func(tx State) error {
+			return advanceLog(tx, message)
+		})
+		if err != nil {
+			c.Logger.Log("Failed to save offset: %v", err)
+		}
+		return
+	}
+
+	// the offset is saved together with the state changes made by the message
+	err := c.processMessageAtomically(message, func(tx State) error {
+		return advanceLog(tx, message)
+	})
+	if err != nil {
+		c.Logger.Log("Failed to process message with offset %d: %v", message.Offset, err)
+		if err = c.saveDeadLetter(message, err); err != nil {
+			c.Logger.Log("Failed to save dead letter: %v", err)
+		}
+		return
+	}
+	c.Logger.Log("Successfully processed message with offset %d, type %s", message.Offset, message.Event)
+	c.retryDeadLetters()
+}
+
 func (c *BaseClient) SendMessage(message storage.Message) error {
 	if _, err := c.storage.Send(message); err != nil {
 		return fmt.Errorf("failed to post message: %w", err)
@@ -150,7 +199,7 @@ func (c *BaseClient) SendMessage(message storage.Message) error {
 }
 
 // processSignature saves a broadcasted reconstructed signature to a LevelDB
-func (c *BaseClient) processSignature(message storage.Message) error {
+func (c *BaseClient) processSignature(state State, message storage.Message) error {
 	var (
 		signature types.ReconstructedSignature
 		err       error
@@ -160,21 +209,132 @@ func (c *BaseClient) processSignature(message storage.Message) error {
 	}
 	signature.Username = message.SenderAddr
 	signature.DKGRoundID = message.DkgRoundID
-	return c.state.SaveSignature(signature)
+	return state.SaveSignature(signature)
 }
 
+// processReconstructedSignature verifies a broadcasted reconstructed signature and saves it to a LevelDB.
+// Invalid and conflicting signatures are saved too, but they are flagged with a verification error
+func (c *BaseClient) processReconstructedSignature(state State, message storage.Message) (api.Event, error) {
+	fsmInstance, err := c.getFSMInstance(state, message.DkgRoundID)
+	if err != nil {
+		return api.Event{}, fmt.Errorf("failed to getFSMInstance: %w", err)
+	}
+
+	if err = c.verifyMessage(fsmInstance, message); err != nil {
+		return api.Event{}, fmt.Errorf("failed to verifyMessage %+v: %w", message, err)
+	}
+
+	var signature types.ReconstructedSignature
+	if err = json.Unmarshal(message.Data, &signature); err != nil {
+		return api.Event{}, fmt.Errorf("failed to unmarshal reconstructed signature: %w", err)
+	}
+	signature.Username = message.SenderAddr
+	signature.DKGRoundID = message.DkgRoundID
+	signature.VerificationError = ""
+
+	if err = c.verifyReconstructedSignature(state, fsmInstance, signature); err != nil {
+		c.Logger.Log("Reconstructed signature for signing %s from %s is rejected: %v",
+			signature.SigningID, signature.Username, err)
+		signature.VerificationError = err.Error()
+	}
+
+	if err = state.SaveSignature(signature); err != nil {
+		return api.Event{}, fmt.Errorf("failed to save signature: %w", err)
+	}
+
+	// a rejected signature is saved for inspection, but the message itself is rejected
+	if signature.VerificationError != "" {
+		event := messageEvent(api.EventMessageRejected, message)
+		event.SigningID = signature.SigningID
+		event.Signature = &signature
+		event.Error = signature.VerificationError
+		return event, nil
+	}
+	event := messageEvent(api.EventSignatureReconstructed, message)
+	event.SigningID = signature.SigningID
+	event.Signature = &signature
+	return event, nil
+}
+
+// ProcessMessage applies the message from the append-only log to the client state,
+// the state changes made by the message are saved all together or not saved at all
 func (c *BaseClient) ProcessMessage(message storage.Message) error {
+	return c.processMessageAtomically(message, nil)
+}
+
+// processMessageAtomically processes the message as a single unit of work, which also includes
+// the changes made by the optional after function, e.g. the offset of the next message.
+// Events are published and resulting messages are sent only when the changes are saved
+func (c *BaseClient) processMessageAtomically(message storage.Message, after func(tx State) error) error {
+	var (
+		events   []api.Event
+		outgoing []storage.Message
+	)
+	err := c.state.Atomic(func(tx State) error {
+		var err error
+		if events, outgoing, err = c.processMessage(tx, message); err != nil {
+			return err
+		}
+		if c.webhooks != nil {
+			if err = c.webhooks.enqueue(tx, events...); err != nil {
+				return fmt.Errorf("failed to enqueue webhooks: %w", err)
+			}
+		}
+		if after != nil {
+			return after(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		event := messageEvent(api.EventMessageRejected, message)
+		event.Error = err.Error()
+		c.events.publish(event)
+		return err
+	}
+
+	for _, event := range events {
+		c.events.publish(event)
+	}
+	if c.webhooks != nil {
+		c.webhooks.notify()
+	}
+	// the changes are already saved, so a message which can't be sent is only logged
+	for _, outgoingMessage := range outgoing {
+		if err = c.SendMessage(outgoingMessage); err != nil {
+			c.Logger.Log("Failed to send message %s: %v", outgoingMessage.Event, err)
+		}
+	}
+	return nil
+}
+
+// processMessage applies the message to the given state and returns the events about the changes and the messages
+// to send to the log. The messages must be sent only after the state is committed
+func (c *BaseClient) processMessage(state State, message storage.Message) ([]api.Event, []storage.Message, error) {
 	// save broadcasted reconstructed signature
 	if fsm.Event(message.Event) == types.SignatureReconstructed {
-		if err := c.processSignature(message); err != nil {
-			return fmt.Errorf("failed to process signature: %w", err)
+		event, err := c.processReconstructedSignature(state, message)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to process signature: %w", err)
 		}
-		return nil
+		return []api.Event{event}, nil, nil
+	}
+	if fsm.Event(message.Event) == types.EquivocationEvidence {
+		events, err := c.processEquivocationEvidence(state, message)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to process equivocation evidence: %w", err)
+		}
+		return events, nil, nil
+	}
+	if fsm.Event(message.Event) == types.LogCheckpointPublished {
+		if err := c.processLogCheckpoint(state, message); err != nil {
+			return nil, nil, fmt.Errorf("failed to process log checkpoint: %w", err)
+		}
+		return nil, nil, nil
 	}
 
-	fsmInstance, err := c.getFSMInstance(message.DkgRoundID)
+	fsmInstance, err := c.getFSMInstance(state, message.DkgRoundID)
 	if err != nil {
-		return fmt.Errorf("failed to getFSMInstance: %w", err)
+		return nil, nil, fmt.Errorf("failed to getFSMInstance: %w", err)
 	}
 
 	//TODO: refactor the following checks
@@ -186,30 +346,10 @@ func (c *BaseClient) ProcessMessage(message storage.Message) error {
 					log.Printf("Participant %s got an error during DKG process: %s. DKG aborted\n",
 						participant.Username, participant.Error.Error())
 					// if we have an error during DKG, abort the whole DKG procedure.
-					return nil
+					return nil, nil, nil
 				}
 			}
 		}
-		if fsmInstance.FSMDump().Payload.SigningProposalPayload != nil {
-			for _, participant := range fsmInstance.FSMDump().Payload.SigningProposalPayload.Quorum {
-				if participant.Error != nil {
-					log.Printf("Participant %s got an error during signing procedure: %s. Signing procedure aborted\n",
-						participant.Username, participant.Error.Error())
-					break
-				}
-			}
-			//if we have an error during signing procedure, start a new signing procedure
-			_, fsmDump, err := fsmInstance.Do(sipf.EventSigningRestart, requests.DefaultRequest{
-				CreatedAt: time.Now(),
-			})
-			if err != nil {
-				return fmt.Errorf("failed to Do operation in FSM: %w", err)
-			}
-
-			if err := c.state.SaveFSM(message.DkgRoundID, fsmDump); err != nil {
-				return fmt.Errorf("failed to SaveFSM: %w", err)
-			}
-		}
 	}
 
 	//handle timeout errors
@@ -219,41 +359,47 @@ func (c *BaseClient) ProcessMessage(message storage.Message) error {
 			log.Printf("DKG process with ID \"%s\" aborted cause of timeout\n",
 				fsmInstance.FSMDump().Payload.DkgId)
 			// if we have an error during DKG, abort the whole DKG procedure.
-			return nil
-		}
-		if strings.HasPrefix(string(fsmInstance.FSMDump().State), "state_signing_") {
-			log.Printf("Signing process with ID \"%s\" aborted cause of timeout\n",
-				fsmInstance.FSMDump().Payload.SigningProposalPayload.SigningId)
-
-			//if we have an error during signing procedure, start a new signing procedure
-			_, fsmDump, err := fsmInstance.Do(sipf.EventSigningRestart, requests.DefaultRequest{
-				CreatedAt: time.Now(),
-			})
-			if err != nil {
-				return fmt.Errorf("failed to Do operation in FSM: %w", err)
-			}
-
-			if err := c.state.SaveFSM(message.DkgRoundID, fsmDump); err != nil {
-				return fmt.Errorf("failed to SaveFSM: %w", err)
-			}
+			return nil, nil, nil
 		}
 	}
 
 	// we can't verify a message at this moment, cause we don't have public keys of participants
 	if fsm.Event(message.Event) != spf.EventInitProposal {
 		if err := c.verifyMessage(fsmInstance, message); err != nil {
-			return fmt.Errorf("failed to verifyMessage %+v: %w", message, err)
+			return nil, nil, fmt.Errorf("failed to verifyMessage %+v: %w", message, err)
 		}
 	}
 
 	fsmReq, err := types.FSMRequestFromMessage(message)
 	if err != nil {
-		return fmt.Errorf("failed to get FSMRequestFromMessage: %v", err)
+		return nil, nil, fmt.Errorf("failed to get FSMRequestFromMessage: %v", err)
+	}
+	// the initiator is the sender of the proposal whatever the proposal says
+	if req, ok := fsmReq.(requests.SignatureProposalParticipantsListRequest); ok {
+		req.Initiator = message.SenderAddr
+		fsmReq = req
+	}
+	// a participant can act only on his own behalf, e.g. nobody but the initiator can abort a proposal.
+	// Participants are unknown before the proposal, but the FSM refuses any request but the proposal then
+	if participantID, ok := requestParticipantID(fsmReq); ok && fsmInstance.FSMDump().Payload.IDs != nil {
+		senderID, err := fsmInstance.GetIDByUsername(message.SenderAddr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to GetIDByUsername: %w", err)
+		}
+		if participantID != senderID {
+			return nil, nil, fmt.Errorf("participant ID %d does not belong to the sender %s", participantID,
+				message.SenderAddr)
+		}
 	}
 
+	fromState := fsmInstance.FSMDump().State
 	resp, fsmDump, err := fsmInstance.Do(fsm.Event(message.Event), fsmReq)
 	if err != nil {
-		return fmt.Errorf("failed to Do operation in FSM: %w", err)
+		return nil, nil, fmt.Errorf("failed to Do operation in FSM: %w", err)
+	}
+	transitions := newFSMTransitions(state, message)
+	if err = transitions.save(fsm.Event(message.Event), false, fromState, resp.State); err != nil {
+		return nil, nil, err
 	}
 
 	c.Logger.Log("message %s done successfully from %s", message.Event, message.SenderAddr)
@@ -262,25 +408,122 @@ func (c *BaseClient) ProcessMessage(message storage.Message) error {
 	if resp.State == spf.StateSignatureProposalCollected {
 		fsmInstance, err = state_machines.FromDump(fsmDump)
 		if err != nil {
-			return fmt.Errorf("failed get state_machines from dump: %w", err)
+			return nil, nil, fmt.Errorf("failed get state_machines from dump: %w", err)
 		}
+		fromState = resp.State
 		resp, fsmDump, err = fsmInstance.Do(dpf.EventDKGInitProcess, requests.DefaultRequest{
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
-			return fmt.Errorf("failed to Do operation in FSM: %w", err)
+			return nil, nil, fmt.Errorf("failed to Do operation in FSM: %w", err)
+		}
+		if err = transitions.save(dpf.EventDKGInitProcess, true, fromState, resp.State); err != nil {
+			return nil, nil, err
 		}
 	}
 	if resp.State == dpf.StateDkgMasterKeyCollected {
 		fsmInstance, err = state_machines.FromDump(fsmDump)
 		if err != nil {
-			return fmt.Errorf("failed get state_machines from dump: %w", err)
+			return nil, nil, fmt.Errorf("failed get state_machines from dump: %w", err)
+		}
+		// public polynomial allows us to reconstruct threshold signatures without the airgapped machine.
+		// Signings don't need it otherwise, so the round goes on and only the reconstruction fails without it
+		if err = c.savePubPolyCommitments(state, message.DkgRoundID, fsmInstance); err != nil {
+			c.Logger.Log("Failed to save public polynomial commitments of DKG round %s: %v", message.DkgRoundID, err)
 		}
+		fromState = resp.State
 		resp, fsmDump, err = fsmInstance.Do(sipf.EventSigningInit, requests.DefaultRequest{
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
-			return fmt.Errorf("failed to Do operation in FSM: %w", err)
+			return nil, nil, fmt.Errorf("failed to Do operation in FSM: %w", err)
+		}
+		if err = transitions.save(sipf.EventSigningInit, true, fromState, resp.State); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var outgoing []storage.Message
+	// partial signs and the public polynomial are public, so we reconstruct the full signature by ourselves
+	if resp.State == sipf.StateSigningPartialSignsCollected {
+		data, ok := resp.Data.(responses.SigningProcessParticipantResponse)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid response data for state %s", resp.State)
+		}
+		// every participant can reconstruct the signature, but only the initiator of the signing broadcasts it
+		participantID, err := fsmInstance.GetIDByUsername(c.GetUsername())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to GetIDByUsername: %w", err)
+		}
+		if participantID == data.InitiatorId {
+			// the signing session is finished anyway, so we don't return the error here
+			signatureMessage, err := c.reconstructThresholdSignature(state, message.DkgRoundID, fsmInstance, data)
+			if err != nil {
+				c.Logger.Log("Failed to reconstruct threshold signature for signing %s: %v", data.SigningId, err)
+			} else if signatureMessage != nil {
+				outgoing = append(outgoing, *signatureMessage)
+			}
+		}
+	}
+
+	switch req := fsmReq.(type) {
+	case requests.SignatureProposalParticipantRequest:
+		if req.Reason != "" {
+			c.Logger.Log("DKG proposal declined by %s: %s", message.SenderAddr, req.Reason)
+		}
+	case requests.SignatureProposalAbortRequest:
+		c.Logger.Log("DKG proposal aborted by %s: %s", message.SenderAddr, req.Reason)
+	case requests.SigningProposalParticipantRequest:
+		if req.Reason != "" {
+			c.Logger.Log("Signing %s declined by %s: %s", req.SigningId, message.SenderAddr, req.Reason)
+		}
+		// our own decline might be sent without the airgapped machine, so the invitation is not needed anymore
+		if fsm.Event(message.Event) == sipf.EventDeclineSigningConfirmation && message.SenderAddr == c.GetUsername() {
+			if err = c.dropSigningInvitation(state, message.DkgRoundID, req.SigningId); err != nil {
+				return nil, nil, fmt.Errorf("failed to drop signing invitation: %w", err)
+			}
+		}
+	case requests.SigningProposalAbortRequest:
+		c.Logger.Log("Signing %s aborted by %s: %s", req.SigningId, message.SenderAddr, req.Reason)
+	}
+
+	if resp.State == spf.StateValidationCanceledByParticipant {
+		if err = c.dropCancelledOperations(state, message.DkgRoundID, fsmInstance); err != nil {
+			return nil, nil, fmt.Errorf("failed to drop cancelled operations: %w", err)
+		}
+	}
+
+	// other signings of the DKG round are not affected
+	if sipf.IsFinalState(resp.State) && resp.State != sipf.StateSigningPartialSignsCollected {
+		c.Logger.Log("Signing process aborted with state %s", resp.State)
+		if err = c.dropCancelledOperations(state, message.DkgRoundID, fsmInstance); err != nil {
+			return nil, nil, fmt.Errorf("failed to drop cancelled operations: %w", err)
+		}
+	}
+
+	// switch FSM state by hand: a finished signing frees a slot for a queued signing proposal, and a newly queued
+	// proposal may find a slot of an expired signing
+	var rejectedEvents []api.Event
+	if sipf.IsFinalState(resp.State) ||
+		(fsm.Event(message.Event) == sipf.EventSigningStart && resp.State == sipf.StateSigningIdle) {
+		createdAt, err := state_machines.GetRequestCreatedAt(fsmReq)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get request time: %w", err)
+		}
+		queuedResp, queuedDump, rejected, err := fsmInstance.StartQueuedSigning(createdAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to start queued signing: %w", err)
+		}
+		fsmDump = queuedDump
+		if queuedResp != nil {
+			resp = queuedResp
+		}
+		for _, rejection := range rejected {
+			c.Logger.Log("Queued signing %s rejected: %v", rejection.SigningID, rejection.Err)
+			event := messageEvent(api.EventSigningRejected, message)
+			event.SigningID = rejection.SigningID
+			event.Error = rejection.Err.Error()
+			rejectedEvents = append(rejectedEvents, event)
 		}
 	}
 
@@ -294,15 +537,14 @@ func (c *BaseClient) ProcessMessage(message storage.Message) error {
 		dpf.StateDkgResponsesAwaitConfirmations,
 		dpf.StateDkgMasterKeyAwaitConfirmations,
 		sipf.StateSigningAwaitPartialSigns,
-		sipf.StateSigningPartialSignsCollected,
 		sipf.StateSigningAwaitConfirmations:
 		if resp.Data != nil {
 
 			// if we are initiator of signing, then we don't need to confirm our participation
 			if data, ok := resp.Data.(responses.SigningProposalParticipantInvitationsResponse); ok {
-				initiator, err := fsmInstance.SigningQuorumGetParticipant(data.InitiatorId)
+				initiator, err := fsmInstance.SigningQuorumGetParticipant(data.SigningId, data.InitiatorId)
 				if err != nil {
-					return fmt.Errorf("failed to get SigningQuorumParticipant: %w", err)
+					return nil, nil, fmt.Errorf("failed to get SigningQuorumParticipant: %w", err)
 				}
 				if initiator.Username == c.GetUsername() {
 					break
@@ -311,7 +553,7 @@ func (c *BaseClient) ProcessMessage(message storage.Message) error {
 
 			operationPayloadBz, err := json.Marshal(resp.Data)
 			if err != nil {
-				return fmt.Errorf("failed to marshal FSM response: %w", err)
+				return nil, nil, fmt.Errorf("failed to marshal FSM response: %w", err)
 			}
 
 			operation = types.NewOperation(
@@ -324,54 +566,91 @@ func (c *BaseClient) ProcessMessage(message storage.Message) error {
 		c.Logger.Log("State %s does not require an operation", resp.State)
 	}
 
-	// switch FSM state by hand due to implementation specifics
-	if resp.State == sipf.StateSigningPartialSignsCollected {
-		fsmInstance, err = state_machines.FromDump(fsmDump)
-		if err != nil {
-			return fmt.Errorf("failed get state_machines from dump: %w", err)
-		}
-		resp, fsmDump, err = fsmInstance.Do(sipf.EventSigningRestart, requests.DefaultRequest{
-			CreatedAt: time.Now(),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to Do operation in FSM: %w", err)
-		}
-	}
-
 	// save signing data to the same storage as we save signatures
 	// This allows easy to view signing data by CLI-command
 	if fsm.Event(message.Event) == sipf.EventSigningStart {
-		if err := c.processSignature(message); err != nil {
-			return fmt.Errorf("failed to process signature: %w", err)
+		if err := c.processSignature(state, message); err != nil {
+			return nil, nil, fmt.Errorf("failed to process signature: %w", err)
 		}
 	}
 
 	if operation != nil {
-		if err := c.state.PutOperation(operation); err != nil {
-			return fmt.Errorf("failed to PutOperation: %w", err)
+		if err := state.PutOperation(operation); err != nil {
+			return nil, nil, fmt.Errorf("failed to PutOperation: %w", err)
 		}
 	}
 
-	if err := c.state.SaveFSM(message.DkgRoundID, fsmDump); err != nil {
-		return fmt.Errorf("failed to SaveFSM: %w", err)
+	if err := state.SaveFSM(message.DkgRoundID, fsmDump); err != nil {
+		return nil, nil, fmt.Errorf("failed to SaveFSM: %w", err)
 	}
 
-	return nil
+	// signingID is empty for the events of the DKG round itself
+	signingID, _ := state_machines.GetSigningID(fsmReq)
+	event := messageEvent(api.EventFSMStateChanged, message)
+	event.State = string(resp.State)
+	event.SigningID = signingID
+	events := append([]api.Event{event}, rejectedEvents...)
+	if operation != nil {
+		event = messageEvent(api.EventNewOperation, message)
+		event.State = string(operation.Type)
+		event.SigningID = signingID
+		event.Operation = operation
+		events = append(events, event)
+	}
+
+	return events, outgoing, nil
 }
 
 func (c *BaseClient) GetOperations() (map[string]*types.Operation, error) {
 	return c.state.GetOperations()
 }
 
-//GetSignatures returns all signatures for the given DKG round that were reconstructed on the airgapped machine and
-// broadcasted by users
+//GetSignatures returns all verified signatures for the given DKG round that were reconstructed and broadcasted by users
 func (c *BaseClient) GetSignatures(dkgID string) (map[string][]types.ReconstructedSignature, error) {
-	return c.state.GetSignatures(dkgID)
+	signatures, err := c.state.GetSignatures(dkgID)
+	if err != nil {
+		return nil, err
+	}
+	verifiedSignatures := make(map[string][]types.ReconstructedSignature, len(signatures))
+	for signingID, signingSignatures := range signatures {
+		verifiedSignatures[signingID] = filterSignatures(signingSignatures, true)
+	}
+	return verifiedSignatures, nil
 }
 
-//GetSignatureByDataHash returns a list of reconstructed signatures of the signed data broadcasted by users
+//GetSignatureByDataHash returns a list of verified reconstructed signatures of the signed data broadcasted by users
 func (c *BaseClient) GetSignatureByID(dkgID, sigID string) ([]types.ReconstructedSignature, error) {
-	return c.state.GetSignatureByID(dkgID, sigID)
+	signatures, err := c.state.GetSignatureByID(dkgID, sigID)
+	if err != nil {
+		return nil, err
+	}
+	return filterSignatures(signatures, true), nil
+}
+
+// GetRejectedSignatures returns all broadcasted signatures for the given DKG round that failed verification
+func (c *BaseClient) GetRejectedSignatures(dkgID string) (map[string][]types.ReconstructedSignature, error) {
+	signatures, err := c.state.GetSignatures(dkgID)
+	if err != nil {
+		return nil, err
+	}
+	rejectedSignatures := make(map[string][]types.ReconstructedSignature)
+	for signingID, signingSignatures := range signatures {
+		if rejected := filterSignatures(signingSignatures, false); len(rejected) > 0 {
+			rejectedSignatures[signingID] = rejected
+		}
+	}
+	return rejectedSignatures, nil
+}
+
+// filterSignatures returns either verified or rejected signatures from the list
+func filterSignatures(signatures []types.ReconstructedSignature, verified bool) []types.ReconstructedSignature {
+	filtered := make([]types.ReconstructedSignature, 0, len(signatures))
+	for _, signature := range signatures {
+		if (signature.VerificationError == "") == verified {
+			filtered = append(filtered, signature)
+		}
+	}
+	return filtered
 }
 
 // getOperationJSON returns a specific JSON-encoded operation
@@ -422,6 +701,9 @@ func (c *BaseClient) handleProcessedOperation(operation types.Operation) error {
 
 	for i, message := range operation.ResultMsgs {
 		message.SenderAddr = c.GetUsername()
+		if err := c.commitToLog(c.state, &message); err != nil {
+			return fmt.Errorf("failed to commit message to the log: %w", err)
+		}
 
 		sig, err := c.signMessage(message.Bytes())
 		if err != nil {
@@ -443,10 +725,110 @@ func (c *BaseClient) handleProcessedOperation(operation types.Operation) error {
 	return nil
 }
 
-// getFSMInstance returns a FSM for a necessary DKG round.
-func (c *BaseClient) getFSMInstance(dkgRoundID string) (*state_machines.FSMInstance, error) {
+// dropCancelledOperations removes pending operations of a cancelled DKG proposal or of cancelled signings,
+// so they are not sent to the airgapped machine anymore
+func (c *BaseClient) dropCancelledOperations(state State, dkgRoundID string,
+	fsmInstance *state_machines.FSMInstance) error {
+	operations, err := state.GetOperations()
+	if err != nil {
+		return fmt.Errorf("failed to get operations: %w", err)
+	}
+
+	for id, operation := range operations {
+		if operation.DKGIdentifier != dkgRoundID {
+			continue
+		}
+
+		var cancelled bool
+		switch fsm.State(operation.Type) {
+		case spf.StateAwaitParticipantsConfirmations:
+			state, err := fsmInstance.State()
+			if err != nil {
+				return fmt.Errorf("failed to get FSM state: %w", err)
+			}
+			cancelled = state == spf.StateValidationCanceledByParticipant
+		case sipf.StateSigningAwaitConfirmations, sipf.StateSigningAwaitPartialSigns:
+			var payload struct{ SigningId string }
+			if err = json.Unmarshal(operation.Payload, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal operation payload: %w", err)
+			}
+			// operations of unknown signings are left untouched
+			state, err := fsmInstance.SigningState(payload.SigningId)
+			cancelled = err == nil && sipf.IsFinalState(state)
+		}
+
+		if cancelled {
+			if err = state.DeleteOperation(id); err != nil {
+				return fmt.Errorf("failed to DeleteOperation: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// dropSigningInvitation removes a pending operation which invites us to confirm the signing
+func (c *BaseClient) dropSigningInvitation(state State, dkgRoundID, signingID string) error {
+	operations, err := state.GetOperations()
+	if err != nil {
+		return fmt.Errorf("failed to get operations: %w", err)
+	}
+
+	for id, operation := range operations {
+		if operation.DKGIdentifier != dkgRoundID || fsm.State(operation.Type) != sipf.StateSigningAwaitConfirmations {
+			continue
+		}
+		var payload responses.SigningProposalParticipantInvitationsResponse
+		if err = json.Unmarshal(operation.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal operation payload: %w", err)
+		}
+		if payload.SigningId == signingID {
+			if err = state.DeleteOperation(id); err != nil {
+				return fmt.Errorf("failed to DeleteOperation: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestParticipantID returns the ID of the participant on whose behalf the FSM request is made
+func requestParticipantID(fsmReq interface{}) (int, bool) {
+	switch req := fsmReq.(type) {
+	case requests.SignatureProposalParticipantRequest:
+		return req.ParticipantId, true
+	case requests.SignatureProposalAbortRequest:
+		return req.ParticipantId, true
+	case requests.SignatureProposalConfirmationErrorRequest:
+		return req.ParticipantId, true
+	case requests.DKGProposalCommitConfirmationRequest:
+		return req.ParticipantId, true
+	case requests.DKGProposalDealConfirmationRequest:
+		return req.ParticipantId, true
+	case requests.DKGProposalResponseConfirmationRequest:
+		return req.ParticipantId, true
+	case requests.DKGProposalMasterKeyConfirmationRequest:
+		return req.ParticipantId, true
+	case requests.DKGProposalConfirmationErrorRequest:
+		return req.ParticipantId, true
+	case requests.SigningProposalStartRequest:
+		return req.ParticipantId, true
+	case requests.SigningProposalParticipantRequest:
+		return req.ParticipantId, true
+	case requests.SigningProposalAbortRequest:
+		return req.ParticipantId, true
+	case requests.SigningProposalPartialSignRequest:
+		return req.ParticipantId, true
+	case requests.SigningProposalConfirmationErrorRequest:
+		return req.ParticipantId, true
+	}
+	return 0, false
+}
+
+// getFSMInstance returns a FSM for a necessary DKG round, a new FSM is saved to the given state.
+func (c *BaseClient) getFSMInstance(state State, dkgRoundID string) (*state_machines.FSMInstance, error) {
 	var err error
-	fsmInstance, ok, err := c.state.LoadFSM(dkgRoundID)
+	fsmInstance, ok, err := state.LoadFSM(dkgRoundID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to LoadFSM: %w", err)
 	}
@@ -460,7 +842,7 @@ func (c *BaseClient) getFSMInstance(dkgRoundID string) (*state_machines.FSMInsta
 		if err != nil {
 			return nil, fmt.Errorf("failed to Dump FSM instance: %w", err)
 		}
-		if err := c.state.SaveFSM(dkgRoundID, bz); err != nil {
+		if err := state.SaveFSM(dkgRoundID, bz); err != nil {
 			return nil, fmt.Errorf("failed to SaveFSM: %w", err)
 		}
 	}
@@ -494,9 +876,92 @@ func (c *BaseClient) verifyMessage(fsmInstance *state_machines.FSMInstance, mess
 }
 
 func (c *BaseClient) GetFSMDump(dkgID string) (*state_machines.FSMDump, error) {
-	fsmInstance, err := c.getFSMInstance(dkgID)
+	fsmInstance, err := c.getFSMInstance(c.state, dkgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get FSM instance for DKG round ID %s: %w", dkgID, err)
 	}
 	return fsmInstance.FSMDump(), nil
 }
+
+// GetSigningQueue returns signing proposals of the DKG round which are waiting to be started, in order
+func (c *BaseClient) GetSigningQueue(dkgID string) ([]requests.SigningProposalStartRequest, error) {
+	fsmInstance, err := c.getFSMInstance(c.state, dkgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FSM instance for DKG round ID %s: %w", dkgID, err)
+	}
+	queue := fsmInstance.FSMDump().Payload.SigningProposalsQueue
+	if queue == nil {
+		queue = []requests.SigningProposalStartRequest{}
+	}
+	return queue, nil
+}
+
+// startDKG sends the DKG proposal to the append-only log and returns the ID of the new DKG round
+func (c *BaseClient) startDKG(proposal []byte) (string, error) {
+	dkgRoundID := md5.Sum(proposal)
+	dkgID := hex.EncodeToString(dkgRoundID[:])
+	message, err := c.buildMessage(dkgID, spf.EventInitProposal, proposal)
+	if err != nil {
+		return "", fmt.Errorf("failed to build message: %w", err)
+	}
+	if err = c.SendMessage(*message); err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+	return dkgID, nil
+}
+
+// proposeSign sends the signing proposal of the data to the append-only log and returns the ID of the new signing
+func (c *BaseClient) proposeSign(dkgID string, data []byte) (string, error) {
+	return c.proposeSignObject(dkgID, data, nil)
+}
+
+// proposeSignObject proposes to sign the data which is the signing root of the signing object,
+// signers check the root against the object
+func (c *BaseClient) proposeSignObject(dkgID string, data []byte, signingObject []byte) (string, error) {
+	fsmInstance, err := c.getFSMInstance(c.state, dkgID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get FSM instance: %w", err)
+	}
+	participantID, err := fsmInstance.GetIDByUsername(c.GetUsername())
+	if err != nil {
+		return "", fmt.Errorf("failed to get participantID: %w", err)
+	}
+
+	messageDataSign := requests.SigningProposalStartRequest{
+		SigningID:     uuid.New().String(),
+		ParticipantId: participantID,
+		SrcPayload:    data,
+		SigningObject: signingObject,
+		CreatedAt:     time.Now(),
+	}
+	messageDataSignBz, err := json.Marshal(messageDataSign)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SigningProposalStartRequest: %w", err)
+	}
+
+	message, err := c.buildMessage(dkgID, sipf.EventSigningStart, messageDataSignBz)
+	if err != nil {
+		return "", fmt.Errorf("failed to build message: %w", err)
+	}
+	if err = c.SendMessage(*message); err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+	return messageDataSign.SigningID, nil
+}
+
+// getFSMStates returns states of all DKG rounds served by the client
+func (c *BaseClient) getFSMStates() (map[string]string, error) {
+	fsmInstances, err := c.state.GetAllFSM()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all FSM instances: %w", err)
+	}
+	fsmInstancesStates := make(map[string]string, len(fsmInstances))
+	for k, v := range fsmInstances {
+		state, err := v.State()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get FSM state: %w", err)
+		}
+		fsmInstancesStates[k] = state.String()
+	}
+	return fsmInstancesStates, nil
+}
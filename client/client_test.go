@@ -11,12 +11,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/share"
+	"github.com/corestario/kyber/sign/bls"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/lidofinance/dc4bc/client"
 	"github.com/lidofinance/dc4bc/client/types"
 	"github.com/lidofinance/dc4bc/fsm/state_machines"
 	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
 	"github.com/lidofinance/dc4bc/fsm/types/requests"
 	"github.com/lidofinance/dc4bc/mocks/clientMocks"
 	"github.com/lidofinance/dc4bc/mocks/qrMocks"
@@ -52,6 +57,10 @@ func TestClient_ProcessMessage(t *testing.T) {
 		qrProcessor,
 	)
 	req.NoError(err)
+	// the unit of work is not tested here, so the changes are made right to the mock
+	state.EXPECT().Atomic(gomock.Any()).AnyTimes().DoAndReturn(func(fn func(client.State) error) error {
+		return fn(state)
+	})
 
 	t.Run("test_process_dkg_init", func(t *testing.T) {
 		fsm, err := state_machines.Create(dkgRoundID)
@@ -99,6 +108,7 @@ func TestClient_ProcessMessage(t *testing.T) {
 		}
 		message.Signature = ed25519.Sign(senderKeyPair.Priv, message.Bytes())
 
+		state.EXPECT().PutFSMTransition(gomock.Any()).Times(1).Return(nil)
 		state.EXPECT().SaveFSM(gomock.Any(), gomock.Any()).Times(1).Return(nil)
 		state.EXPECT().PutOperation(gomock.Any()).Times(1).Return(nil)
 
@@ -107,6 +117,199 @@ func TestClient_ProcessMessage(t *testing.T) {
 	})
 }
 
+func TestClient_ProcessReconstructedSignature(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		req  = require.New(t)
+		ctrl = gomock.NewController(t)
+	)
+	defer ctrl.Finish()
+
+	userName := "user_name"
+	dkgRoundID := "dkg_round_id"
+	signingID := "signing_id"
+	srcPayload := []byte("message to sign")
+	state := clientMocks.NewMockState(ctrl)
+	keyStore := clientMocks.NewMockKeyStore(ctrl)
+	stg := storageMocks.NewMockStorage(ctrl)
+	qrProcessor := qrMocks.NewMockProcessor(ctrl)
+
+	keyStore.EXPECT().LoadKeys(userName, "").Times(1).Return(client.NewKeyPair(), nil)
+
+	clt, err := client.NewClient(
+		ctx,
+		userName,
+		state,
+		stg,
+		keyStore,
+		qrProcessor,
+	)
+	req.NoError(err)
+	// the unit of work is not tested here, so the changes are made right to the mock
+	state.EXPECT().Atomic(gomock.Any()).AnyTimes().DoAndReturn(func(fn func(client.State) error) error {
+		return fn(state)
+	})
+
+	senderKeyPair := client.NewKeyPair()
+	senderAddr := senderKeyPair.GetAddr()
+	fsmInstance, err := state_machines.Create(dkgRoundID)
+	req.NoError(err)
+	_, _, err = fsmInstance.Do(spf.EventInitProposal, requests.SignatureProposalParticipantsListRequest{
+		Participants: []*requests.SignatureProposalParticipantsEntry{
+			{Username: senderAddr, PubKey: senderKeyPair.Pub, DkgPubKey: make([]byte, 128)},
+			{Username: "111", PubKey: client.NewKeyPair().Pub, DkgPubKey: make([]byte, 128)},
+		},
+		CreatedAt:        time.Now(),
+		SigningThreshold: 2,
+	})
+	req.NoError(err)
+	fsmInstance = withSigningSession(t, fsmInstance, signingID, srcPayload)
+
+	suite := bls12381.NewBLS12381Suite(nil)
+	priPoly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(suite.Point().Base()).Info()
+	commitsBz := make([][]byte, 0, len(commits))
+	for _, commit := range commits {
+		commitBz, err := commit.MarshalBinary()
+		req.NoError(err)
+		commitsBz = append(commitsBz, commitBz)
+	}
+	validSignature, err := bls.Sign(suite.(pairing.Suite), priPoly.Secret(), srcPayload)
+	req.NoError(err)
+
+	buildMessage := func(signature []byte, payload []byte) storage.Message {
+		signatureBz, err := json.Marshal(types.ReconstructedSignature{
+			SigningID:  signingID,
+			SrcPayload: payload,
+			Signature:  signature,
+		})
+		req.NoError(err)
+		message := storage.Message{
+			ID:         uuid.New().String(),
+			DkgRoundID: dkgRoundID,
+			Event:      string(types.SignatureReconstructed),
+			Data:       signatureBz,
+			SenderAddr: senderAddr,
+		}
+		message.Signature = ed25519.Sign(senderKeyPair.Priv, message.Bytes())
+		return message
+	}
+
+	t.Run("test_valid_signature", func(t *testing.T) {
+		state.EXPECT().LoadFSM(dkgRoundID).Times(1).Return(fsmInstance, true, nil)
+		state.EXPECT().LoadPubPolyCommitments(dkgRoundID).Times(1).Return(commitsBz, nil)
+		// the record saved on the signing start has no signature to conflict with
+		state.EXPECT().GetSignatures(dkgRoundID).Times(1).Return(map[string][]types.ReconstructedSignature{
+			signingID: {{SigningID: signingID, SrcPayload: srcPayload, Username: "111", DKGRoundID: dkgRoundID}},
+		}, nil)
+		state.EXPECT().SaveSignature(gomock.Any()).Times(1).DoAndReturn(
+			func(signature types.ReconstructedSignature) error {
+				req.Empty(signature.VerificationError)
+				req.Equal(senderAddr, signature.Username)
+				return nil
+			})
+
+		req.NoError(clt.ProcessMessage(buildMessage(validSignature, srcPayload)))
+	})
+
+	t.Run("test_invalid_signature", func(t *testing.T) {
+		invalidSignature, err := bls.Sign(suite.(pairing.Suite), suite.Scalar().Pick(suite.RandomStream()),
+			srcPayload)
+		req.NoError(err)
+
+		state.EXPECT().LoadFSM(dkgRoundID).Times(1).Return(fsmInstance, true, nil)
+		state.EXPECT().LoadPubPolyCommitments(dkgRoundID).Times(1).Return(commitsBz, nil)
+		state.EXPECT().SaveSignature(gomock.Any()).Times(1).DoAndReturn(
+			func(signature types.ReconstructedSignature) error {
+				req.NotEmpty(signature.VerificationError)
+				return nil
+			})
+
+		req.NoError(clt.ProcessMessage(buildMessage(invalidSignature, srcPayload)))
+	})
+
+	t.Run("test_payload_of_other_signing", func(t *testing.T) {
+		// the signature is valid, but it signs the data which was never proposed in the signing
+		otherPayload := []byte("other message to sign")
+		otherSignature, err := bls.Sign(suite.(pairing.Suite), priPoly.Secret(), otherPayload)
+		req.NoError(err)
+
+		state.EXPECT().LoadFSM(dkgRoundID).Times(1).Return(fsmInstance, true, nil)
+		state.EXPECT().SaveSignature(gomock.Any()).Times(1).DoAndReturn(
+			func(signature types.ReconstructedSignature) error {
+				req.Contains(signature.VerificationError, "payload of the signing proposal")
+				return nil
+			})
+
+		req.NoError(clt.ProcessMessage(buildMessage(otherSignature, otherPayload)))
+	})
+
+	t.Run("test_unknown_signing", func(t *testing.T) {
+		unknownFSMInstance, err := state_machines.FromDump(mustDump(t, fsmInstance))
+		req.NoError(err)
+		unknownFSMInstance.FSMDump().Payload.SigningProposals = nil
+
+		state.EXPECT().LoadFSM(dkgRoundID).Times(1).Return(unknownFSMInstance, true, nil)
+		state.EXPECT().SaveSignature(gomock.Any()).Times(1).DoAndReturn(
+			func(signature types.ReconstructedSignature) error {
+				req.Contains(signature.VerificationError, "not found")
+				return nil
+			})
+
+		req.NoError(clt.ProcessMessage(buildMessage(validSignature, srcPayload)))
+	})
+
+	t.Run("test_conflicting_signature", func(t *testing.T) {
+		state.EXPECT().LoadFSM(dkgRoundID).Times(1).Return(fsmInstance, true, nil)
+		state.EXPECT().LoadPubPolyCommitments(dkgRoundID).Times(1).Return(commitsBz, nil)
+		state.EXPECT().GetSignatures(dkgRoundID).Times(1).Return(map[string][]types.ReconstructedSignature{
+			signingID: {{SigningID: signingID, SrcPayload: srcPayload, Signature: []byte("other signature"),
+				Username: "111", DKGRoundID: dkgRoundID}},
+		}, nil)
+		state.EXPECT().SaveSignature(gomock.Any()).Times(1).DoAndReturn(
+			func(signature types.ReconstructedSignature) error {
+				req.Contains(signature.VerificationError, "conflicts with the signature")
+				return nil
+			})
+
+		req.NoError(clt.ProcessMessage(buildMessage(validSignature, srcPayload)))
+	})
+
+	t.Run("test_corrupt_message", func(t *testing.T) {
+		message := buildMessage(validSignature, srcPayload)
+		message.Signature = ed25519.Sign(client.NewKeyPair().Priv, message.Bytes())
+
+		state.EXPECT().LoadFSM(dkgRoundID).Times(1).Return(fsmInstance, true, nil)
+
+		req.Error(clt.ProcessMessage(message))
+	})
+}
+
+func mustDump(t *testing.T, fsmInstance *state_machines.FSMInstance) []byte {
+	dump, err := fsmInstance.Dump()
+	require.NoError(t, err)
+	return dump
+}
+
+// withSigningSession returns a copy of the FSM with a collected signing session of the given payload
+func withSigningSession(t *testing.T, fsmInstance *state_machines.FSMInstance, signingID string,
+	srcPayload []byte) *state_machines.FSMInstance {
+	var dump map[string]interface{}
+	require.NoError(t, json.Unmarshal(mustDump(t, fsmInstance), &dump))
+	dump["Payload"].(map[string]interface{})["SigningProposals"] = map[string]interface{}{
+		signingID: map[string]interface{}{
+			"State":   sipf.StateSigningPartialSignsCollected,
+			"Payload": map[string]interface{}{"SigningId": signingID, "SrcPayload": srcPayload},
+		},
+	}
+	dumpBz, err := json.Marshal(dump)
+	require.NoError(t, err)
+
+	fsmInstance, err = state_machines.FromDump(dumpBz)
+	require.NoError(t, err)
+	return fsmInstance
+}
+
 func TestClient_GetOperationsList(t *testing.T) {
 	var (
 		ctx  = context.Background()
@@ -0,0 +1,335 @@
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+)
+
+const (
+	dashboardPath       = "/dashboard"
+	dashboardQRPath     = "/dashboard/qr"
+	dashboardUploadPath = "/dashboard/upload"
+
+	// maxUploadedOperationSize limits the size of a processed operation uploaded through the dashboard
+	maxUploadedOperationSize = 32 << 20
+)
+
+type dashboardRound struct {
+	api.RoundStatus
+	Operations []*types.Operation
+	Signatures []types.ReconstructedSignature
+}
+
+type dashboardData struct {
+	Username string
+	Error    string
+	Message  string
+	Rounds   []dashboardRound
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02 15:04:05 MST")
+	},
+	"printable": printableData,
+	"hex":       hex.EncodeToString,
+}).Parse(dashboardHTML))
+
+// getDashboardData returns all DKG rounds known to the client with their operations and signatures,
+// the most recent rounds go first
+func (c *BaseClient) getDashboardData() (*dashboardData, error) {
+	roundStatuses, err := c.GetRoundStatus("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get round statuses: %w", err)
+	}
+	operations, err := c.state.GetOperations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operations: %w", err)
+	}
+
+	data := &dashboardData{Username: c.GetUsername()}
+	for _, roundStatus := range roundStatuses {
+		round := dashboardRound{RoundStatus: roundStatus}
+		for _, operationID := range roundStatus.PendingOperations {
+			// the operation could be handled after the status was made
+			if operation, ok := operations[operationID]; ok {
+				round.Operations = append(round.Operations, operation)
+			}
+		}
+
+		signatures, err := c.state.GetSignatures(roundStatus.DKGRoundID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get signatures for DKG round %s: %w", roundStatus.DKGRoundID, err)
+		}
+		signingIDs := make([]string, 0, len(signatures))
+		for signingID := range signatures {
+			signingIDs = append(signingIDs, signingID)
+		}
+		sort.Strings(signingIDs)
+		for _, signingID := range signingIDs {
+			round.Signatures = append(round.Signatures, signatures[signingID]...)
+		}
+
+		data.Rounds = append(data.Rounds, round)
+	}
+
+	return data, nil
+}
+
+func (c *BaseClient) dashboardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+
+	data, err := c.getDashboardData()
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get dashboard data: %v", err))
+		return
+	}
+	data.Error = r.URL.Query().Get("error")
+	data.Message = r.URL.Query().Get("message")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, data); err != nil {
+		c.Logger.Log("Failed to render dashboard: %v", err)
+	}
+}
+
+// dashboardQRHandler returns the animated QR of the operation, the same one as GetOperationQRPath writes
+func (c *BaseClient) dashboardQRHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+
+	qrPath, err := c.GetOperationQRPath(r.URL.Query().Get("operationID"))
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get operation QR path: %v", err))
+		return
+	}
+	qrGIF, err := ioutil.ReadFile(qrPath)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to read operation QR: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(qrGIF)))
+	rawResponse(w, qrGIF)
+}
+
+// dashboardUploadHandler handles the processed operation uploaded from the dashboard form
+// and redirects back to the dashboard with the result
+func (c *BaseClient) dashboardUploadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+
+	// the dashboard is the only page allowed to post the form, otherwise any site opened in the browser of
+	// the operator could make it upload an operation
+	if err := checkSameOrigin(r); err != nil {
+		errorResponse(w, http.StatusForbidden, fmt.Sprintf("cross-origin request is refused: %v", err))
+		return
+	}
+
+	redirect := func(key, value string) {
+		http.Redirect(w, r, dashboardPath+"?"+key+"="+url.QueryEscape(value), http.StatusSeeOther)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadedOperationSize)
+	file, _, err := r.FormFile("operation")
+	if err != nil {
+		redirect("error", fmt.Sprintf("failed to read uploaded operation: %v", err))
+		return
+	}
+	defer file.Close()
+
+	operationJSON, err := ioutil.ReadAll(file)
+	if err != nil {
+		redirect("error", fmt.Sprintf("failed to read uploaded operation: %v", err))
+		return
+	}
+
+	var operation types.Operation
+	if err = json.Unmarshal(operationJSON, &operation); err != nil {
+		redirect("error", fmt.Sprintf("failed to unmarshal operation: %v", err))
+		return
+	}
+
+	if err = c.handleProcessedOperation(operation); err != nil {
+		redirect("error", fmt.Sprintf("failed to handle processed operation: %v", err))
+		return
+	}
+
+	redirect("message", fmt.Sprintf("Operation %s is handled", operation.ID))
+}
+
+// checkSameOrigin makes sure that the request is made by a page served from the same host. Browsers set Origin
+// for POST requests, Referer is checked if Origin is not sent, requests without both headers are refused
+func checkSameOrigin(r *http.Request) error {
+	source := r.Header.Get("Origin")
+	if source == "" {
+		source = r.Header.Get("Referer")
+	}
+	if source == "" {
+		return errors.New("neither Origin nor Referer header is set")
+	}
+
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return fmt.Errorf("failed to parse request source: %w", err)
+	}
+	if sourceURL.Host != r.Host {
+		return fmt.Errorf("request source %s does not match host %s", source, r.Host)
+	}
+	return nil
+}
+
+// printableData returns the data as a string if it's a printable text, otherwise it's hex-encoded
+func printableData(data []byte) string {
+	if !utf8.Valid(data) {
+		return hex.EncodeToString(data)
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return hex.EncodeToString(data)
+		}
+	}
+	return string(data)
+}
+
+const dashboardHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>dc4bc &ndash; {{.Username}}</title>
+  <style>
+    body { font-family: 'Helvetica Neue', 'Calibri', Arial, sans-serif; margin: 0; background: #eceff1; color: #263238; }
+    header { background: #607d8b; color: #fff; padding: 10px 20px; }
+    main { padding: 0 20px 20px; }
+    section.round { background: #fff; margin-top: 20px; padding: 10px 20px; border-radius: 4px; }
+    h2, h3, h4 { font-weight: normal; }
+    table { border-collapse: collapse; margin-bottom: 10px; }
+    td, th { border-bottom: 1px solid #cfd8dc; padding: 4px 10px; text-align: left; vertical-align: top; }
+    .mono { font-family: monospace; word-break: break-all; }
+    .done { color: #2e7d32; }
+    .failed { color: #c62828; }
+    .flash { padding: 10px 20px; margin-top: 20px; border-radius: 4px; background: #c8e6c9; }
+    .flash.failed { background: #ffcdd2; }
+    .operation { display: flex; gap: 20px; align-items: flex-start; margin-bottom: 10px; }
+  </style>
+</head>
+<body>
+<header><h1>dc4bc node of {{.Username}}</h1></header>
+<main>
+  {{if .Error}}<div class="flash failed">{{.Error}}</div>{{end}}
+  {{if .Message}}<div class="flash">{{.Message}}</div>{{end}}
+
+  <section class="round">
+    <h2>Upload processed operation</h2>
+    <form method="post" action="/dashboard/upload" enctype="multipart/form-data">
+      <input type="file" name="operation" accept="application/json,.json" required>
+      <button type="submit">Upload</button>
+    </form>
+  </section>
+
+  {{range .Rounds}}
+  <section class="round">
+    <h2>DKG round <span class="mono">{{.DKGRoundID}}</span></h2>
+    <p>State: <b>{{.State}}</b>{{if .Threshold}}, threshold: {{.Threshold}}{{end}}, created: {{formatTime .CreatedAt}}</p>
+    {{if .AbortedBy}}<p class="failed">Aborted by {{.AbortedBy}}: {{.AbortReason}}</p>{{end}}
+    {{if .NextAction}}<p>Next action: <b>{{.NextAction}}</b></p>{{end}}
+
+    {{range .Phases}}{{template "phase" .}}{{end}}
+
+    {{if .Operations}}
+    <h3>Pending operations</h3>
+    {{range .Operations}}
+    <div class="operation">
+      <img src="/dashboard/qr?operationID={{.ID}}" alt="QR code of operation {{.ID}}">
+      <div>
+        <p>ID: <span class="mono">{{.ID}}</span></p>
+        <p>Type: {{.Type}}</p>
+        <p>Created: {{formatTime .CreatedAt}}</p>
+        <p>Scan the QR code with the airgapped machine, then upload the processed operation above.</p>
+      </div>
+    </div>
+    {{end}}
+    {{end}}
+
+    {{if .Signings}}
+    <h3>Signings</h3>
+    {{range .Signings}}
+    <h4>Signing <span class="mono">{{.SigningID}}</span>: {{.State}}</h4>
+    <p>Data: <span class="mono">{{printable .Data}}</span></p>
+    {{if .AbortedBy}}<p class="failed">Aborted by {{.AbortedBy}}: {{.AbortReason}}</p>{{end}}
+    {{if .NextAction}}<p>Next action: <b>{{.NextAction}}</b></p>{{end}}
+    {{template "phase" .Phase}}
+    {{end}}
+    {{end}}
+
+    {{if .Signatures}}
+    <h3>Reconstructed signatures</h3>
+    <table>
+      <tr><th>Signing ID</th><th>Broadcasted by</th><th>Data</th><th>Signature</th><th>Verification</th></tr>
+      {{range .Signatures}}
+      <tr>
+        <td class="mono">{{.SigningID}}</td>
+        <td>{{.Username}}</td>
+        <td class="mono">{{printable .SrcPayload}}</td>
+        <td class="mono">{{hex .Signature}}</td>
+        <td>{{if .VerificationError}}<span class="failed">{{.VerificationError}}</span>{{else}}<span class="done">valid</span>{{end}}</td>
+      </tr>
+      {{end}}
+    </table>
+    {{end}}
+  </section>
+  {{else}}
+  <section class="round"><p>There are no DKG rounds yet.</p></section>
+  {{end}}
+</main>
+<script>
+  // reload the page when the state of the node changes
+  if (window.EventSource) {
+    var events = new EventSource("/watchEvents");
+    events.onmessage = function () { window.location.reload(); };
+    ["fsm_state_changed", "new_operation", "signature_reconstructed"].forEach(function (type) {
+      events.addEventListener(type, function () { window.location.reload(); });
+    });
+  }
+</script>
+</body>
+</html>
+
+{{define "phase"}}
+<h4>{{.Name}}: {{.Acted}}/{{len .Participants}} acted, deadline: {{formatTime .Deadline}}</h4>
+<table>
+  <tr><th>Participant</th><th>Status</th><th>Error</th></tr>
+  {{range .Participants}}
+  <tr>
+    <td>{{.Username}}</td>
+    <td class="{{if .Acted}}done{{end}}">{{.Status}}</td>
+    <td>{{.Error}}</td>
+  </tr>
+  {{end}}
+</table>
+{{end}}
+`
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	"github.com/lidofinance/dc4bc/qr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_Dashboard(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_dashboard")
+	defer round.close()
+
+	baseClient := round.newClient("user_name")
+	baseClient.qrProcessor = qr.NewCameraProcessor()
+	state := baseClient.state
+
+	dkgRoundID := "dkg_round_id"
+	fsmInstance, err := state_machines.Create(dkgRoundID)
+	req.NoError(err)
+	fsmDump, err := fsmInstance.Dump()
+	req.NoError(err)
+	req.NoError(state.SaveFSM(dkgRoundID, fsmDump))
+
+	operation := types.NewOperation(dkgRoundID, []byte("payload"), "state_sig_proposal_await_participants_confirmations")
+	req.NoError(state.PutOperation(operation))
+	req.NoError(state.SaveSignature(types.ReconstructedSignature{
+		SigningID:  "signing_id",
+		SrcPayload: []byte("message to sign"),
+		Signature:  []byte{0xde, 0xad},
+		Username:   "alice",
+		DKGRoundID: dkgRoundID,
+	}))
+	req.NoError(state.SaveSignature(types.ReconstructedSignature{
+		SigningID:         "signing_id",
+		SrcPayload:        []byte{0xff, 0x00},
+		Username:          "mallory",
+		DKGRoundID:        dkgRoundID,
+		VerificationError: "signature is not valid",
+	}))
+
+	recorder := httptest.NewRecorder()
+	baseClient.dashboardHandler(recorder, httptest.NewRequest(http.MethodGet, dashboardPath+"?error=bad+upload", nil))
+	req.Equal(http.StatusOK, recorder.Code)
+	page := recorder.Body.String()
+	req.Contains(page, dkgRoundID)
+	req.Contains(page, "bad upload")
+	req.Contains(page, dashboardQRPath+"?operationID="+operation.ID)
+	req.Contains(page, "message to sign")
+	req.Contains(page, "dead")
+	req.Contains(page, "signature is not valid")
+	req.Contains(page, "ff00")
+
+	recorder = httptest.NewRecorder()
+	baseClient.dashboardQRHandler(recorder, httptest.NewRequest(http.MethodGet, dashboardQRPath+"?operationID="+operation.ID, nil))
+	req.Equal(http.StatusOK, recorder.Code)
+	req.Equal("image/gif", recorder.Header().Get("Content-Type"))
+	req.True(bytes.HasPrefix(recorder.Body.Bytes(), []byte("GIF89a")))
+
+	// the operation does not match the stored one, so it's rejected
+	processedOperation := *operation
+	processedOperation.Payload = []byte("another payload")
+	processedOperationJSON, err := json.Marshal(processedOperation)
+	req.NoError(err)
+
+	newUploadRequest := func(header, value string) *http.Request {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("operation", "operation.json")
+		req.NoError(err)
+		_, err = part.Write(processedOperationJSON)
+		req.NoError(err)
+		req.NoError(form.Close())
+
+		uploadRequest := httptest.NewRequest(http.MethodPost, dashboardUploadPath, &body)
+		uploadRequest.Header.Set("Content-Type", form.FormDataContentType())
+		if header != "" {
+			uploadRequest.Header.Set(header, value)
+		}
+		return uploadRequest
+	}
+
+	// the form posted by other sites or without the source of the request is refused
+	for _, source := range [][2]string{
+		{"Origin", "https://evil.example.org"},
+		{"Origin", "null"},
+		{"Referer", "https://evil.example.org/dashboard"},
+		{"", ""},
+	} {
+		recorder = httptest.NewRecorder()
+		baseClient.dashboardUploadHandler(recorder, newUploadRequest(source[0], source[1]))
+		req.Equal(http.StatusForbidden, recorder.Code, source)
+	}
+
+	for _, source := range [][2]string{
+		{"Origin", "http://example.com"},
+		{"Referer", "http://example.com/dashboard?message=ok"},
+	} {
+		recorder = httptest.NewRecorder()
+		baseClient.dashboardUploadHandler(recorder, newUploadRequest(source[0], source[1]))
+		req.Equal(http.StatusSeeOther, recorder.Code)
+		location, err := url.Parse(recorder.Header().Get("Location"))
+		req.NoError(err)
+		req.Equal(dashboardPath, location.Path)
+		req.Contains(location.Query().Get("error"), "processed operation does not match stored operation")
+	}
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// maxDeadLetterAttempts limits automatic retries of a message which arrived too early,
+// the message still can be retried by hand
+const maxDeadLetterAttempts = 10
+
+// isRetryable reports whether the message failed because it arrived too early and may be applied later,
+// e.g. a confirmation for a state the FSM has not reached yet
+func isRetryable(err error) bool {
+	return errors.Is(err, fsm.ErrEventNotAvailable)
+}
+
+// saveDeadLetter puts the failed message to the dead-letter list together with the offset of the next message,
+// so the message does not block the log, but it's not lost either
+func (c *BaseClient) saveDeadLetter(message storage.Message, processErr error) error {
+	now := time.Now()
+	letter := types.DeadLetter{
+		Message:     message,
+		Error:       processErr.Error(),
+		Retryable:   isRetryable(processErr),
+		Attempts:    1,
+		CreatedAt:   now,
+		LastAttempt: now,
+	}
+	return c.state.Atomic(func(tx State) error {
+		if err := tx.PutDeadLetter(letter); err != nil {
+			return fmt.Errorf("failed to put dead letter: %w", err)
+		}
+		return advanceLog(tx, message)
+	})
+}
+
+// retryDeadLetter processes the failed message again, the message is removed from the dead-letter list
+// in the same unit of work. Otherwise the letter is updated with the new error
+func (c *BaseClient) retryDeadLetter(letter types.DeadLetter) error {
+	processErr := c.processMessageAtomically(letter.Message, func(tx State) error {
+		return tx.DeleteDeadLetter(letter.Message.ID)
+	})
+	if processErr == nil {
+		return nil
+	}
+
+	letter.Error = processErr.Error()
+	letter.Retryable = isRetryable(processErr)
+	letter.Attempts++
+	letter.LastAttempt = time.Now()
+	if err := c.state.PutDeadLetter(letter); err != nil {
+		return fmt.Errorf("failed to update dead letter: %w", err)
+	}
+
+	return processErr
+}
+
+// retryDeadLetters retries the messages which arrived too early, the just processed message could be
+// the one they wait for. A successful retry could unblock other messages, so they are retried again
+func (c *BaseClient) retryDeadLetters() {
+	for retried := true; retried; {
+		retried = false
+
+		letters, err := c.state.GetDeadLetters()
+		if err != nil {
+			c.Logger.Log("Failed to get dead letters: %v", err)
+			return
+		}
+		for _, letter := range letters {
+			if !letter.Retryable || letter.Attempts >= maxDeadLetterAttempts {
+				continue
+			}
+			if err = c.retryDeadLetter(letter); err != nil {
+				c.Logger.Log("Failed to retry message with offset %d: %v", letter.Message.Offset, err)
+				continue
+			}
+			c.Logger.Log("Successfully retried message with offset %d, type %s", letter.Message.Offset,
+				letter.Message.Event)
+			retried = true
+		}
+	}
+}
+
+// GetDeadLetters returns the messages which the client failed to process ordered by their offsets
+func (c *BaseClient) GetDeadLetters() ([]types.DeadLetter, error) {
+	return c.state.GetDeadLetters()
+}
+
+func (c *BaseClient) getDeadLetter(messageID string) (types.DeadLetter, error) {
+	letters, err := c.state.GetDeadLetters()
+	if err != nil {
+		return types.DeadLetter{}, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+	for _, letter := range letters {
+		if letter.Message.ID == messageID {
+			return letter, nil
+		}
+	}
+	return types.DeadLetter{}, fmt.Errorf("dead letter %s not found", messageID)
+}
+
+// RetryDeadLetter processes the failed message again and removes it from the dead-letter list on success
+func (c *BaseClient) RetryDeadLetter(messageID string) error {
+	letter, err := c.getDeadLetter(messageID)
+	if err != nil {
+		return err
+	}
+	if err = c.retryDeadLetter(letter); err != nil {
+		return fmt.Errorf("failed to process message: %w", err)
+	}
+	return nil
+}
+
+// DiscardDeadLetter removes the failed message from the dead-letter list without processing it
+func (c *BaseClient) DiscardDeadLetter(messageID string) error {
+	if _, err := c.getDeadLetter(messageID); err != nil {
+		return err
+	}
+	return c.state.DeleteDeadLetter(messageID)
+}
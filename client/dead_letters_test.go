@@ -0,0 +1,115 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_DeadLetters(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_dead_letters")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+	baseClient.SkipCommKeysVerification = true
+	state := baseClient.state
+
+	dkgRoundID := "dkg_round_id"
+	initRequest := round.initRequest
+	initData, err := json.Marshal(initRequest)
+	req.NoError(err)
+
+	// participant IDs are known only after the proposal
+	fsmInstance, err := state_machines.Create(dkgRoundID)
+	req.NoError(err)
+	_, _, err = fsmInstance.Do(spf.EventInitProposal, initRequest)
+	req.NoError(err)
+	bobID, err := fsmInstance.GetIDByUsername("bob")
+	req.NoError(err)
+	confirmData, err := json.Marshal(requests.SignatureProposalParticipantRequest{
+		ParticipantId: bobID,
+		CreatedAt:     time.Now(),
+	})
+	req.NoError(err)
+
+	// the confirmation arrives before the proposal, so it's retried when the proposal is processed
+	baseClient.handleMessage(storage.Message{
+		ID:         "confirm",
+		DkgRoundID: dkgRoundID,
+		Offset:     0,
+		Event:      string(spf.EventConfirmSignatureProposal),
+		Data:       confirmData,
+		SenderAddr: "bob",
+	})
+	letters, err := baseClient.GetDeadLetters()
+	req.NoError(err)
+	req.Len(letters, 1)
+	req.True(letters[0].Retryable)
+	req.Equal(1, letters[0].Attempts)
+	offset, err := state.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(1), offset)
+
+	baseClient.handleMessage(storage.Message{
+		ID:         "init",
+		DkgRoundID: dkgRoundID,
+		Offset:     1,
+		Event:      string(spf.EventInitProposal),
+		Data:       initData,
+		SenderAddr: "alice",
+	})
+	letters, err = baseClient.GetDeadLetters()
+	req.NoError(err)
+	req.Empty(letters)
+	statuses, err := baseClient.GetRoundStatus(dkgRoundID)
+	req.NoError(err)
+	req.ElementsMatch([]string{"alice", "carol"}, statuses[0].Phases[0].Waiting())
+
+	// a broken message is not retried automatically
+	baseClient.handleMessage(storage.Message{
+		ID:         "broken",
+		DkgRoundID: dkgRoundID,
+		Offset:     2,
+		Event:      string(spf.EventConfirmSignatureProposal),
+		Data:       []byte("not a request"),
+		SenderAddr: "carol",
+	})
+	offset, err = state.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(3), offset)
+
+	recorder := httptest.NewRecorder()
+	baseClient.getDeadLettersHandler(recorder, httptest.NewRequest(http.MethodGet, api.EndpointGetDeadLetters, nil))
+	req.Equal(http.StatusOK, recorder.Code)
+	var response struct {
+		Result []types.DeadLetter
+	}
+	req.NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+	req.Len(response.Result, 1)
+	req.Equal("broken", response.Result[0].Message.ID)
+	req.False(response.Result[0].Retryable)
+
+	req.Error(baseClient.RetryDeadLetter("broken"))
+	letters, err = baseClient.GetDeadLetters()
+	req.NoError(err)
+	req.Len(letters, 1)
+	req.Equal(2, letters[0].Attempts)
+
+	req.Error(baseClient.DiscardDeadLetter("unknown"))
+	req.NoError(baseClient.DiscardDeadLetter("broken"))
+	letters, err = baseClient.GetDeadLetters()
+	req.NoError(err)
+	req.Empty(letters)
+}
@@ -0,0 +1,238 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// equivocationSteps maps events to the steps of a DKG round a participant sends one message for.
+// An error or a decline is another answer to the same step
+var equivocationSteps = map[fsm.Event]string{
+	spf.EventConfirmSignatureProposal:         "proposal_confirmation",
+	spf.EventDeclineProposal:                  "proposal_confirmation",
+	dpf.EventDKGCommitConfirmationReceived:    "dkg_commit",
+	dpf.EventDKGCommitConfirmationError:       "dkg_commit",
+	dpf.EventDKGDealConfirmationReceived:      "dkg_deal",
+	dpf.EventDKGDealConfirmationError:         "dkg_deal",
+	dpf.EventDKGResponseConfirmationReceived:  "dkg_response",
+	dpf.EventDKGResponseConfirmationError:     "dkg_response",
+	dpf.EventDKGMasterKeyConfirmationReceived: "dkg_master_key",
+	dpf.EventDKGMasterKeyConfirmationError:    "dkg_master_key",
+	sipf.EventConfirmSigningConfirmation:      "signing_confirmation",
+	sipf.EventDeclineSigningConfirmation:      "signing_confirmation",
+	sipf.EventSigningPartialSignReceived:      "signing_partial_sign",
+	sipf.EventSigningPartialSignError:         "signing_partial_sign",
+}
+
+// equivocationStep returns the step of the DKG round the message is sent for. Deals are sent to every
+// participant separately, and there is a step for every signing
+func equivocationStep(message storage.Message) (string, bool) {
+	step, ok := equivocationSteps[fsm.Event(message.Event)]
+	if !ok {
+		return "", false
+	}
+	if message.RecipientAddr != "" {
+		step += ":" + message.RecipientAddr
+	}
+	if fsmReq, err := types.FSMRequestFromMessage(message); err == nil {
+		if signingID, err := state_machines.GetSigningID(fsmReq); err == nil {
+			step += ":" + signingID
+		}
+	}
+	return step, true
+}
+
+// checkEquivocation compares the message with the first message of the sender for the same step. Messages with
+// different data signed by the same participant are saved as an evidence, an alert is published and the evidence
+// is broadcast if BroadcastEquivocations is set. It's safe to check the same message again
+func (c *BaseClient) checkEquivocation(message storage.Message) {
+	var (
+		equivocation *types.Equivocation
+		event        api.Event
+	)
+	err := c.state.Atomic(func(tx State) (err error) {
+		if equivocation, err = c.detectEquivocation(tx, message); err != nil || equivocation == nil {
+			return err
+		}
+		event = c.equivocationEvent(message, *equivocation)
+		if c.webhooks != nil {
+			return c.webhooks.enqueue(tx, event)
+		}
+		return nil
+	})
+	if err != nil {
+		c.Logger.Log("Failed to check message with offset %d for equivocation: %v", message.Offset, err)
+		return
+	}
+	if equivocation == nil {
+		return
+	}
+
+	c.events.publish(event)
+	if c.webhooks != nil {
+		c.webhooks.notify()
+	}
+	if c.BroadcastEquivocations {
+		if err = c.broadcastEquivocation(*equivocation); err != nil {
+			c.Logger.Log("Failed to broadcast equivocation evidence: %v", err)
+		}
+	}
+}
+
+// detectEquivocation returns a new evidence if the message conflicts with the first message of the sender
+// for the same step
+func (c *BaseClient) detectEquivocation(state State, message storage.Message) (*types.Equivocation, error) {
+	step, ok := equivocationStep(message)
+	if !ok {
+		return nil, nil
+	}
+	fsmInstance, ok, err := state.LoadFSM(message.DkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load FSM: %w", err)
+	}
+	// messages with invalid signatures are not evidences, they are rejected when they are processed
+	if !ok || c.verifyMessage(fsmInstance, message) != nil {
+		return nil, nil
+	}
+
+	first, err := state.GetStepMessage(message.DkgRoundID, message.SenderAddr, step)
+	if err != nil {
+		return nil, err
+	}
+	if first == nil {
+		return nil, state.PutStepMessage(message.DkgRoundID, message.SenderAddr, step, message)
+	}
+	// the same data could be sent again with another commitment to the log
+	if first.ID == message.ID || bytes.Equal(first.Data, message.Data) {
+		return nil, nil
+	}
+
+	known, err := state.GetEquivocation(message.DkgRoundID, message.SenderAddr, step)
+	if err != nil || known != nil {
+		return nil, err
+	}
+	equivocation := types.Equivocation{
+		DKGRoundID: message.DkgRoundID,
+		Username:   message.SenderAddr,
+		Step:       step,
+		First:      *first,
+		Second:     message,
+		DetectedAt: time.Now(),
+	}
+	if err = state.PutEquivocation(equivocation); err != nil {
+		return nil, err
+	}
+
+	return &equivocation, nil
+}
+
+// equivocationEvent logs the equivocation and returns an alert about it
+func (c *BaseClient) equivocationEvent(message storage.Message, equivocation types.Equivocation) api.Event {
+	description := fmt.Sprintf("participant %s has signed different messages %s and %s for step %s",
+		equivocation.Username, equivocation.First.ID, equivocation.Second.ID, equivocation.Step)
+	if equivocation.Reporter != "" {
+		description += ", reported by " + equivocation.Reporter
+	}
+	c.Logger.Log("Equivocation in DKG round %s: %s", equivocation.DKGRoundID, description)
+
+	event := messageEvent(api.EventEquivocationDetected, message)
+	event.Error = description
+	return event
+}
+
+// broadcastEquivocation sends the evidence to the log, so the participants who have seen only one
+// of the messages learn about the equivocation too
+func (c *BaseClient) broadcastEquivocation(equivocation types.Equivocation) error {
+	equivocation.Reporter = ""
+	evidence, err := json.Marshal(equivocation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal equivocation: %w", err)
+	}
+	message, err := c.buildMessage(equivocation.DKGRoundID, types.EquivocationEvidence, evidence)
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	return c.SendMessage(*message)
+}
+
+// processEquivocationEvidence verifies the evidence broadcast by a participant and saves it
+func (c *BaseClient) processEquivocationEvidence(state State, message storage.Message) ([]api.Event, error) {
+	fsmInstance, err := c.getFSMInstance(state, message.DkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to getFSMInstance: %w", err)
+	}
+	if err = c.verifyMessage(fsmInstance, message); err != nil {
+		return nil, fmt.Errorf("failed to verifyMessage: %w", err)
+	}
+
+	var evidence types.Equivocation
+	if err = json.Unmarshal(message.Data, &evidence); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal equivocation: %w", err)
+	}
+	if err = c.verifyEquivocation(fsmInstance, message.DkgRoundID, evidence); err != nil {
+		return nil, fmt.Errorf("invalid equivocation evidence: %w", err)
+	}
+
+	known, err := state.GetEquivocation(evidence.DKGRoundID, evidence.Username, evidence.Step)
+	if err != nil || known != nil {
+		return nil, err
+	}
+	evidence.Reporter = message.SenderAddr
+	evidence.DetectedAt = time.Now()
+	if err = state.PutEquivocation(evidence); err != nil {
+		return nil, err
+	}
+
+	return []api.Event{c.equivocationEvent(message, evidence)}, nil
+}
+
+// verifyEquivocation checks that both messages of the evidence are signed by the accused participant
+// for the same step of the DKG round and that they differ
+func (c *BaseClient) verifyEquivocation(fsmInstance *state_machines.FSMInstance, dkgRoundID string,
+	evidence types.Equivocation) error {
+	if evidence.DKGRoundID != dkgRoundID {
+		return fmt.Errorf("evidence of DKG round %s is sent to DKG round %s", evidence.DKGRoundID, dkgRoundID)
+	}
+	for _, message := range []storage.Message{evidence.First, evidence.Second} {
+		if message.DkgRoundID != dkgRoundID || message.SenderAddr != evidence.Username {
+			return fmt.Errorf("message %s is not sent by %s in DKG round %s", message.ID, evidence.Username,
+				dkgRoundID)
+		}
+		if step, ok := equivocationStep(message); !ok || step != evidence.Step {
+			return fmt.Errorf("message %s is not sent for step %s", message.ID, evidence.Step)
+		}
+		if err := c.verifyMessage(fsmInstance, message); err != nil {
+			return fmt.Errorf("failed to verify message %s: %w", message.ID, err)
+		}
+	}
+	if bytes.Equal(evidence.First.Data, evidence.Second.Data) {
+		return errors.New("messages have the same data")
+	}
+
+	return nil
+}
+
+// GetEquivocations returns evidences of participants who have signed different messages for the same step
+func (c *BaseClient) GetEquivocations() ([]types.Equivocation, error) {
+	equivocations, err := c.state.GetEquivocations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equivocations: %w", err)
+	}
+	if equivocations == nil {
+		equivocations = []types.Equivocation{}
+	}
+	return equivocations, nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_Equivocation(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_equivocation")
+	defer round.close()
+
+	alice, carol := round.newClient("alice"), round.newClient("carol")
+	alice.BroadcastEquivocations = true
+	stg := round.storage
+
+	initRequest := round.initRequest
+	initData, err := json.Marshal(initRequest)
+	req.NoError(err)
+	fsmInstance, err := state_machines.Create("dkg_round_id")
+	req.NoError(err)
+	_, _, err = fsmInstance.Do(spf.EventInitProposal, initRequest)
+	req.NoError(err)
+	bobID, err := fsmInstance.GetIDByUsername("bob")
+	req.NoError(err)
+	bobMessage := func(event string, createdAt time.Time) storage.Message {
+		data, err := json.Marshal(requests.SignatureProposalParticipantRequest{
+			ParticipantId: bobID,
+			CreatedAt:     createdAt,
+		})
+		req.NoError(err)
+		message := storage.Message{
+			DkgRoundID: "dkg_round_id",
+			Event:      event,
+			Data:       data,
+			SenderAddr: "bob",
+		}
+		message.Signature = ed25519.Sign(round.keyPair("bob").Priv, message.Bytes())
+		return message
+	}
+
+	// bob confirms the proposal and declines it then
+	messages, err := stg.SendBatch(
+		storage.Message{
+			DkgRoundID: "dkg_round_id",
+			Event:      string(spf.EventInitProposal),
+			Data:       initData,
+			SenderAddr: "alice",
+		},
+		bobMessage(string(spf.EventConfirmSignatureProposal), time.Now()),
+		bobMessage(string(spf.EventDeclineProposal), time.Now().Add(time.Second)),
+	)
+	req.NoError(err)
+
+	events, unsubscribe := alice.SubscribeEvents()
+	defer unsubscribe()
+	for _, message := range messages {
+		alice.handleMessage(message)
+	}
+	equivocations, err := alice.GetEquivocations()
+	req.NoError(err)
+	req.Len(equivocations, 1)
+	req.Equal("bob", equivocations[0].Username)
+	req.Equal("proposal_confirmation", equivocations[0].Step)
+	req.Equal(messages[1].ID, equivocations[0].First.ID)
+	req.Equal(messages[2].ID, equivocations[0].Second.ID)
+	req.Empty(equivocations[0].Reporter)
+	for event := range events {
+		if event.Type == api.EventEquivocationDetected {
+			req.Equal(uint64(2), event.MessageOffset)
+			break
+		}
+	}
+
+	// the same message processed again is not an equivocation
+	alice.checkEquivocation(messages[1])
+	equivocations, err = alice.GetEquivocations()
+	req.NoError(err)
+	req.Len(equivocations, 1)
+
+	// carol has missed the decline, but gets the evidence broadcast by alice
+	evidenceMessages, err := stg.GetMessages(3)
+	req.NoError(err)
+	req.Len(evidenceMessages, 1)
+	req.Equal(string(types.EquivocationEvidence), evidenceMessages[0].Event)
+
+	forged := evidenceMessages[0]
+	var evidence types.Equivocation
+	req.NoError(json.Unmarshal(forged.Data, &evidence))
+	evidence.Second.Data = []byte("forged")
+	forged.Data, err = json.Marshal(evidence)
+	req.NoError(err)
+	forged.Signature = ed25519.Sign(round.keyPair("alice").Priv, forged.Bytes())
+
+	for _, message := range []storage.Message{messages[0], messages[1], forged} {
+		carol.handleMessage(message)
+	}
+	equivocations, err = carol.GetEquivocations()
+	req.NoError(err)
+	req.Empty(equivocations)
+
+	carol.handleMessage(evidenceMessages[0])
+	equivocations, err = carol.GetEquivocations()
+	req.NoError(err)
+	req.Len(equivocations, 1)
+	req.Equal("bob", equivocations[0].Username)
+	req.Equal("alice", equivocations[0].Reporter)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// subscriberBufferSize is a number of events a subscriber can lag behind before it starts to miss events
+const subscriberBufferSize = 64
+
+// eventBus delivers events to all subscribers. Publishing never blocks the message processing,
+// so a slow subscriber misses events instead of stopping the client
+type eventBus struct {
+	sync.Mutex
+	subscribers map[int]chan api.Event
+	nextID      int
+	logger      *logger
+}
+
+func newEventBus(logger *logger) *eventBus {
+	return &eventBus{
+		subscribers: make(map[int]chan api.Event),
+		logger:      logger,
+	}
+}
+
+// subscribe returns a channel with new events and a function to unsubscribe
+func (b *eventBus) subscribe() (<-chan api.Event, func()) {
+	b.Lock()
+	defer b.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	events := make(chan api.Event, subscriberBufferSize)
+	b.subscribers[id] = events
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			b.Lock()
+			defer b.Unlock()
+			delete(b.subscribers, id)
+			close(events)
+		})
+	}
+}
+
+func (b *eventBus) publish(event api.Event) {
+	b.Lock()
+	defer b.Unlock()
+
+	for id, events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+			b.logger.Log("Event subscriber %d is too slow, dropping event %s", id, event.Type)
+		}
+	}
+}
+
+// messageEvent returns an event caused by the message from the append-only log
+func messageEvent(eventType api.EventType, message storage.Message) api.Event {
+	return api.Event{
+		Type:          eventType,
+		DKGRoundID:    message.DkgRoundID,
+		MessageEvent:  message.Event,
+		MessageOffset: message.Offset,
+		Sender:        message.SenderAddr,
+		Time:          time.Now(),
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_WatchEvents(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_events")
+	defer round.close()
+
+	baseClient := round.newClient("user_name")
+
+	server := httptest.NewServer((&apiAuth{logger: baseClient.Logger}).middleware(
+		http.HandlerFunc(baseClient.watchEventsHandler)))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan api.Event, 1)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- api.NewClient(server.URL).WatchEvents(ctx, "dkg_round_id", func(event api.Event) error {
+			received <- event
+			return errors.New("stop")
+		})
+	}()
+
+	// the subscription is made by the server handler, so wait for it before processing the message
+	req.Eventually(func() bool {
+		baseClient.events.Lock()
+		defer baseClient.events.Unlock()
+		return len(baseClient.events.subscribers) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// events of other DKG rounds are filtered out by the server
+	baseClient.events.publish(api.Event{Type: api.EventFSMStateChanged, DKGRoundID: "another_dkg_round_id"})
+
+	// the DKG round has no participants yet, so the message cannot be verified
+	message := storage.Message{
+		DkgRoundID: "dkg_round_id",
+		Offset:     7,
+		Event:      string(sipf.EventSigningStart),
+		SenderAddr: "sender",
+	}
+	req.Error(baseClient.ProcessMessage(message))
+
+	select {
+	case event := <-received:
+		req.Equal(api.EventMessageRejected, event.Type)
+		req.Equal("dkg_round_id", event.DKGRoundID)
+		req.Equal(uint64(7), event.MessageOffset)
+		req.Equal("sender", event.Sender)
+		req.NotEmpty(event.Error)
+	case <-ctx.Done():
+		t.Fatal("event was not received")
+	}
+	req.EqualError(<-watchErr, "stop")
+}
@@ -1,22 +1,22 @@
 package client
 
 import (
-	"bytes"
 	"context"
-	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/sign/bls"
 	"github.com/lidofinance/dc4bc/airgapped"
-	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/client/api"
 	"github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
 	"github.com/lidofinance/dc4bc/fsm/types/requests"
 	"github.com/lidofinance/dc4bc/qr"
@@ -28,64 +28,16 @@ type node struct {
 	keyPair    *KeyPair
 	air        *airgapped.Machine
 	listenAddr string
-}
-
-type OperationsResponse struct {
-	ErrorMessage string                      `json:"error_message,omitempty"`
-	Result       map[string]*types.Operation `json:"result"`
-}
-
-func getOperations(url string) (*OperationsResponse, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get operations for node %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body %v", err)
-	}
-
-	var response OperationsResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-	return &response, nil
-}
-
-func handleProcessedOperation(url string, operation types.Operation) error {
-	operationBz, err := json.Marshal(operation)
-	if err != nil {
-		return fmt.Errorf("failed to marshal operation: %w", err)
-	}
-	resp, err := http.Post(url, "application/json", bytes.NewReader(operationBz))
-	if err != nil {
-		return fmt.Errorf("failed to handle processed operation %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read body %v", err)
-	}
-
-	var response Response
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-	if response.ErrorMessage != "" {
-		return fmt.Errorf("failed to handle processed operation: %s", response.ErrorMessage)
-	}
-	return nil
+	api        *api.Client
 }
 
 func (n *node) run(t *testing.T) {
 	for {
-		operationsResponse, err := getOperations(fmt.Sprintf("http://%s/getOperations", n.listenAddr))
+		operations, err := n.api.GetOperations(context.Background())
 		if err != nil {
 			t.Fatalf("failed to get operations: %v", err)
 		}
 
-		operations := operationsResponse.Result
 		if len(operations) == 0 {
 			time.Sleep(1 * time.Second)
 			continue
@@ -116,8 +68,7 @@ func (n *node) run(t *testing.T) {
 				}
 			}
 
-			if err = handleProcessedOperation(fmt.Sprintf("http://%s/handleProcessedOperationJSON", n.listenAddr),
-				processedOperation); err != nil {
+			if err = n.api.HandleProcessedOperation(context.Background(), processedOperation); err != nil {
 				n.client.GetLogger().Log("Failed to handle processed operation: %v", err)
 			} else {
 				n.client.GetLogger().Log("Successfully handled processed operation %s", processedOperation.Event)
@@ -200,6 +151,7 @@ func TestFullFlow(t *testing.T) {
 			keyPair:    keyPair,
 			air:        airgappedMachine,
 			listenAddr: fmt.Sprintf("localhost:%d", startingPort),
+			api:        api.NewClient(fmt.Sprintf("localhost:%d", startingPort)),
 		}
 		startingPort++
 	}
@@ -246,32 +198,92 @@ func TestFullFlow(t *testing.T) {
 		t.Fatalf("failed to marshal SignatureProposalParticipantsListRequest: %v\n", err)
 	}
 
-	if _, err := http.Post(fmt.Sprintf("http://localhost:%d/startDKG", startingPort-1),
-		"application/json", bytes.NewReader(messageDataBz)); err != nil {
+	initiator := nodes[len(nodes)-1].api
+	dkgRoundID, err := initiator.StartDKG(context.Background(), messageDataBz)
+	if err != nil {
 		t.Fatalf("failed to send HTTP request to start DKG: %v\n", err)
 	}
 
 	time.Sleep(30 * time.Second)
 	log.Println("Propose message to sign")
 
-	dkgRoundID := md5.Sum(messageDataBz)
-	messageDataBz, err = json.Marshal(map[string][]byte{"data": []byte("message to sign"),
-		"dkgID": dkgRoundID[:]})
-	if err != nil {
-		t.Fatalf("failed to marshal SignatureProposalParticipantsListRequest: %v\n", err)
-	}
-
-	if _, err := http.Post(fmt.Sprintf("http://localhost:%d/proposeSignMessage", startingPort-1),
-		"application/json", bytes.NewReader(messageDataBz)); err != nil {
+	if err = initiator.ProposeSign(context.Background(), dkgRoundID, []byte("message to sign")); err != nil {
 		t.Fatalf("failed to send HTTP request to sign message: %v\n", err)
 	}
 	time.Sleep(10 * time.Second)
 
 	fmt.Println("Sign message again")
-	if _, err := http.Post(fmt.Sprintf("http://localhost:%d/proposeSignMessage", startingPort-1),
-		"application/json", bytes.NewReader(messageDataBz)); err != nil {
+	if err = initiator.ProposeSign(context.Background(), dkgRoundID, []byte("message to sign")); err != nil {
 		t.Fatalf("failed to send HTTP request to sign message: %v\n", err)
 	}
-	time.Sleep(10 * time.Second)
 
+	// signings of the same DKG round are processed concurrently
+	fmt.Println("Sign another message concurrently")
+	if err = initiator.ProposeSign(context.Background(), dkgRoundID, []byte("another message to sign")); err != nil {
+		t.Fatalf("failed to send HTTP request to sign message: %v\n", err)
+	}
+
+	messages := [][]byte{[]byte("message to sign"), []byte("another message to sign")}
+	signatures := waitForSignatures(t, initiator, dkgRoundID, messages, time.Minute)
+
+	masterKeyHex, err := ioutil.ReadFile("/tmp/participant_0.pubkey")
+	if err != nil {
+		t.Fatalf("failed to read master key: %v", err)
+	}
+	masterKeyBz, err := hex.DecodeString(string(masterKeyHex))
+	if err != nil {
+		t.Fatalf("failed to decode master key: %v", err)
+	}
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+	masterKey := blsSuite.Point()
+	if err = masterKey.UnmarshalBinary(masterKeyBz); err != nil {
+		t.Fatalf("failed to unmarshal master key: %v", err)
+	}
+	for idx, message := range messages {
+		signature := signatures[string(message)]
+		if err = bls.Verify(blsSuite.(pairing.Suite), masterKey, message, signature); err != nil {
+			t.Fatalf("signature of %q is invalid: %v", message, err)
+		}
+		otherMessage := messages[(idx+1)%len(messages)]
+		if err = bls.Verify(blsSuite.(pairing.Suite), masterKey, otherMessage, signature); err == nil {
+			t.Fatalf("signature of %q must not verify %q", message, otherMessage)
+		}
+	}
+}
+
+// waitForSignatures waits until every message has a verified reconstructed signature and returns the signatures
+// by the signed messages
+func waitForSignatures(t *testing.T, client *api.Client, dkgRoundID string, messages [][]byte,
+	timeout time.Duration) map[string][]byte {
+	deadline := time.Now().Add(timeout)
+	for {
+		signingsBySigningID, err := client.GetSignatures(context.Background(), dkgRoundID)
+		if err != nil {
+			t.Fatalf("failed to get signatures: %v", err)
+		}
+
+		signatures := make(map[string][]byte)
+		for _, signing := range signingsBySigningID {
+			for _, signature := range signing {
+				if len(signature.Signature) > 0 && signature.VerificationError == "" {
+					signatures[string(signature.SrcPayload)] = signature.Signature
+				}
+			}
+		}
+
+		reconstructed := true
+		for _, message := range messages {
+			if _, ok := signatures[string(message)]; !ok {
+				reconstructed = false
+			}
+		}
+		if reconstructed {
+			return signatures
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("signatures were not reconstructed in %s, got %d", timeout, len(signatures))
+		}
+		time.Sleep(time.Second)
+	}
 }
@@ -0,0 +1,431 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/api/pb"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// readOnlyGRPCMethods can be called with any scope, all other methods require ScopeOperator
+var readOnlyGRPCMethods = map[string]bool{
+	"/dc4bc.DC4BC/GetUsername":      true,
+	"/dc4bc.DC4BC/GetPubKey":        true,
+	"/dc4bc.DC4BC/GetOperations":    true,
+	"/dc4bc.DC4BC/GetOperation":     true,
+	"/dc4bc.DC4BC/GetSignatures":    true,
+	"/dc4bc.DC4BC/GetSignatureByID": true,
+	"/dc4bc.DC4BC/GetFSMStatus":     true,
+	"/dc4bc.DC4BC/GetFSMList":       true,
+	"/dc4bc.DC4BC/GetOffset":        true,
+	"/dc4bc.DC4BC/WatchEvents":      true,
+}
+
+// GRPCServerConfig configures the gRPC API of the node, the fields have the same meaning as in HTTPServerConfig
+type GRPCServerConfig struct {
+	ListenAddr string
+
+	TLSCertFile  string
+	TLSKeyFile   string
+	ClientCAFile string
+
+	Credentials []APICredential
+
+	AuditLogPath string
+}
+
+// StartGRPCServer serves the gRPC API, it runs side by side with the HTTP API and blocks until the listener fails
+func (c *BaseClient) StartGRPCServer(config GRPCServerConfig) error {
+	tlsConfig, err := serverTLSConfig(config.TLSCertFile, config.TLSKeyFile, config.ClientCAFile)
+	if err != nil {
+		return fmt.Errorf("failed to init TLS config: %w", err)
+	}
+
+	auth := &apiAuth{
+		credentials: config.Credentials,
+		logger:      c.Logger,
+	}
+	if config.AuditLogPath != "" {
+		if auth.audit, err = newAuditLog(config.AuditLogPath); err != nil {
+			return fmt.Errorf("failed to init audit log: %w", err)
+		}
+	}
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(options...)
+	pb.RegisterDC4BCServer(server, &grpcServer{client: c})
+
+	listener, err := net.Listen("tcp", config.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen %s: %w", config.ListenAddr, err)
+	}
+	c.Logger.Log("gRPC server started on address: %s", config.ListenAddr)
+	return server.Serve(listener)
+}
+
+// authorizeGRPC checks the credential of the call, the credential name is returned for the audit log
+func (a *apiAuth) authorizeGRPC(ctx context.Context, method string) (string, error) {
+	if len(a.credentials) == 0 {
+		return "anonymous", nil
+	}
+
+	var credential *APICredential
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) > 0 && strings.HasPrefix(values[0], "Bearer ") {
+		credential = a.findToken(strings.TrimPrefix(values[0], "Bearer "))
+	} else if p, ok := peer.FromContext(ctx); ok {
+		// certificates are already verified by the TLS handshake if mutual TLS is on
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			credential = a.findCommonName(tlsInfo.State.VerifiedChains[0][0].Subject.CommonName)
+		}
+	}
+
+	if credential == nil {
+		return "anonymous", status.Error(codes.Unauthenticated, "missing or invalid credentials")
+	}
+	if credential.Scope != ScopeOperator && !readOnlyGRPCMethods[method] {
+		return credential.Name, status.Errorf(codes.PermissionDenied,
+			"credential %s is not allowed to call %s", credential.Name, method)
+	}
+	return credential.Name, nil
+}
+
+func (a *apiAuth) auditGRPC(ctx context.Context, credentialName, method string, err error) {
+	if a.audit == nil {
+		return
+	}
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	auditErr := a.audit.write(auditRecord{
+		Time:       time.Now(),
+		Credential: credentialName,
+		RemoteAddr: remoteAddr,
+		Method:     "gRPC",
+		Path:       method,
+		StatusCode: int(status.Code(err)),
+	})
+	if auditErr != nil {
+		a.logger.Log("Failed to write audit log: %v", auditErr)
+	}
+}
+
+func (a *apiAuth) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (resp interface{}, err error) {
+	credentialName, err := a.authorizeGRPC(ctx, info.FullMethod)
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+	a.auditGRPC(ctx, credentialName, info.FullMethod, err)
+	return resp, err
+}
+
+func (a *apiAuth) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	credentialName, err := a.authorizeGRPC(ss.Context(), info.FullMethod)
+	if err == nil {
+		err = handler(srv, ss)
+	}
+	a.auditGRPC(ss.Context(), credentialName, info.FullMethod, err)
+	return err
+}
+
+// grpcServer implements the gRPC API on top of the client, like the HTTP handlers do
+type grpcServer struct {
+	pb.UnimplementedDC4BCServer
+	client *BaseClient
+}
+
+func internalError(format string, err error) error {
+	return status.Errorf(codes.Internal, format, err)
+}
+
+func (s *grpcServer) GetUsername(context.Context, *pb.Empty) (*pb.GetUsernameResponse, error) {
+	return &pb.GetUsernameResponse{Username: s.client.GetUsername()}, nil
+}
+
+func (s *grpcServer) GetPubKey(context.Context, *pb.Empty) (*pb.GetPubKeyResponse, error) {
+	return &pb.GetPubKeyResponse{PubKey: s.client.GetPubKey()}, nil
+}
+
+func (s *grpcServer) GetOperations(context.Context, *pb.Empty) (*pb.GetOperationsResponse, error) {
+	operations, err := s.client.GetOperations()
+	if err != nil {
+		return nil, internalError("failed to get operations: %v", err)
+	}
+
+	resp := &pb.GetOperationsResponse{Operations: make([]*pb.Operation, 0, len(operations))}
+	for _, operation := range operations {
+		resp.Operations = append(resp.Operations, operationToProto(operation))
+	}
+	sort.Slice(resp.Operations, func(i, j int) bool {
+		return resp.Operations[i].CreatedAt < resp.Operations[j].CreatedAt
+	})
+	return resp, nil
+}
+
+func (s *grpcServer) GetOperation(_ context.Context, req *pb.GetOperationRequest) (*pb.Operation, error) {
+	operation, err := s.client.state.GetOperationByID(req.OperationId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to get operation: %v", err)
+	}
+	return operationToProto(operation), nil
+}
+
+func (s *grpcServer) HandleProcessedOperation(_ context.Context, req *pb.Operation) (*pb.Empty, error) {
+	if err := s.client.handleProcessedOperation(*operationFromProto(req)); err != nil {
+		return nil, internalError("failed to handle processed operation: %v", err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *grpcServer) StartDKG(_ context.Context, req *pb.StartDKGRequest) (*pb.StartDKGResponse, error) {
+	dkgID, err := s.client.startDKG(req.Proposal)
+	if err != nil {
+		return nil, internalError("failed to start DKG: %v", err)
+	}
+	return &pb.StartDKGResponse{DkgId: dkgID}, nil
+}
+
+func (s *grpcServer) ProposeSign(_ context.Context, req *pb.ProposeSignRequest) (*pb.ProposeSignResponse, error) {
+	if _, err := hex.DecodeString(req.DkgId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to decode dkgID: %v", err)
+	}
+	signingID, err := s.client.proposeSignObject(req.DkgId, req.Data, req.SigningObject)
+	if err != nil {
+		return nil, internalError("failed to propose signing: %v", err)
+	}
+	return &pb.ProposeSignResponse{SigningId: signingID}, nil
+}
+
+func (s *grpcServer) GetSignatures(_ context.Context, req *pb.GetSignaturesRequest) (*pb.GetSignaturesResponse, error) {
+	signatures, err := s.client.GetSignatures(req.DkgId)
+	if err != nil {
+		return nil, internalError("failed to get signatures: %v", err)
+	}
+
+	signingIDs := make([]string, 0, len(signatures))
+	for signingID := range signatures {
+		signingIDs = append(signingIDs, signingID)
+	}
+	sort.Strings(signingIDs)
+
+	resp := &pb.GetSignaturesResponse{}
+	for _, signingID := range signingIDs {
+		resp.Signatures = append(resp.Signatures, signaturesToProto(signatures[signingID])...)
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) GetSignatureByID(_ context.Context, req *pb.GetSignatureByIDRequest) (*pb.GetSignaturesResponse, error) {
+	signatures, err := s.client.GetSignatureByID(req.DkgId, req.SigningId)
+	if err != nil {
+		return nil, internalError("failed to get signature: %v", err)
+	}
+	return &pb.GetSignaturesResponse{Signatures: signaturesToProto(signatures)}, nil
+}
+
+func (s *grpcServer) GetFSMStatus(_ context.Context, req *pb.GetFSMStatusRequest) (*pb.FSMStatus, error) {
+	dump, err := s.client.GetFSMDump(req.DkgId)
+	if err != nil {
+		return nil, internalError("failed to get FSM dump: %v", err)
+	}
+	dumpJSON, err := json.Marshal(dump)
+	if err != nil {
+		return nil, internalError("failed to marshal FSM dump: %v", err)
+	}
+
+	resp := &pb.FSMStatus{
+		DkgId: req.DkgId,
+		State: string(dump.State),
+		Dump:  dumpJSON,
+	}
+	if dump.Payload != nil {
+		for signingID, session := range dump.Payload.SigningProposals {
+			resp.Signings = append(resp.Signings, &pb.SigningStatus{SigningId: signingID, State: string(session.State)})
+		}
+	}
+	sort.Slice(resp.Signings, func(i, j int) bool {
+		return resp.Signings[i].SigningId < resp.Signings[j].SigningId
+	})
+	return resp, nil
+}
+
+func (s *grpcServer) GetFSMList(context.Context, *pb.Empty) (*pb.GetFSMListResponse, error) {
+	states, err := s.client.getFSMStates()
+	if err != nil {
+		return nil, internalError("failed to get FSM states: %v", err)
+	}
+	return &pb.GetFSMListResponse{States: states}, nil
+}
+
+func (s *grpcServer) GetOffset(context.Context, *pb.Empty) (*pb.Offset, error) {
+	offset, err := s.client.state.LoadOffset()
+	if err != nil {
+		return nil, internalError("failed to load offset: %v", err)
+	}
+	return &pb.Offset{Offset: offset}, nil
+}
+
+func (s *grpcServer) SaveOffset(_ context.Context, req *pb.Offset) (*pb.Empty, error) {
+	if err := s.client.state.SaveOffset(req.Offset); err != nil {
+		return nil, internalError("failed to save offset: %v", err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *grpcServer) WatchEvents(req *pb.WatchEventsRequest, stream pb.DC4BC_WatchEventsServer) error {
+	events, unsubscribe := s.client.SubscribeEvents()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if req.DkgId != "" && req.DkgId != event.DKGRoundID {
+				continue
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func operationToProto(operation *types.Operation) *pb.Operation {
+	resultMsgs := make([]*pb.Message, 0, len(operation.ResultMsgs))
+	for _, msg := range operation.ResultMsgs {
+		resultMsgs = append(resultMsgs, &pb.Message{
+			Id:         msg.ID,
+			DkgRoundId: msg.DkgRoundID,
+			Offset:     msg.Offset,
+			Event:      msg.Event,
+			Data:       msg.Data,
+			Signature:  msg.Signature,
+			Sender:     msg.SenderAddr,
+			Recipient:  msg.RecipientAddr,
+			PrevOffset: msg.PrevOffset,
+			PrevHash:   msg.PrevHash,
+		})
+	}
+	return &pb.Operation{
+		Id:            operation.ID,
+		Type:          string(operation.Type),
+		Payload:       operation.Payload,
+		ResultMsgs:    resultMsgs,
+		CreatedAt:     operation.CreatedAt.UnixNano(),
+		DkgIdentifier: operation.DKGIdentifier,
+		To:            operation.To,
+		Event:         string(operation.Event),
+	}
+}
+
+func operationFromProto(operation *pb.Operation) *types.Operation {
+	resultMsgs := make([]storage.Message, 0, len(operation.ResultMsgs))
+	for _, msg := range operation.ResultMsgs {
+		resultMsgs = append(resultMsgs, storage.Message{
+			ID:            msg.Id,
+			DkgRoundID:    msg.DkgRoundId,
+			Offset:        msg.Offset,
+			Event:         msg.Event,
+			Data:          msg.Data,
+			Signature:     msg.Signature,
+			SenderAddr:    msg.Sender,
+			RecipientAddr: msg.Recipient,
+			PrevOffset:    msg.PrevOffset,
+			PrevHash:      msg.PrevHash,
+		})
+	}
+	return &types.Operation{
+		ID:            operation.Id,
+		Type:          types.OperationType(operation.Type),
+		Payload:       operation.Payload,
+		ResultMsgs:    resultMsgs,
+		CreatedAt:     time.Unix(0, operation.CreatedAt),
+		DKGIdentifier: operation.DkgIdentifier,
+		To:            operation.To,
+		Event:         fsm.Event(operation.Event),
+	}
+}
+
+func signaturesToProto(signatures []types.ReconstructedSignature) []*pb.Signature {
+	result := make([]*pb.Signature, 0, len(signatures))
+	for _, signature := range signatures {
+		result = append(result, &pb.Signature{
+			SigningId:  signature.SigningID,
+			SrcPayload: signature.SrcPayload,
+			Signature:  signature.Signature,
+			Username:   signature.Username,
+			DkgRoundId: signature.DKGRoundID,
+		})
+	}
+	return result
+}
+
+func eventToProto(event api.Event) *pb.Event {
+	result := &pb.Event{
+		DkgId:         event.DKGRoundID,
+		State:         event.State,
+		SigningId:     event.SigningID,
+		Time:          event.Time.UnixNano(),
+		MessageEvent:  event.MessageEvent,
+		MessageOffset: event.MessageOffset,
+		Sender:        event.Sender,
+		Error:         event.Error,
+	}
+	switch event.Type {
+	case api.EventFSMStateChanged:
+		result.Type = pb.Event_FSM_STATE_CHANGED
+	case api.EventNewOperation:
+		result.Type = pb.Event_NEW_OPERATION
+	case api.EventSignatureReconstructed:
+		result.Type = pb.Event_SIGNATURE_RECONSTRUCTED
+	case api.EventMessageRejected:
+		result.Type = pb.Event_MESSAGE_REJECTED
+	case api.EventSigningRejected:
+		result.Type = pb.Event_SIGNING_REJECTED
+	case api.EventDeadlineApproaching:
+		result.Type = pb.Event_DEADLINE_APPROACHING
+	case api.EventLogForkDetected:
+		result.Type = pb.Event_LOG_FORK_DETECTED
+	case api.EventEquivocationDetected:
+		result.Type = pb.Event_EQUIVOCATION_DETECTED
+	case api.EventLogUnverified:
+		result.Type = pb.Event_LOG_UNVERIFIED
+	}
+	if event.Operation != nil {
+		result.Operation = operationToProto(event.Operation)
+	}
+	if event.Signature != nil {
+		result.Signature = signaturesToProto([]types.ReconstructedSignature{*event.Signature})[0]
+	}
+	return result
+}
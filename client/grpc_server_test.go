@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/api/pb"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCServer(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+	)
+
+	round := newTestRound(t, "dc4bc_grpc_server")
+	defer round.close()
+
+	userName := "user_name"
+	baseClient := round.newClient(userName)
+	req.NoError(baseClient.state.SaveOffset(42))
+
+	auth := &apiAuth{
+		credentials: []APICredential{
+			{Name: "monitoring", Token: "read-token", Scope: ScopeReadOnly},
+		},
+		logger: baseClient.Logger,
+	}
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor),
+	)
+	pb.RegisterDC4BCServer(server, &grpcServer{client: baseClient})
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	req.NoError(err)
+	defer conn.Close()
+	grpcClient := pb.NewDC4BCClient(conn)
+
+	_, err = grpcClient.GetUsername(ctx, &pb.Empty{})
+	req.Equal(codes.Unauthenticated, status.Code(err))
+
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer read-token")
+	username, err := grpcClient.GetUsername(authCtx, &pb.Empty{})
+	req.NoError(err)
+	req.Equal(userName, username.Username)
+
+	offset, err := grpcClient.GetOffset(authCtx, &pb.Empty{})
+	req.NoError(err)
+	req.Equal(uint64(42), offset.Offset)
+
+	_, err = grpcClient.StartDKG(authCtx, &pb.StartDKGRequest{Proposal: []byte("{}")})
+	req.Equal(codes.PermissionDenied, status.Code(err))
+
+	stream, err := grpcClient.WatchEvents(authCtx, &pb.WatchEventsRequest{DkgId: "dkg_round_id"})
+	req.NoError(err)
+
+	// the subscription is made by the server handler, so wait for it before publishing
+	req.Eventually(func() bool {
+		baseClient.events.Lock()
+		defer baseClient.events.Unlock()
+		return len(baseClient.events.subscribers) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	operation := types.NewOperation("dkg_round_id", []byte("payload"), "state_sig_proposal_await_participants_confirmations")
+	baseClient.events.publish(api.Event{Type: api.EventFSMStateChanged, DKGRoundID: "another_dkg_round_id", Time: time.Now()})
+	baseClient.events.publish(api.Event{
+		Type:       api.EventNewOperation,
+		DKGRoundID: "dkg_round_id",
+		State:      string(operation.Type),
+		Operation:  operation,
+		Time:       time.Now(),
+	})
+
+	event, err := stream.Recv()
+	req.NoError(err)
+	req.Equal(pb.Event_NEW_OPERATION, event.Type)
+	req.Equal("dkg_round_id", event.DkgId)
+	req.Equal(operation.ID, event.Operation.Id)
+	req.Equal(operation.Payload, operationFromProto(event.Operation).Payload)
+}
+
+func TestEventToProto(t *testing.T) {
+	req := require.New(t)
+
+	for eventType, protoType := range map[api.EventType]pb.Event_Type{
+		api.EventFSMStateChanged:        pb.Event_FSM_STATE_CHANGED,
+		api.EventNewOperation:           pb.Event_NEW_OPERATION,
+		api.EventSignatureReconstructed: pb.Event_SIGNATURE_RECONSTRUCTED,
+		api.EventMessageRejected:        pb.Event_MESSAGE_REJECTED,
+		api.EventSigningRejected:        pb.Event_SIGNING_REJECTED,
+		api.EventDeadlineApproaching:    pb.Event_DEADLINE_APPROACHING,
+		api.EventLogForkDetected:        pb.Event_LOG_FORK_DETECTED,
+		api.EventEquivocationDetected:   pb.Event_EQUIVOCATION_DETECTED,
+		api.EventLogUnverified:          pb.Event_LOG_UNVERIFIED,
+	} {
+		req.Equal(protoType, eventToProto(api.Event{Type: eventType}).Type, eventType)
+	}
+
+	// the commitment to the log prefix is signed, so it must survive the round trip
+	operation := types.NewOperation("dkg_round_id", []byte("payload"), "state_sig_proposal_await_participants_confirmations")
+	operation.ResultMsgs = []storage.Message{{Event: "event", Data: []byte("data"), PrevOffset: 7,
+		PrevHash: []byte("prev_hash")}}
+	resultMsgs := operationFromProto(operationToProto(operation)).ResultMsgs
+	req.Equal(uint64(7), resultMsgs[0].PrevOffset)
+	req.Equal([]byte("prev_hash"), resultMsgs[0].PrevHash)
+}
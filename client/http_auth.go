@@ -0,0 +1,298 @@
+package client
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+)
+
+// APIScope defines which HTTP endpoints a credential is allowed to call
+type APIScope string
+
+const (
+	// ScopeReadOnly allows to call endpoints that don't change the node state or send messages
+	ScopeReadOnly APIScope = "read_only"
+	// ScopeOperator allows to call every endpoint
+	ScopeOperator APIScope = "operator"
+)
+
+// readOnlyEndpoints can be called with any scope, all other endpoints require ScopeOperator
+var readOnlyEndpoints = map[string]bool{
+	api.EndpointGetUsername:           true,
+	api.EndpointGetPubKey:             true,
+	api.EndpointGetOperations:         true,
+	api.EndpointGetOperationQRPath:    true,
+	api.EndpointGetOperationQR:        true,
+	api.EndpointGetOperation:          true,
+	api.EndpointGetSignatures:         true,
+	api.EndpointGetSignatureByID:      true,
+	api.EndpointGetRejectedSignatures: true,
+	api.EndpointGetOffset:             true,
+	api.EndpointGetFSMDump:            true,
+	api.EndpointGetFSMList:            true,
+	api.EndpointGetSigningQueue:       true,
+	api.EndpointWatchEvents:           true,
+	api.EndpointGetRoundStatus:        true,
+	api.EndpointGetDeadLetters:        true,
+	api.EndpointGetLogCheckpoints:     true,
+	api.EndpointGetEquivocations:      true,
+	dashboardPath:                     true,
+	dashboardQRPath:                   true,
+	web3SignerPublicKeysPath:          true,
+	web3SignerUpcheckPath:             true,
+}
+
+// APICredential is an entry of the credentials file. A credential is identified either by a bearer token
+// or by the common name of a client TLS certificate
+type APICredential struct {
+	Name           string   `json:"name"`
+	Token          string   `json:"token,omitempty"`
+	CertCommonName string   `json:"cert_cn,omitempty"`
+	Scope          APIScope `json:"scope"`
+}
+
+func (c *APICredential) Validate() error {
+	if c.Name == "" {
+		return errors.New("credential name cannot be empty")
+	}
+	if c.Token == "" && c.CertCommonName == "" {
+		return fmt.Errorf("credential %s must have a token or a certificate common name", c.Name)
+	}
+	if c.Scope != ScopeReadOnly && c.Scope != ScopeOperator {
+		return fmt.Errorf("credential %s has invalid scope %s", c.Name, c.Scope)
+	}
+	return nil
+}
+
+// LoadAPICredentials reads a JSON list of credentials from the file
+func LoadAPICredentials(path string) ([]APICredential, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var credentials []APICredential
+	if err = json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for _, credential := range credentials {
+		if err = credential.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid credential: %w", err)
+		}
+		if names[credential.Name] {
+			return nil, fmt.Errorf("duplicate credential name %s", credential.Name)
+		}
+		names[credential.Name] = true
+	}
+
+	return credentials, nil
+}
+
+// HTTPServerConfig configures the HTTP API of the node. Empty fields leave the corresponding feature disabled,
+// so the zero config (except of ListenAddr) gives a plain HTTP server without authentication
+type HTTPServerConfig struct {
+	ListenAddr string
+
+	// TLSCertFile and TLSKeyFile enable HTTPS
+	TLSCertFile string
+	TLSKeyFile  string
+	// ClientCAFile enables mutual TLS: clients must present a certificate signed by this CA
+	ClientCAFile string
+
+	// Credentials enable authentication, every request must be made with one of them
+	Credentials []APICredential
+
+	// AuditLogPath is a file to append a record about every API call to
+	AuditLogPath string
+}
+
+func (cfg *HTTPServerConfig) tlsConfig() (*tls.Config, error) {
+	return serverTLSConfig(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.ClientCAFile)
+}
+
+// serverTLSConfig returns nil if the TLS certificate and key are not set
+func serverTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, errors.New("client CA requires the server TLS certificate and key")
+		}
+		return nil, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		caCert, err := ioutil.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("failed to parse client CA")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// auditRecord is a line of the audit log
+type auditRecord struct {
+	Time       time.Time `json:"time"`
+	Credential string    `json:"credential"`
+	RemoteAddr string    `json:"remote_addr"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"status_code"`
+}
+
+type auditLog struct {
+	sync.Mutex
+	file *os.File
+}
+
+func newAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &auditLog{file: file}, nil
+}
+
+func (l *auditLog) write(record auditRecord) error {
+	recordBz, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if _, err = l.file.Write(append(recordBz, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// statusRecorder remembers the status code of a response for the audit log
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush allows to stream responses through the recorder
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// apiAuth authenticates and authorizes requests to the HTTP API and writes them to the audit log
+type apiAuth struct {
+	credentials []APICredential
+	audit       *auditLog
+	logger      *logger
+}
+
+// authenticate returns a credential the request was made with, nil if the request has no valid credential
+func (a *apiAuth) authenticate(r *http.Request) *APICredential {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return a.findToken(strings.TrimPrefix(header, "Bearer "))
+	}
+	// browsers can't send bearer tokens, so the dashboard users enter the token as a password of basic auth
+	if _, password, ok := r.BasicAuth(); ok {
+		return a.findToken(password)
+	}
+
+	// certificates are already verified by the TLS handshake if mutual TLS is on
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return a.findCommonName(r.TLS.VerifiedChains[0][0].Subject.CommonName)
+	}
+
+	return nil
+}
+
+func (a *apiAuth) findToken(token string) *APICredential {
+	for idx, credential := range a.credentials {
+		if credential.Token != "" && subtle.ConstantTimeCompare([]byte(credential.Token), []byte(token)) == 1 {
+			return &a.credentials[idx]
+		}
+	}
+	return nil
+}
+
+func (a *apiAuth) findCommonName(commonName string) *APICredential {
+	for idx, credential := range a.credentials {
+		if credential.CertCommonName != "" && credential.CertCommonName == commonName {
+			return &a.credentials[idx]
+		}
+	}
+	return nil
+}
+
+func (a *apiAuth) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		credentialName := "anonymous"
+
+		switch {
+		case len(a.credentials) == 0:
+			next.ServeHTTP(recorder, r)
+		default:
+			credential := a.authenticate(r)
+			if credential == nil {
+				recorder.Header().Set("WWW-Authenticate", `Basic realm="dc4bc"`)
+				errorResponse(recorder, http.StatusUnauthorized, "missing or invalid credentials")
+				break
+			}
+			credentialName = credential.Name
+			if credential.Scope != ScopeOperator && !readOnlyEndpoints[r.URL.Path] {
+				errorResponse(recorder, http.StatusForbidden,
+					fmt.Sprintf("credential %s is not allowed to call %s", credential.Name, r.URL.Path))
+				break
+			}
+			next.ServeHTTP(recorder, r)
+		}
+
+		if a.audit == nil {
+			return
+		}
+		err := a.audit.write(auditRecord{
+			Time:       time.Now(),
+			Credential: credentialName,
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			StatusCode: recorder.statusCode,
+		})
+		if err != nil {
+			a.logger.Log("Failed to write audit log: %v", err)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIAuth_Middleware(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "dc4bc_api_auth")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	audit, err := newAuditLog(filepath.Join(dir, "audit.log"))
+	req.NoError(err)
+
+	auth := &apiAuth{
+		credentials: []APICredential{
+			{Name: "monitoring", Token: "read-token", Scope: ScopeReadOnly},
+			{Name: "operator", Token: "operator-token", Scope: ScopeOperator},
+		},
+		audit:  audit,
+		logger: newLogger("test"),
+	}
+	handler := auth.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		successResponse(w, "ok")
+	}))
+
+	testCases := []struct {
+		path       string
+		token      string
+		statusCode int
+	}{
+		{"/getOperations", "", http.StatusUnauthorized},
+		{"/getOperations", "wrong-token", http.StatusUnauthorized},
+		{"/getOperations", "read-token", http.StatusOK},
+		{"/startDKG", "read-token", http.StatusForbidden},
+		{"/handleProcessedOperationJSON", "read-token", http.StatusForbidden},
+		{"/startDKG", "operator-token", http.StatusOK},
+	}
+	for _, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.token != "" {
+			r.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		req.Equal(tc.statusCode, w.Code, "%s with token %q", tc.path, tc.token)
+	}
+
+	auditFile, err := os.Open(filepath.Join(dir, "audit.log"))
+	req.NoError(err)
+	defer auditFile.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(auditFile)
+	for scanner.Scan() {
+		var record auditRecord
+		req.NoError(json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	req.Len(records, len(testCases))
+	req.Equal("anonymous", records[0].Credential)
+	req.Equal("monitoring", records[3].Credential)
+	req.Equal("/startDKG", records[3].Path)
+	req.Equal(http.StatusForbidden, records[3].StatusCode)
+	req.Equal("operator", records[5].Credential)
+
+	// the dashboard is opened in a browser, which sends the token as a basic auth password
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	req.Equal(http.StatusUnauthorized, w.Code)
+	req.Equal(`Basic realm="dc4bc"`, w.Header().Get("WWW-Authenticate"))
+
+	r := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+	r.SetBasicAuth("", "read-token")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	req.Equal(http.StatusOK, w.Code)
+
+	r = httptest.NewRequest(http.MethodPost, "/dashboard/upload", nil)
+	r.SetBasicAuth("", "read-token")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	req.Equal(http.StatusForbidden, w.Code)
+}
+
+func TestAPIAuth_NoCredentials(t *testing.T) {
+	auth := &apiAuth{logger: newLogger("test")}
+	handler := auth.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		successResponse(w, "ok")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/startDKG", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLoadAPICredentials(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "dc4bc_api_credentials")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "credentials.json")
+	req.NoError(ioutil.WriteFile(path, []byte(`[
+		{"name": "monitoring", "token": "read-token", "scope": "read_only"},
+		{"name": "alice", "cert_cn": "alice", "scope": "operator"}
+	]`), 0600))
+	credentials, err := LoadAPICredentials(path)
+	req.NoError(err)
+	req.Len(credentials, 2)
+	req.Equal(ScopeOperator, credentials[1].Scope)
+
+	req.NoError(ioutil.WriteFile(path, []byte(`[{"name": "monitoring", "token": "read-token", "scope": "admin"}]`), 0600))
+	_, err = LoadAPICredentials(path)
+	req.Error(err)
+
+	req.NoError(ioutil.WriteFile(path, []byte(`[{"name": "monitoring", "scope": "read_only"}]`), 0600))
+	_, err = LoadAPICredentials(path)
+	req.Error(err)
+}
@@ -1,7 +1,6 @@
 package client
 
 import (
-	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -11,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lidofinance/dc4bc/client/api"
 	"github.com/lidofinance/dc4bc/client/types"
 	"github.com/lidofinance/dc4bc/fsm/fsm"
 	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
@@ -21,10 +21,8 @@ import (
 	"github.com/lidofinance/dc4bc/storage"
 )
 
-type Response struct {
-	ErrorMessage string      `json:"error_message,omitempty"`
-	Result       interface{} `json:"result"`
-}
+// Response is kept for compatibility, new code should use api.Response
+type Response = api.Response
 
 func rawResponse(w http.ResponseWriter, response []byte) {
 	if _, err := w.Write(response); err != nil {
@@ -35,7 +33,7 @@ func rawResponse(w http.ResponseWriter, response []byte) {
 func errorResponse(w http.ResponseWriter, statusCode int, error string) {
 	w.WriteHeader(statusCode)
 	w.Header().Set("Content-Type", "application/json")
-	resp := Response{ErrorMessage: error}
+	resp := api.Response{ErrorMessage: error}
 	respBz, err := json.Marshal(resp)
 	if err != nil {
 		log.Printf("Failed to marshal response: %v\n", err)
@@ -48,7 +46,7 @@ func errorResponse(w http.ResponseWriter, statusCode int, error string) {
 
 func successResponse(w http.ResponseWriter, response interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	resp := Response{Result: response}
+	resp := api.Response{Result: response}
 	respBz, err := json.Marshal(resp)
 	if err != nil {
 		log.Printf("Failed to marshal response: %v\n", err)
@@ -60,33 +58,131 @@ func successResponse(w http.ResponseWriter, response interface{}) {
 }
 
 func (c *BaseClient) StartHTTPServer(listenAddr string) error {
+	return c.StartHTTPServerWithConfig(HTTPServerConfig{ListenAddr: listenAddr})
+}
+
+// StartHTTPServerWithConfig starts the HTTP API with optional TLS, authentication and audit log
+func (c *BaseClient) StartHTTPServerWithConfig(config HTTPServerConfig) error {
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("failed to init TLS config: %w", err)
+	}
+
+	auth := &apiAuth{
+		credentials: config.Credentials,
+		logger:      c.Logger,
+	}
+	if config.AuditLogPath != "" {
+		if auth.audit, err = newAuditLog(config.AuditLogPath); err != nil {
+			return fmt.Errorf("failed to init audit log: %w", err)
+		}
+	}
+
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/getUsername", c.getUsernameHandler)
-	mux.HandleFunc("/getPubKey", c.getPubkeyHandler)
+	mux.HandleFunc(api.EndpointGetUsername, c.getUsernameHandler)
+	mux.HandleFunc(api.EndpointGetPubKey, c.getPubkeyHandler)
+
+	mux.HandleFunc(api.EndpointSendMessage, c.sendMessageHandler)
+	mux.HandleFunc(api.EndpointGetOperations, c.getOperationsHandler)
+	mux.HandleFunc(api.EndpointGetOperationQRPath, c.getOperationQRPathHandler)
+
+	mux.HandleFunc(api.EndpointGetSignatures, c.getSignaturesHandler)
+	mux.HandleFunc(api.EndpointGetSignatureByID, c.getSignatureByIDHandler)
+	mux.HandleFunc(api.EndpointGetRejectedSignatures, c.getRejectedSignaturesHandler)
+
+	mux.HandleFunc(api.EndpointGetOperationQR, c.getOperationQRToBodyHandler)
+	mux.HandleFunc(api.EndpointHandleProcessedOp, c.handleJSONOperationHandler)
+	mux.HandleFunc(api.EndpointGetOperation, c.getOperationHandler)
+
+	mux.HandleFunc(api.EndpointStartDKG, c.startDKGHandler)
+	mux.HandleFunc(api.EndpointProposeSignMessage, c.proposeSignDataHandler)
+	mux.HandleFunc(api.EndpointDeclineDKG, c.declineDKGHandler)
+	mux.HandleFunc(api.EndpointAbortDKG, c.abortDKGHandler)
+	mux.HandleFunc(api.EndpointDeclineSigning, c.declineSigningHandler)
+	mux.HandleFunc(api.EndpointAbortSigning, c.abortSigningHandler)
 
-	mux.HandleFunc("/sendMessage", c.sendMessageHandler)
-	mux.HandleFunc("/getOperations", c.getOperationsHandler)
-	mux.HandleFunc("/getOperationQRPath", c.getOperationQRPathHandler)
+	mux.HandleFunc(api.EndpointSaveOffset, c.saveOffsetHandler)
+	mux.HandleFunc(api.EndpointGetOffset, c.getOffsetHandler)
 
-	mux.HandleFunc("/getSignatures", c.getSignaturesHandler)
-	mux.HandleFunc("/getSignatureByID", c.getSignatureByIDHandler)
+	mux.HandleFunc(api.EndpointGetFSMDump, c.getFSMDumpHandler)
+	mux.HandleFunc(api.EndpointGetFSMList, c.getFSMList)
+	mux.HandleFunc(api.EndpointGetSigningQueue, c.getSigningQueueHandler)
+	mux.HandleFunc(api.EndpointGetRoundStatus, c.getRoundStatusHandler)
 
-	mux.HandleFunc("/getOperationQR", c.getOperationQRToBodyHandler)
-	mux.HandleFunc("/handleProcessedOperationJSON", c.handleJSONOperationHandler)
-	mux.HandleFunc("/getOperation", c.getOperationHandler)
+	mux.HandleFunc(api.EndpointGetDeadLetters, c.getDeadLettersHandler)
+	mux.HandleFunc(api.EndpointRetryDeadLetter, c.retryDeadLetterHandler)
+	mux.HandleFunc(api.EndpointDiscardDeadLetter, c.discardDeadLetterHandler)
 
-	mux.HandleFunc("/startDKG", c.startDKGHandler)
-	mux.HandleFunc("/proposeSignMessage", c.proposeSignDataHandler)
+	mux.HandleFunc(api.EndpointGetLogCheckpoints, c.getLogCheckpointsHandler)
+	mux.HandleFunc(api.EndpointPublishLogCheckpoint, c.publishLogCheckpointHandler)
+	mux.HandleFunc(api.EndpointGetEquivocations, c.getEquivocationsHandler)
+	mux.HandleFunc(api.EndpointExportTranscript, c.exportTranscriptHandler)
 
-	mux.HandleFunc("/saveOffset", c.saveOffsetHandler)
-	mux.HandleFunc("/getOffset", c.getOffsetHandler)
+	mux.HandleFunc(api.EndpointWatchEvents, c.watchEventsHandler)
 
-	mux.HandleFunc("/getFSMDump", c.getFSMDumpHandler)
-	mux.HandleFunc("/getFSMList", c.getFSMList)
+	mux.HandleFunc(dashboardPath, c.dashboardHandler)
+	mux.HandleFunc(dashboardQRPath, c.dashboardQRHandler)
+	mux.HandleFunc(dashboardUploadPath, c.dashboardUploadHandler)
 
-	c.Logger.Log("HTTP server started on address: %s", listenAddr)
-	return http.ListenAndServe(listenAddr, mux)
+	server := &http.Server{
+		Addr:      config.ListenAddr,
+		Handler:   auth.middleware(mux),
+		TLSConfig: tlsConfig,
+	}
+
+	if tlsConfig != nil {
+		c.Logger.Log("HTTPS server started on address: %s", config.ListenAddr)
+		return server.ListenAndServeTLS("", "")
+	}
+	c.Logger.Log("HTTP server started on address: %s", config.ListenAddr)
+	return server.ListenAndServe()
+}
+
+// watchEventsHandler streams events of the client as Server-Sent Events until the client disconnects,
+// the optional dkgID query parameter filters events of one DKG round
+func (c *BaseClient) watchEventsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		errorResponse(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	dkgID := r.URL.Query().Get("dkgID")
+
+	events, unsubscribe := c.SubscribeEvents()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if dkgID != "" && dkgID != event.DKGRoundID {
+				continue
+			}
+			eventBz, err := json.Marshal(event)
+			if err != nil {
+				c.Logger.Log("Failed to marshal event: %v", err)
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, eventBz); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
 }
 
 func (c *BaseClient) getFSMDumpHandler(w http.ResponseWriter, r *http.Request) {
@@ -102,24 +198,167 @@ func (c *BaseClient) getFSMDumpHandler(w http.ResponseWriter, r *http.Request) {
 	successResponse(w, dump)
 }
 
-func (c *BaseClient) getFSMList(w http.ResponseWriter, r *http.Request) {
+func (c *BaseClient) getRoundStatusHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
 		return
 	}
-	fsmInstances, err := c.state.GetAllFSM()
+	statuses, err := c.GetRoundStatus(r.URL.Query().Get("dkgID"))
 	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get all FSM instances: %v", err))
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get round status: %v", err))
 		return
 	}
-	fsmInstancesStates := make(map[string]string, len(fsmInstances))
-	for k, v := range fsmInstances {
-		state, err := v.State()
-		if err != nil {
-			errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get FSM state: %v", err))
-			return
-		}
-		fsmInstancesStates[k] = state.String()
+	successResponse(w, statuses)
+}
+
+func (c *BaseClient) getDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	letters, err := c.GetDeadLetters()
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get dead letters: %v", err))
+		return
+	}
+	successResponse(w, letters)
+}
+
+func (c *BaseClient) retryDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := readDeadLetterRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := c.RetryDeadLetter(req.MessageID); err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to retry dead letter: %v", err))
+		return
+	}
+	successResponse(w, "ok")
+}
+
+func (c *BaseClient) discardDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := readDeadLetterRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := c.DiscardDeadLetter(req.MessageID); err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to discard dead letter: %v", err))
+		return
+	}
+	successResponse(w, "ok")
+}
+
+// readDeadLetterRequest reads a request body of the dead letter endpoints.
+// On failure it writes an error response and returns false
+func readDeadLetterRequest(w http.ResponseWriter, r *http.Request) (*api.DeadLetterRequest, bool) {
+	if r.Method != http.MethodPost {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return nil, false
+	}
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to read body: %v", err))
+		return nil, false
+	}
+	defer r.Body.Close()
+
+	var req api.DeadLetterRequest
+	if err = json.Unmarshal(reqBody, &req); err != nil {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to unmarshal request: %v", err))
+		return nil, false
+	}
+	if req.MessageID == "" {
+		errorResponse(w, http.StatusBadRequest, "messageID cannot be empty")
+		return nil, false
+	}
+	return &req, true
+}
+
+func (c *BaseClient) getLogCheckpointsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	checkpoints, err := c.GetLogCheckpoints()
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get log checkpoints: %v", err))
+		return
+	}
+	successResponse(w, checkpoints)
+}
+
+func (c *BaseClient) publishLogCheckpointHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	var req api.LogCheckpointRequest
+	if err = json.Unmarshal(reqBody, &req); err != nil {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to unmarshal request: %v", err))
+		return
+	}
+	if err = c.PublishLogCheckpoint(hex.EncodeToString(req.DKGID)); err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to publish log checkpoint: %v", err))
+		return
+	}
+	successResponse(w, "ok")
+}
+
+func (c *BaseClient) getEquivocationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	equivocations, err := c.GetEquivocations()
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get equivocations: %v", err))
+		return
+	}
+	successResponse(w, equivocations)
+}
+
+func (c *BaseClient) exportTranscriptHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	archive, err := c.ExportTranscript(r.URL.Query().Get("dkgID"))
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to export transcript: %v", err))
+		return
+	}
+	successResponse(w, archive)
+}
+
+func (c *BaseClient) getSigningQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	queue, err := c.GetSigningQueue(r.URL.Query().Get("dkgID"))
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	successResponse(w, queue)
+}
+
+func (c *BaseClient) getFSMList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+	fsmInstancesStates, err := c.getFSMStates()
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get FSM states: %v", err))
+		return
 	}
 	successResponse(w, fsmInstancesStates)
 }
@@ -165,16 +404,16 @@ func (c *BaseClient) saveOffsetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	var req map[string]uint64
+	var req api.SaveOffsetRequest
 	if err = json.Unmarshal(reqBytes, &req); err != nil {
 		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request: %v", err))
 		return
 	}
-	if _, ok := req["offset"]; !ok {
-		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("offset cannot be null: %v", err))
+	if req.Offset == nil {
+		errorResponse(w, http.StatusInternalServerError, "offset cannot be null")
 		return
 	}
-	if err = c.state.SaveOffset(req["offset"]); err != nil {
+	if err = c.state.SaveOffset(*req.Offset); err != nil {
 		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to save offset: %v", err))
 		return
 	}
@@ -252,6 +491,21 @@ func (c *BaseClient) getSignatureByIDHandler(w http.ResponseWriter, r *http.Requ
 	successResponse(w, signature)
 }
 
+func (c *BaseClient) getRejectedSignaturesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return
+	}
+
+	signatures, err := c.GetRejectedSignatures(r.URL.Query().Get("dkgID"))
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get rejected signatures: %v", err))
+		return
+	}
+
+	successResponse(w, signatures)
+}
+
 func (c *BaseClient) getOperationQRPathHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
@@ -320,14 +574,8 @@ func (c *BaseClient) startDKGHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	dkgRoundID := md5.Sum(reqBody)
-	message, err := c.buildMessage(hex.EncodeToString(dkgRoundID[:]), spf.EventInitProposal, reqBody)
-	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to build message: %v", err))
-		return
-	}
-	if err = c.SendMessage(*message); err != nil {
-		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to send message: %v", err))
+	if _, err = c.startDKG(reqBody); err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to start DKG: %v", err))
 		return
 	}
 	successResponse(w, "ok")
@@ -345,36 +593,131 @@ func (c *BaseClient) proposeSignDataHandler(w http.ResponseWriter, r *http.Reque
 	}
 	defer r.Body.Close()
 
-	var req map[string][]byte
+	var req api.ProposeSignRequest
 	if err = json.Unmarshal(reqBody, &req); err != nil {
 		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to umarshal request: %v", err))
 		return
 	}
 
-	fsmInstance, err := c.getFSMInstance(hex.EncodeToString(req["dkgID"]))
-	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get FSM instance: %v", err))
+	if _, err = c.proposeSignObject(hex.EncodeToString(req.DKGID), req.Data, req.SigningObject); err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to propose signing: %v", err))
 		return
 	}
-	participantID, err := fsmInstance.GetIDByUsername(c.GetUsername())
-	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get participantID: %v", err))
+	successResponse(w, "ok")
+}
+
+func (c *BaseClient) declineDKGHandler(w http.ResponseWriter, r *http.Request) {
+	req, participantID, ok := c.readParticipantRequest(w, r)
+	if !ok {
+		return
+	}
+
+	request := requests.SignatureProposalParticipantRequest{
+		ParticipantId: participantID,
+		Reason:        req.Reason,
+		CreatedAt:     time.Now(),
+	}
+	c.sendParticipantRequest(w, hex.EncodeToString(req.DKGID), spf.EventDeclineProposal, request)
+}
+
+func (c *BaseClient) abortDKGHandler(w http.ResponseWriter, r *http.Request) {
+	req, participantID, ok := c.readParticipantRequest(w, r)
+	if !ok {
+		return
+	}
+
+	request := requests.SignatureProposalAbortRequest{
+		ParticipantId: participantID,
+		Reason:        req.Reason,
+		CreatedAt:     time.Now(),
+	}
+	if err := request.Validate(); err != nil {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	c.sendParticipantRequest(w, hex.EncodeToString(req.DKGID), spf.EventAbortProposal, request)
+}
+
+func (c *BaseClient) declineSigningHandler(w http.ResponseWriter, r *http.Request) {
+	req, participantID, ok := c.readParticipantRequest(w, r)
+	if !ok {
+		return
+	}
+
+	request := requests.SigningProposalParticipantRequest{
+		SigningId:     req.SigningID,
+		ParticipantId: participantID,
+		Reason:        req.Reason,
+		CreatedAt:     time.Now(),
+	}
+	if err := request.Validate(); err != nil {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	c.sendParticipantRequest(w, hex.EncodeToString(req.DKGID), sif.EventDeclineSigningConfirmation, request)
+}
+
+func (c *BaseClient) abortSigningHandler(w http.ResponseWriter, r *http.Request) {
+	req, participantID, ok := c.readParticipantRequest(w, r)
+	if !ok {
 		return
 	}
 
-	messageDataSign := requests.SigningProposalStartRequest{
-		SigningID:     uuid.New().String(),
+	request := requests.SigningProposalAbortRequest{
+		SigningId:     req.SigningID,
 		ParticipantId: participantID,
-		SrcPayload:    req["data"],
+		Reason:        req.Reason,
 		CreatedAt:     time.Now(),
 	}
-	messageDataSignBz, err := json.Marshal(messageDataSign)
+	if err := request.Validate(); err != nil {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	c.sendParticipantRequest(w, hex.EncodeToString(req.DKGID), sif.EventAbortSigning, request)
+}
+
+// readParticipantRequest reads a request body of a participant's decision and resolves our participant id
+// for the requested DKG round. On failure it writes an error response and returns false
+func (c *BaseClient) readParticipantRequest(w http.ResponseWriter, r *http.Request) (*api.ParticipantDecisionRequest, int, bool) {
+	if r.Method != http.MethodPost {
+		errorResponse(w, http.StatusBadRequest, "Wrong HTTP method")
+		return nil, 0, false
+	}
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to read body: %v", err))
+		return nil, 0, false
+	}
+	defer r.Body.Close()
+
+	var req api.ParticipantDecisionRequest
+	if err = json.Unmarshal(reqBody, &req); err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to umarshal request: %v", err))
+		return nil, 0, false
+	}
+
+	fsmInstance, err := c.getFSMInstance(c.state, hex.EncodeToString(req.DKGID))
 	if err != nil {
-		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to marshal SigningProposalStartRequest: %v", err))
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get FSM instance: %v", err))
+		return nil, 0, false
+	}
+	participantID, err := fsmInstance.GetIDByUsername(c.GetUsername())
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to get participantID: %v", err))
+		return nil, 0, false
+	}
+
+	return &req, participantID, true
+}
+
+func (c *BaseClient) sendParticipantRequest(w http.ResponseWriter, dkgRoundID string, event fsm.Event, request interface{}) {
+	requestBz, err := json.Marshal(request)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to marshal request: %v", err))
 		return
 	}
 
-	message, err := c.buildMessage(hex.EncodeToString(req["dkgID"]), sif.EventSigningStart, messageDataSignBz)
+	message, err := c.buildMessage(dkgRoundID, event, requestBz)
 	if err != nil {
 		errorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to build message: %v", err))
 		return
@@ -413,6 +756,13 @@ func (c *BaseClient) handleJSONOperationHandler(w http.ResponseWriter, r *http.R
 }
 
 func (c *BaseClient) buildMessage(dkgRoundID string, event fsm.Event, data []byte) (*storage.Message, error) {
+	return c.buildStateMessage(c.state, dkgRoundID, event, data)
+}
+
+// buildStateMessage builds a message committing to the log prefix of the state, so it can be called
+// inside Atomic with the unit of work
+func (c *BaseClient) buildStateMessage(state State, dkgRoundID string, event fsm.Event,
+	data []byte) (*storage.Message, error) {
 	message := storage.Message{
 		ID:         uuid.New().String(),
 		DkgRoundID: dkgRoundID,
@@ -420,6 +770,9 @@ func (c *BaseClient) buildMessage(dkgRoundID string, event fsm.Event, data []byt
 		Data:       data,
 		SenderAddr: c.GetUsername(),
 	}
+	if err := c.commitToLog(state, &message); err != nil {
+		return nil, fmt.Errorf("failed to commit message to the log: %w", err)
+	}
 	signature, err := c.signMessage(message.Bytes())
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign message: %w", err)
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+var (
+	// ErrLogGap is returned when the append-only log skips, repeats or loses messages
+	ErrLogGap = errors.New("gap in the append-only log")
+	// ErrLogFork is returned when a message was rewritten or commits to a log prefix the client has not seen
+	ErrLogFork = errors.New("append-only log is forked")
+)
+
+// readLog returns the messages of the append-only log starting from the offset. The last processed message
+// is read again to check that it was not rewritten after the client had processed it
+func (c *BaseClient) readLog(offset uint64) ([]storage.Message, error) {
+	if offset == 0 {
+		return c.storage.GetMessages(offset)
+	}
+
+	messages, err := c.storage.GetMessages(offset - 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 || messages[0].Offset != offset-1 {
+		return nil, fmt.Errorf("%w: processed message with offset %d is missing", ErrLogGap, offset-1)
+	}
+
+	prevHead, err := c.state.GetHeadHash(offset - 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head hash: %w", err)
+	}
+	head, err := c.state.GetHeadHash(offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head hash: %w", err)
+	}
+	if prevHead != nil && head != nil && !bytes.Equal(storage.NextHeadHash(prevHead, messages[0]), head) {
+		return nil, fmt.Errorf("%w: processed message with offset %d was rewritten", ErrLogFork, offset-1)
+	}
+
+	return messages[1:], nil
+}
+
+// verifyLogLink checks that the message is the next message of the log and that it commits to a log prefix
+// the client has seen. A message appended by an honest participant to a forked or rewritten log commits
+// to a prefix with a different head hash. The commitment can't be verified if the head hash of the prefix
+// is unknown, e.g. after the offset is moved by hand, false is returned then
+func (c *BaseClient) verifyLogLink(message storage.Message) (bool, error) {
+	offset, err := c.state.LoadOffset()
+	if err != nil {
+		return false, fmt.Errorf("failed to LoadOffset: %w", err)
+	}
+	if message.Offset != offset {
+		return false, fmt.Errorf("%w: expected message with offset %d, got %d", ErrLogGap, offset, message.Offset)
+	}
+
+	if !message.Committed() {
+		return true, nil
+	}
+	if message.PrevOffset > message.Offset {
+		return false, fmt.Errorf("%w: message %s from %s with offset %d commits to %d messages", ErrLogFork,
+			message.ID, message.SenderAddr, message.Offset, message.PrevOffset)
+	}
+	head, err := c.state.GetHeadHash(message.PrevOffset)
+	if err != nil {
+		return false, fmt.Errorf("failed to get head hash: %w", err)
+	}
+	if head == nil {
+		return false, nil
+	}
+	if !bytes.Equal(head, message.PrevHash) {
+		return false, fmt.Errorf("%w: message %s from %s commits to head hash %x of the first %d messages, ours is %x",
+			ErrLogFork, message.ID, message.SenderAddr, message.PrevHash, message.PrevOffset, head)
+	}
+
+	return true, nil
+}
+
+// pollMessage verifies that the message extends the log the client has seen and handles it. A message which
+// commits to another log is put to the dead-letter list: the sender has seen a forked log, but the log we read
+// is still consistent, so other DKG rounds and signings go on and the participants can compare checkpoints.
+// The error is returned only if our own log skips messages or is rewritten, nothing can be trusted then
+func (c *BaseClient) pollMessage(message storage.Message) error {
+	verified, err := c.verifyLogLink(message)
+	if errors.Is(err, ErrLogFork) {
+		c.Logger.Log("Message with offset %d is refused: %v", message.Offset, err)
+		event := messageEvent(api.EventLogForkDetected, message)
+		event.Error = err.Error()
+		c.events.publish(event)
+		if err = c.saveDeadLetter(message, err); err != nil {
+			return fmt.Errorf("failed to save dead letter: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		event := messageEvent(api.EventLogForkDetected, message)
+		event.Error = err.Error()
+		c.events.publish(event)
+		return err
+	}
+	if !verified {
+		c.Logger.Log("Head hash of the first %d messages is unknown, the commitment of message %s is not verified",
+			message.PrevOffset, message.ID)
+		event := messageEvent(api.EventLogUnverified, message)
+		event.Error = fmt.Sprintf("head hash of the first %d messages is unknown", message.PrevOffset)
+		c.events.publish(event)
+	}
+
+	c.handleMessage(message)
+	return nil
+}
+
+// advanceLog moves the offset past the message and extends the head hash chain with the message.
+// The chain is not extended if the messages before were skipped by moving the offset by hand
+func advanceLog(state State, message storage.Message) error {
+	head, err := state.GetHeadHash(message.Offset)
+	if err != nil {
+		return fmt.Errorf("failed to get head hash: %w", err)
+	}
+	if head != nil {
+		if err = state.SaveHeadHash(message.Offset+1, storage.NextHeadHash(head, message)); err != nil {
+			return fmt.Errorf("failed to save head hash: %w", err)
+		}
+	}
+	if err = state.SaveOffset(message.Offset + 1); err != nil {
+		return fmt.Errorf("failed to save offset: %w", err)
+	}
+
+	return nil
+}
+
+// commitToLog commits the message to the log prefix the client has processed, so it must be called
+// before the message is signed
+func (c *BaseClient) commitToLog(state State, message *storage.Message) error {
+	offset, err := state.LoadOffset()
+	if err != nil {
+		return fmt.Errorf("failed to LoadOffset: %w", err)
+	}
+	head, err := state.GetHeadHash(offset)
+	if err != nil {
+		return fmt.Errorf("failed to get head hash: %w", err)
+	}
+	if head != nil {
+		message.PrevOffset = offset
+		message.PrevHash = head
+	}
+
+	return nil
+}
+
+// processLogCheckpoint saves the head hash published by a participant of the DKG round, Poll has already
+// checked that the head hash matches ours
+func (c *BaseClient) processLogCheckpoint(state State, message storage.Message) error {
+	fsmInstance, err := c.getFSMInstance(state, message.DkgRoundID)
+	if err != nil {
+		return fmt.Errorf("failed to getFSMInstance: %w", err)
+	}
+	if err = c.verifyMessage(fsmInstance, message); err != nil {
+		return fmt.Errorf("failed to verifyMessage: %w", err)
+	}
+	if !message.Committed() {
+		return errors.New("log checkpoint does not commit to the log")
+	}
+
+	return state.PutLogCheckpoint(types.LogCheckpoint{
+		DKGRoundID:    message.DkgRoundID,
+		Username:      message.SenderAddr,
+		Offset:        message.PrevOffset,
+		HeadHash:      message.PrevHash,
+		MessageOffset: message.Offset,
+	})
+}
+
+// PublishLogCheckpoint sends our head hash of the log to the log on behalf of a participant of the DKG round,
+// other participants refuse it if they see a different log
+func (c *BaseClient) PublishLogCheckpoint(dkgRoundID string) error {
+	if _, ok, err := c.state.LoadFSM(dkgRoundID); err != nil {
+		return fmt.Errorf("failed to load FSM: %w", err)
+	} else if !ok {
+		return fmt.Errorf("DKG round %s not found", dkgRoundID)
+	}
+
+	message, err := c.buildMessage(dkgRoundID, types.LogCheckpointPublished, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+	if !message.Committed() {
+		return errors.New("head hash of the log is unknown, rebuild the state to restore it")
+	}
+
+	return c.SendMessage(*message)
+}
+
+// GetLogCheckpoints returns our head hash of the log and the head hashes published by participants
+func (c *BaseClient) GetLogCheckpoints() (*api.LogCheckpoints, error) {
+	offset, err := c.state.LoadOffset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to LoadOffset: %w", err)
+	}
+	head, err := c.state.GetHeadHash(offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head hash: %w", err)
+	}
+	checkpoints, err := c.state.GetLogCheckpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log checkpoints: %w", err)
+	}
+	if checkpoints == nil {
+		checkpoints = []types.LogCheckpoint{}
+	}
+
+	return &api.LogCheckpoints{
+		Offset:      offset,
+		HeadHash:    head,
+		Verified:    head != nil,
+		Checkpoints: checkpoints,
+	}, nil
+}
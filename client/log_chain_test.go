@@ -0,0 +1,130 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// rewritingStorage returns the message with the offset with different data, as a malicious log operator could
+type rewritingStorage struct {
+	storage.Storage
+	offset uint64
+}
+
+func (s rewritingStorage) GetMessages(offset uint64) ([]storage.Message, error) {
+	messages, err := s.Storage.GetMessages(offset)
+	for i := range messages {
+		if messages[i].Offset == s.offset {
+			messages[i].Data = []byte("rewritten")
+		}
+	}
+	return messages, err
+}
+
+func TestBaseClient_LogChain(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_log_chain")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+	state, stg := baseClient.state, round.storage
+	poll := func() error {
+		offset, err := state.LoadOffset()
+		req.NoError(err)
+		messages, err := baseClient.readLog(offset)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if err = baseClient.pollMessage(message); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	proposal, err := json.Marshal(round.initRequest)
+	req.NoError(err)
+	dkgRoundID, err := baseClient.startDKG(proposal)
+	req.NoError(err)
+	req.NoError(poll())
+
+	// a checkpoint commits to the head of the log the node has processed
+	head, err := state.GetHeadHash(1)
+	req.NoError(err)
+	req.NotNil(head)
+	req.NoError(baseClient.PublishLogCheckpoint(dkgRoundID))
+	req.NoError(poll())
+	checkpoints, err := baseClient.GetLogCheckpoints()
+	req.NoError(err)
+	req.Equal(uint64(2), checkpoints.Offset)
+	req.Len(checkpoints.Checkpoints, 1)
+	req.Equal("alice", checkpoints.Checkpoints[0].Username)
+	req.Equal(uint64(1), checkpoints.Checkpoints[0].Offset)
+	req.Equal(head, checkpoints.Checkpoints[0].HeadHash)
+
+	// a participant who has seen another log
+	events, unsubscribe := baseClient.SubscribeEvents()
+	defer unsubscribe()
+	_, err = stg.Send(storage.Message{
+		DkgRoundID: dkgRoundID,
+		Event:      "log_checkpoint_published",
+		SenderAddr: "bob",
+		PrevOffset: 1,
+		PrevHash:   storage.GenesisHeadHash,
+	})
+	req.NoError(err)
+	// the message is dead-lettered, but the node goes on polling
+	req.NoError(poll())
+	event := <-events
+	req.Equal(api.EventLogForkDetected, event.Type)
+	req.Equal(uint64(2), event.MessageOffset)
+	offset, err := state.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(3), offset)
+	letters, err := state.GetDeadLetters()
+	req.NoError(err)
+	req.Len(letters, 1)
+	req.Equal(uint64(2), letters[0].Message.Offset)
+	req.Contains(letters[0].Error, ErrLogFork.Error())
+	req.False(letters[0].Retryable)
+
+	_, err = baseClient.verifyLogLink(storage.Message{Offset: 4})
+	req.True(errors.Is(err, ErrLogGap), err)
+
+	// the processed message is changed after the node has processed it
+	baseClient.storage = rewritingStorage{Storage: stg, offset: 2}
+	err = poll()
+	req.True(errors.Is(err, ErrLogFork), err)
+	baseClient.storage = stg
+
+	// after the offset is moved by hand past a message the head hash is unknown, so the commitments
+	// of new messages can't be verified
+	_, err = stg.Send(storage.Message{DkgRoundID: dkgRoundID, Event: "skipped", SenderAddr: "bob"})
+	req.NoError(err)
+	req.NoError(state.SaveOffset(4))
+	checkpoints, err = baseClient.GetLogCheckpoints()
+	req.NoError(err)
+	req.False(checkpoints.Verified)
+	_, err = stg.Send(storage.Message{
+		DkgRoundID: dkgRoundID,
+		Event:      "log_checkpoint_published",
+		SenderAddr: "bob",
+		PrevOffset: 4,
+		PrevHash:   storage.GenesisHeadHash,
+	})
+	req.NoError(err)
+	req.NoError(poll())
+	event = <-events
+	req.Equal(api.EventLogUnverified, event.Type)
+	req.Equal(uint64(4), event.MessageOffset)
+	offset, err = state.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(5), offset)
+}
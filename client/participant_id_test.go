@@ -0,0 +1,75 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_ForgedParticipantID(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_participant_id")
+	defer round.close()
+
+	carol := round.newClient("carol")
+	state := carol.state
+
+	initRequest := round.initRequest
+	initData, err := json.Marshal(initRequest)
+	req.NoError(err)
+	req.NoError(carol.processMessageAtomically(storage.Message{
+		DkgRoundID: "dkg_round_id",
+		Event:      string(spf.EventInitProposal),
+		Data:       initData,
+		SenderAddr: "alice",
+	}, nil))
+
+	fsmInstance, err := carol.getFSMInstance(state, "dkg_round_id")
+	req.NoError(err)
+	aliceID, err := fsmInstance.GetIDByUsername("alice")
+	req.NoError(err)
+	signedMessage := func(sender string, event string, request interface{}) storage.Message {
+		data, err := json.Marshal(request)
+		req.NoError(err)
+		message := storage.Message{
+			DkgRoundID: "dkg_round_id",
+			Event:      event,
+			Data:       data,
+			SenderAddr: sender,
+		}
+		message.Signature = ed25519.Sign(round.keyPair(sender).Priv, message.Bytes())
+		return message
+	}
+	abortRequest := requests.SignatureProposalAbortRequest{
+		ParticipantId: aliceID,
+		Reason:        "aborted",
+		CreatedAt:     time.Now(),
+	}
+
+	// bob signs the abort himself, but claims to be the initiator
+	err = carol.processMessageAtomically(signedMessage("bob", string(spf.EventAbortProposal), abortRequest), nil)
+	req.Error(err)
+	req.Contains(err.Error(), "does not belong to the sender bob")
+	// bob can't decline the proposal on behalf of alice either
+	err = carol.processMessageAtomically(signedMessage("bob", string(spf.EventDeclineProposal),
+		requests.SignatureProposalParticipantRequest{ParticipantId: aliceID, Reason: "declined",
+			CreatedAt: time.Now()}), nil)
+	req.Error(err)
+
+	fsmInstance, err = carol.getFSMInstance(state, "dkg_round_id")
+	req.NoError(err)
+	req.Equal(spf.StateAwaitParticipantsConfirmations, fsmInstance.FSMDump().State)
+
+	req.NoError(carol.processMessageAtomically(signedMessage("alice", string(spf.EventAbortProposal),
+		abortRequest), nil))
+	fsmInstance, err = carol.getFSMInstance(state, "dkg_round_id")
+	req.NoError(err)
+	req.Equal("alice", fsmInstance.FSMDump().Payload.SignatureProposalPayload.AbortedBy)
+}
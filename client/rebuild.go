@@ -0,0 +1,288 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// answeredOperationTypes maps events sent by the airgapped machine to the types of operations they answer
+var answeredOperationTypes = map[fsm.Event]fsm.State{
+	spf.EventConfirmSignatureProposal:         spf.StateAwaitParticipantsConfirmations,
+	spf.EventDeclineProposal:                  spf.StateAwaitParticipantsConfirmations,
+	dpf.EventDKGCommitConfirmationReceived:    dpf.StateDkgCommitsAwaitConfirmations,
+	dpf.EventDKGCommitConfirmationError:       dpf.StateDkgCommitsAwaitConfirmations,
+	dpf.EventDKGDealConfirmationReceived:      dpf.StateDkgDealsAwaitConfirmations,
+	dpf.EventDKGDealConfirmationError:         dpf.StateDkgDealsAwaitConfirmations,
+	dpf.EventDKGResponseConfirmationReceived:  dpf.StateDkgResponsesAwaitConfirmations,
+	dpf.EventDKGResponseConfirmationError:     dpf.StateDkgResponsesAwaitConfirmations,
+	dpf.EventDKGMasterKeyConfirmationReceived: dpf.StateDkgMasterKeyAwaitConfirmations,
+	dpf.EventDKGMasterKeyConfirmationError:    dpf.StateDkgMasterKeyAwaitConfirmations,
+	sipf.EventConfirmSigningConfirmation:      sipf.StateSigningAwaitConfirmations,
+	sipf.EventDeclineSigningConfirmation:      sipf.StateSigningAwaitConfirmations,
+	sipf.EventSigningPartialSignReceived:      sipf.StateSigningAwaitPartialSigns,
+	sipf.EventSigningPartialSignError:         sipf.StateSigningAwaitPartialSigns,
+}
+
+// errReplayReadOnly is returned when the client tries to send a message while the log is replayed,
+// everything the client could send is already on the log
+var errReplayReadOnly = errors.New("messages are not sent while the log is replayed")
+
+// replayStorage gives read-only access to the append-only log
+type replayStorage struct {
+	storage.Storage
+}
+
+func (s replayStorage) Send(storage.Message) (storage.Message, error) {
+	return storage.Message{}, errReplayReadOnly
+}
+
+func (s replayStorage) SendBatch(...storage.Message) ([]storage.Message, error) {
+	return nil, errReplayReadOnly
+}
+
+// RebuildReport compares the client state with the state rebuilt from the append-only log
+type RebuildReport struct {
+	// Messages is the number of replayed messages
+	Messages      int
+	CurrentOffset uint64
+	RebuiltOffset uint64
+	// AnsweredOperations are the operations the replay created, but which are already answered on the log,
+	// so they are dropped from the rebuilt state
+	AnsweredOperations []string
+	// DeadLetters are the messages which failed during the replay
+	DeadLetters []types.DeadLetter
+	// Differences describe how the current state diverges from the rebuilt one
+	Differences []string
+}
+
+// RebuildState replays the whole append-only log into the empty rebuilt state and compares the result
+// with the client state. The client state is not changed
+func (c *BaseClient) RebuildState(rebuilt State) (*RebuildReport, error) {
+	messages, err := c.storage.GetMessages(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetMessages: %w", err)
+	}
+
+	replayClient := &BaseClient{
+		Logger:                   c.Logger,
+		userName:                 c.userName,
+		pubKey:                   c.pubKey,
+		ctx:                      c.ctx,
+		state:                    rebuilt,
+		storage:                  replayStorage{c.storage},
+		keyStore:                 c.keyStore,
+		qrProcessor:              c.qrProcessor,
+		SkipCommKeysVerification: c.SkipCommKeysVerification,
+		events:                   newEventBus(c.Logger),
+	}
+
+	report := &RebuildReport{Messages: len(messages)}
+	for _, message := range messages {
+		if err = replayClient.pollMessage(message); err != nil {
+			return nil, fmt.Errorf("failed to replay message with offset %d: %w", message.Offset, err)
+		}
+		answered, err := replayClient.dropAnsweredOperations(message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to drop answered operations: %w", err)
+		}
+		report.AnsweredOperations = append(report.AnsweredOperations, answered...)
+	}
+
+	if report.DeadLetters, err = rebuilt.GetDeadLetters(); err != nil {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+	if report.CurrentOffset, err = c.state.LoadOffset(); err != nil {
+		return nil, fmt.Errorf("failed to load current offset: %w", err)
+	}
+	if report.RebuiltOffset, err = rebuilt.LoadOffset(); err != nil {
+		return nil, fmt.Errorf("failed to load rebuilt offset: %w", err)
+	}
+	if report.CurrentOffset != report.RebuiltOffset {
+		report.Differences = append(report.Differences, fmt.Sprintf("offset: current %d, rebuilt %d",
+			report.CurrentOffset, report.RebuiltOffset))
+	} else {
+		currentHead, err := c.state.GetHeadHash(report.CurrentOffset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current head hash: %w", err)
+		}
+		rebuiltHead, err := rebuilt.GetHeadHash(report.RebuiltOffset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rebuilt head hash: %w", err)
+		}
+		if !bytes.Equal(currentHead, rebuiltHead) {
+			report.Differences = append(report.Differences, fmt.Sprintf("head hash: current %x, rebuilt %x",
+				currentHead, rebuiltHead))
+		}
+	}
+
+	differences, err := c.diffState(rebuilt)
+	if err != nil {
+		return nil, err
+	}
+	report.Differences = append(report.Differences, differences...)
+
+	return report, nil
+}
+
+// dropAnsweredOperations removes the operations which are answered by our message,
+// the airgapped machine must not process them twice
+func (c *BaseClient) dropAnsweredOperations(message storage.Message) ([]string, error) {
+	operationType, ok := answeredOperationTypes[fsm.Event(message.Event)]
+	if !ok || message.SenderAddr != c.GetUsername() {
+		return nil, nil
+	}
+	fsmReq, err := types.FSMRequestFromMessage(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FSMRequestFromMessage: %w", err)
+	}
+	// signingID is empty for the events of the DKG round itself
+	signingID, _ := state_machines.GetSigningID(fsmReq)
+
+	operations, err := c.state.GetOperations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operations: %w", err)
+	}
+
+	var answered []string
+	for id, operation := range operations {
+		if operation.DKGIdentifier != message.DkgRoundID || fsm.State(operation.Type) != operationType {
+			continue
+		}
+		if signingID != "" && operationSigningID(operation) != signingID {
+			continue
+		}
+		if err = c.state.DeleteOperation(id); err != nil {
+			return nil, fmt.Errorf("failed to DeleteOperation: %w", err)
+		}
+		answered = append(answered, id)
+	}
+
+	return answered, nil
+}
+
+// operationSigningID returns the ID of the signing the operation belongs to, if any
+func operationSigningID(operation *types.Operation) string {
+	var payload struct{ SigningId string }
+	if err := json.Unmarshal(operation.Payload, &payload); err != nil {
+		return ""
+	}
+	return payload.SigningId
+}
+
+// diffState compares FSMs, operations and signatures of the client state with the rebuilt state.
+// Timestamps differ after a replay, so FSMs are compared by the states of the rounds, signings and participants
+func (c *BaseClient) diffState(rebuilt State) ([]string, error) {
+	current, err := c.stateSummary(c.state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize current state: %w", err)
+	}
+	replayed, err := c.stateSummary(rebuilt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize rebuilt state: %w", err)
+	}
+
+	keys := make([]string, 0, len(current)+len(replayed))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range replayed {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var differences []string
+	for _, key := range keys {
+		currentValue, inCurrent := current[key]
+		replayedValue, inReplayed := replayed[key]
+		switch {
+		case !inReplayed:
+			differences = append(differences, fmt.Sprintf("%s: %q is not on the log", key, currentValue))
+		case !inCurrent:
+			differences = append(differences, fmt.Sprintf("%s: %q is missing in the current state", key, replayedValue))
+		case currentValue != replayedValue:
+			differences = append(differences, fmt.Sprintf("%s: current %q, rebuilt %q", key, currentValue,
+				replayedValue))
+		}
+	}
+
+	return differences, nil
+}
+
+// stateSummary describes the state as a map of comparable values, e.g. a status of a participant
+// in a phase of the DKG round
+func (c *BaseClient) stateSummary(state State) (map[string]string, error) {
+	fsmInstances, err := state.GetAllFSM()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FSM instances: %w", err)
+	}
+
+	summary := make(map[string]string)
+	for dkgRoundID, fsmInstance := range fsmInstances {
+		round := fmt.Sprintf("DKG round %s", dkgRoundID)
+		status := newRoundStatus(dkgRoundID, fsmInstance.FSMDump(), c.GetUsername(), nil, time.Time{})
+		summary[round+" state"] = status.State
+		for _, phase := range status.Phases {
+			addPhaseSummary(summary, round+" "+phase.Name, phase)
+		}
+		for _, signing := range status.Signings {
+			signingKey := fmt.Sprintf("%s signing %s", round, signing.SigningID)
+			summary[signingKey+" state"] = signing.State
+			addPhaseSummary(summary, signingKey, signing.Phase)
+		}
+
+		signatures, err := state.GetSignatures(dkgRoundID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get signatures: %w", err)
+		}
+		for signingID, signingSignatures := range signatures {
+			entries := make([]string, 0, len(signingSignatures))
+			for _, signature := range signingSignatures {
+				entry := fmt.Sprintf("%s:%s", signature.Username, hex.EncodeToString(signature.Signature))
+				if signature.VerificationError != "" {
+					entry += ":rejected"
+				}
+				entries = append(entries, entry)
+			}
+			sort.Strings(entries)
+			summary[fmt.Sprintf("%s signing %s signatures", round, signingID)] = strings.Join(entries, ", ")
+		}
+	}
+
+	operations, err := state.GetOperations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operations: %w", err)
+	}
+	// the payloads of operations can have timestamps, so they are compared by types
+	for _, operation := range operations {
+		key := fmt.Sprintf("DKG round %s operation %s", operation.DKGIdentifier, operation.Type)
+		if signingID := operationSigningID(operation); signingID != "" {
+			key += " of signing " + signingID
+		}
+		summary[key] = "pending"
+	}
+
+	return summary, nil
+}
+
+func addPhaseSummary(summary map[string]string, key string, phase api.PhaseStatus) {
+	for _, participant := range phase.Participants {
+		summary[fmt.Sprintf("%s participant %s", key, participant.Username)] = participant.Status
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_RebuildState(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_rebuild_state")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+	baseClient.SkipCommKeysVerification = true
+	state, stg := baseClient.state, round.storage
+
+	dkgRoundID := "dkg_round_id"
+	initRequest := round.initRequest
+	initData, err := json.Marshal(initRequest)
+	req.NoError(err)
+
+	fsmInstance, err := state_machines.Create(dkgRoundID)
+	req.NoError(err)
+	_, _, err = fsmInstance.Do(spf.EventInitProposal, initRequest)
+	req.NoError(err)
+	confirmMessage := func(username string) storage.Message {
+		participantID, err := fsmInstance.GetIDByUsername(username)
+		req.NoError(err)
+		confirmData, err := json.Marshal(requests.SignatureProposalParticipantRequest{
+			ParticipantId: participantID,
+			CreatedAt:     time.Now(),
+		})
+		req.NoError(err)
+		return storage.Message{
+			DkgRoundID: dkgRoundID,
+			Event:      string(spf.EventConfirmSignatureProposal),
+			Data:       confirmData,
+			SenderAddr: username,
+		}
+	}
+
+	messages, err := stg.SendBatch(
+		storage.Message{
+			DkgRoundID: dkgRoundID,
+			Event:      string(spf.EventInitProposal),
+			Data:       initData,
+			SenderAddr: "alice",
+		},
+		confirmMessage("bob"),
+		confirmMessage("alice"),
+	)
+	req.NoError(err)
+
+	// the node has not seen our own confirmation yet
+	for _, message := range messages[:2] {
+		baseClient.handleMessage(message)
+	}
+	operations, err := state.GetOperations()
+	req.NoError(err)
+	req.Len(operations, 1)
+
+	rebuilt, err := NewLevelDBState(filepath.Join(round.dir, "rebuilt"), "test_topic")
+	req.NoError(err)
+	report, err := baseClient.RebuildState(rebuilt)
+	req.NoError(err)
+	req.Equal(3, report.Messages)
+	req.Equal(uint64(2), report.CurrentOffset)
+	req.Equal(uint64(3), report.RebuiltOffset)
+	req.Len(report.AnsweredOperations, 1)
+	req.Empty(report.DeadLetters)
+	req.Len(report.Differences, 3)
+
+	rebuiltOperations, err := rebuilt.GetOperations()
+	req.NoError(err)
+	req.Empty(rebuiltOperations)
+
+	// the state doesn't diverge from the log once the node catches up
+	baseClient.handleMessage(messages[2])
+	for id := range operations {
+		req.NoError(state.DeleteOperation(id))
+	}
+	verified, err := NewLevelDBState(filepath.Join(round.dir, "verified"), "test_topic")
+	req.NoError(err)
+	report, err = baseClient.RebuildState(verified)
+	req.NoError(err)
+	req.Empty(report.Differences)
+}
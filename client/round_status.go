@@ -0,0 +1,223 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+)
+
+// roundAwaitStates are states of a DKG round which wait for the participants
+var roundAwaitStates = map[fsm.State]bool{
+	spf.StateAwaitParticipantsConfirmations: true,
+	dpf.StateDkgCommitsAwaitConfirmations:   true,
+	dpf.StateDkgDealsAwaitConfirmations:     true,
+	dpf.StateDkgResponsesAwaitConfirmations: true,
+	dpf.StateDkgMasterKeyAwaitConfirmations: true,
+}
+
+// signingAwaitStates are states of a signing session which wait for the participants
+var signingAwaitStates = map[fsm.State]bool{
+	sipf.StateSigningAwaitConfirmations: true,
+	sipf.StateSigningAwaitPartialSigns:  true,
+}
+
+// dkgPhaseName returns a name of the key generation phase by the state of the DKG round
+func dkgPhaseName(state fsm.State) string {
+	switch {
+	case strings.HasPrefix(string(state), "state_dkg_commits"):
+		return "DKG commits"
+	case strings.HasPrefix(string(state), "state_dkg_deals"):
+		return "DKG deals"
+	case strings.HasPrefix(string(state), "state_dkg_responses"):
+		return "DKG responses"
+	case strings.HasPrefix(string(state), "state_dkg_master_key"):
+		return "DKG master key"
+	default:
+		return "DKG"
+	}
+}
+
+func newPhaseStatus(name string, deadline, now time.Time) api.PhaseStatus {
+	return api.PhaseStatus{
+		Name:         name,
+		Deadline:     deadline,
+		TimeLeft:     deadline.Sub(now),
+		Participants: []api.ParticipantProgress{},
+	}
+}
+
+// addParticipant adds a participant to the phase, every phase marks the participants it waits for
+// with an "...Await..." status, e.g. CommitAwaitConfirmation or SigningAwaitPartialSigns
+func addParticipant(phase *api.PhaseStatus, username string, status fmt.Stringer, errorMessage string) {
+	phase.Participants = append(phase.Participants, api.ParticipantProgress{
+		Username: username,
+		Status:   status.String(),
+		Acted:    !strings.Contains(status.String(), "Await"),
+		Error:    errorMessage,
+	})
+}
+
+// nextAction returns what the user must do in the phase which waits for the participants
+func nextAction(phase api.PhaseStatus, username string, hasOperation bool) string {
+	waiting := phase.Waiting()
+	for _, waitingUsername := range waiting {
+		if waitingUsername != username {
+			continue
+		}
+		if hasOperation {
+			return "Process the pending operation on the airgapped machine"
+		}
+		return "Wait until the operation for the airgapped machine appears"
+	}
+	if len(waiting) > 0 {
+		return fmt.Sprintf("Wait for %s", strings.Join(waiting, ", "))
+	}
+	return ""
+}
+
+// newRoundStatus summarizes the DKG round and its signings for the user, operations are the pending
+// operations of the round
+func newRoundStatus(
+	dkgRoundID string,
+	dump *state_machines.FSMDump,
+	username string,
+	operations []*types.Operation,
+	now time.Time,
+) api.RoundStatus {
+	status := api.RoundStatus{
+		DKGRoundID:        dkgRoundID,
+		State:             string(dump.State),
+		Phases:            []api.PhaseStatus{},
+		Signings:          []api.SigningStatus{},
+		PendingOperations: []string{},
+	}
+	operationTypes := make(map[types.OperationType]bool)
+	for _, operation := range operations {
+		status.PendingOperations = append(status.PendingOperations, operation.ID)
+		operationTypes[operation.Type] = true
+	}
+
+	payload := dump.Payload
+	if payload == nil {
+		return status
+	}
+	status.Threshold = payload.Threshold
+
+	if payload.SignatureProposalPayload != nil {
+		status.CreatedAt = payload.SignatureProposalPayload.CreatedAt
+		status.AbortedBy = payload.SignatureProposalPayload.AbortedBy
+		status.AbortReason = payload.SignatureProposalPayload.AbortReason
+		phase := newPhaseStatus("Participation confirmation", payload.SignatureProposalPayload.ExpiresAt, now)
+		for _, participant := range payload.SignatureProposalPayload.Quorum.GetOrderedParticipants() {
+			addParticipant(&phase, participant.Username, participant.Status, participant.DeclineReason)
+		}
+		status.Phases = append(status.Phases, phase)
+	}
+
+	if payload.DKGProposalPayload != nil {
+		phase := newPhaseStatus(dkgPhaseName(dump.State), payload.DKGProposalPayload.ExpiresAt, now)
+		for _, participant := range payload.DKGProposalPayload.Quorum.GetOrderedParticipants() {
+			var errorMessage string
+			if participant.Error != nil {
+				errorMessage = participant.Error.Error()
+			}
+			addParticipant(&phase, participant.Username, participant.Status, errorMessage)
+			// deals are private messages, so a participant doesn't wait for its own deal
+			if participant.Username == username && participant.Status.String() == "DealAwaitConfirmation" {
+				phase.Participants[len(phase.Participants)-1].Acted = true
+			}
+		}
+		status.Phases = append(status.Phases, phase)
+	}
+
+	if roundAwaitStates[dump.State] && len(status.Phases) > 0 {
+		status.NextAction = nextAction(status.Phases[len(status.Phases)-1], username, operationTypes[types.OperationType(dump.State)])
+	}
+
+	for signingID, session := range payload.SigningProposals {
+		if session == nil || session.Payload == nil {
+			continue
+		}
+		signing := api.SigningStatus{
+			SigningID:   signingID,
+			State:       string(session.State),
+			Data:        session.Payload.SrcPayload,
+			CreatedAt:   session.Payload.CreatedAt,
+			Phase:       newPhaseStatus("Signing", session.Payload.ExpiresAt, now),
+			AbortedBy:   session.Payload.AbortedBy,
+			AbortReason: session.Payload.AbortReason,
+		}
+		for _, participant := range session.Payload.Quorum.GetOrderedParticipants() {
+			errorMessage := participant.DeclineReason
+			if participant.Error != nil {
+				errorMessage = participant.Error.Error()
+			}
+			addParticipant(&signing.Phase, participant.Username, participant.Status, errorMessage)
+		}
+		if signingAwaitStates[session.State] {
+			signing.NextAction = nextAction(signing.Phase, username, operationTypes[types.OperationType(session.State)])
+		}
+		status.Signings = append(status.Signings, signing)
+	}
+	sort.Slice(status.Signings, func(i, j int) bool {
+		return status.Signings[i].CreatedAt.After(status.Signings[j].CreatedAt)
+	})
+
+	if status.NextAction == "" && len(status.PendingOperations) > 0 {
+		status.NextAction = fmt.Sprintf("Process %d pending operation(s) on the airgapped machine", len(status.PendingOperations))
+	}
+
+	return status
+}
+
+// GetRoundStatus returns summaries of the DKG round and its signings, or of all DKG rounds
+// if dkgID is empty. The most recent rounds go first
+func (c *BaseClient) GetRoundStatus(dkgID string) ([]api.RoundStatus, error) {
+	fsmInstances, err := c.state.GetAllFSM()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FSM instances: %w", err)
+	}
+	if dkgID != "" {
+		fsmInstance, ok := fsmInstances[dkgID]
+		if !ok {
+			return nil, fmt.Errorf("DKG round %s not found", dkgID)
+		}
+		fsmInstances = map[string]*state_machines.FSMInstance{dkgID: fsmInstance}
+	}
+
+	operations, err := c.state.GetOperations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operations: %w", err)
+	}
+	roundOperations := make(map[string][]*types.Operation)
+	for _, operation := range operations {
+		roundOperations[operation.DKGIdentifier] = append(roundOperations[operation.DKGIdentifier], operation)
+	}
+
+	now := time.Now()
+	statuses := make([]api.RoundStatus, 0, len(fsmInstances))
+	for dkgRoundID, fsmInstance := range fsmInstances {
+		operations := roundOperations[dkgRoundID]
+		sort.Slice(operations, func(i, j int) bool {
+			return operations[i].CreatedAt.Before(operations[j].CreatedAt)
+		})
+		statuses = append(statuses, newRoundStatus(dkgRoundID, fsmInstance.FSMDump(), c.GetUsername(), operations, now))
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if !statuses[i].CreatedAt.Equal(statuses[j].CreatedAt) {
+			return statuses[i].CreatedAt.After(statuses[j].CreatedAt)
+		}
+		return statuses[i].DKGRoundID < statuses[j].DKGRoundID
+	})
+
+	return statuses, nil
+}
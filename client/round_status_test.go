@@ -0,0 +1,64 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_GetRoundStatus(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_round_status")
+	defer round.close()
+
+	baseClient := round.newClient("bob")
+	state := baseClient.state
+
+	dkgRoundID := "dkg_round_id"
+	fsmInstance, err := state_machines.Create(dkgRoundID)
+	req.NoError(err)
+	createdAt := round.initRequest.CreatedAt
+	_, _, err = fsmInstance.Do(spf.EventInitProposal, round.initRequest)
+	req.NoError(err)
+
+	aliceID, err := fsmInstance.GetIDByUsername("alice")
+	req.NoError(err)
+	_, fsmDump, err := fsmInstance.Do(spf.EventConfirmSignatureProposal, requests.SignatureProposalParticipantRequest{
+		ParticipantId: aliceID,
+		CreatedAt:     createdAt,
+	})
+	req.NoError(err)
+	req.NoError(state.SaveFSM(dkgRoundID, fsmDump))
+
+	operation := types.NewOperation(dkgRoundID, []byte("payload"), spf.StateAwaitParticipantsConfirmations)
+	req.NoError(state.PutOperation(operation))
+
+	statuses, err := baseClient.GetRoundStatus(dkgRoundID)
+	req.NoError(err)
+	req.Len(statuses, 1)
+	status := statuses[0]
+	req.Equal(string(spf.StateAwaitParticipantsConfirmations), status.State)
+	req.Equal(2, status.Threshold)
+	req.Equal([]string{operation.ID}, status.PendingOperations)
+	req.Equal("Process the pending operation on the airgapped machine", status.NextAction)
+
+	req.Len(status.Phases, 1)
+	phase := status.Phases[0]
+	req.Equal("Participation confirmation", phase.Name)
+	req.True(phase.TimeLeft > 0)
+	req.Equal(1, phase.Acted())
+	req.ElementsMatch([]string{"bob", "carol"}, phase.Waiting())
+
+	// alice has already confirmed, so she waits for the others
+	status = newRoundStatus(dkgRoundID, fsmInstance.FSMDump(), "alice", nil, time.Now())
+	req.Equal("Wait for bob, carol", status.NextAction)
+
+	_, err = baseClient.GetRoundStatus("unknown_dkg_round_id")
+	req.Error(err)
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// testRound is a DKG round of alice, bob and carol whose client nodes share a log in a temporary directory
+type testRound struct {
+	req         *require.Assertions
+	dir         string
+	storage     storage.Storage
+	keyPairs    map[string]*KeyPair
+	initRequest requests.SignatureProposalParticipantsListRequest
+}
+
+func newTestRound(t *testing.T, dirPrefix string) *testRound {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", dirPrefix)
+	req.NoError(err)
+	stg, err := storage.NewFileStorage(filepath.Join(dir, "log"), filepath.Join(dir, "log.lock"))
+	if err != nil {
+		os.RemoveAll(dir)
+		req.NoError(err)
+	}
+
+	round := &testRound{
+		req:      req,
+		dir:      dir,
+		storage:  stg,
+		keyPairs: map[string]*KeyPair{},
+		initRequest: requests.SignatureProposalParticipantsListRequest{
+			SigningThreshold: 2,
+			CreatedAt:        time.Now(),
+		},
+	}
+	for _, username := range []string{"alice", "bob", "carol"} {
+		round.initRequest.Participants = append(round.initRequest.Participants,
+			&requests.SignatureProposalParticipantsEntry{
+				Username:  username,
+				PubKey:    round.keyPair(username).Pub,
+				DkgPubKey: make([]byte, 128),
+			})
+	}
+
+	return round
+}
+
+// keyPair returns the key pair of the user, users who are not participants get theirs on the first call
+func (r *testRound) keyPair(username string) *KeyPair {
+	if _, ok := r.keyPairs[username]; !ok {
+		r.keyPairs[username] = NewKeyPair()
+	}
+	return r.keyPairs[username]
+}
+
+// newClient returns a client node of the user with its own state and keystore
+func (r *testRound) newClient(username string) *BaseClient {
+	state, err := NewLevelDBState(filepath.Join(r.dir, username+"_state"), "test_topic")
+	r.req.NoError(err)
+	keyStore, err := NewLevelDBKeyStore(username, filepath.Join(r.dir, username+"_keystore"))
+	r.req.NoError(err)
+	keyPair := r.keyPair(username)
+	r.req.NoError(keyStore.PutKeys(username, keyPair))
+
+	clientLogger := newLogger(username)
+	return &BaseClient{
+		ctx:      context.Background(),
+		Logger:   clientLogger,
+		userName: username,
+		pubKey:   keyPair.Pub,
+		state:    state,
+		storage:  r.storage,
+		keyStore: keyStore,
+		events:   newEventBus(clientLogger),
+	}
+}
+
+func (r *testRound) close() {
+	r.storage.Close()
+	os.RemoveAll(r.dir)
+}
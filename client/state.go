@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/lidofinance/dc4bc/client/types"
 
 	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	"github.com/lidofinance/dc4bc/storage"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const (
@@ -19,6 +22,14 @@ const (
 	operationsKey       = "operations"
 	fsmStateKey         = "fsm_state"
 	signaturesKeyPrefix = "signatures"
+	pubPolyKeyPrefix    = "pub_poly"
+	webhooksKeyPrefix   = "webhooks"
+	deadLetterKeyPrefix = "dead_letters"
+	headHashKeyPrefix   = "head_hashes"
+	checkpointKeyPrefix = "log_checkpoints"
+	stepMessagePrefix   = "step_messages"
+	equivocationPrefix  = "equivocations"
+	fsmTransitionPrefix = "fsm_transitions"
 )
 
 func makeCompositeKey(prefix, key string) []byte {
@@ -43,11 +54,51 @@ type State interface {
 	SaveSignature(signature types.ReconstructedSignature) error
 	GetSignatureByID(dkgID, signatureID string) ([]types.ReconstructedSignature, error)
 	GetSignatures(dkgID string) (map[string][]types.ReconstructedSignature, error)
+
+	SavePubPolyCommitments(dkgRoundID string, commitments [][]byte) error
+	LoadPubPolyCommitments(dkgRoundID string) ([][]byte, error)
+
+	PutWebhookDelivery(delivery types.WebhookDelivery) error
+	DeleteWebhookDelivery(deliveryID string) error
+	GetWebhookDeliveries() ([]types.WebhookDelivery, error)
+
+	PutDeadLetter(letter types.DeadLetter) error
+	DeleteDeadLetter(messageID string) error
+	GetDeadLetters() ([]types.DeadLetter, error)
+
+	// SaveHeadHash saves the head hash of the first offset messages of the append-only log, GetHeadHash
+	// returns nil if the hash is unknown
+	SaveHeadHash(offset uint64, hash []byte) error
+	GetHeadHash(offset uint64) ([]byte, error)
+
+	PutLogCheckpoint(checkpoint types.LogCheckpoint) error
+	GetLogCheckpoints() ([]types.LogCheckpoint, error)
+
+	// PutStepMessage saves the first message of a participant for a step of a DKG round, GetStepMessage
+	// returns nil if there is no message for the step yet
+	PutStepMessage(dkgRoundID, username, step string, message storage.Message) error
+	GetStepMessage(dkgRoundID, username, step string) (*storage.Message, error)
+
+	// PutEquivocation saves the evidence, GetEquivocation returns nil if there is no evidence for the step
+	PutEquivocation(equivocation types.Equivocation) error
+	GetEquivocation(dkgRoundID, username, step string) (*types.Equivocation, error)
+	GetEquivocations() ([]types.Equivocation, error)
+
+	// PutFSMTransition saves the transition of a DKG round FSM, GetFSMTransitions returns the transitions
+	// of the DKG round in the order of the log
+	PutFSMTransition(transition types.FSMTransition) error
+	GetFSMTransitions(dkgRoundID string) ([]types.FSMTransition, error)
+
+	// Atomic runs fn as a single unit of work: the changes made through tx are saved together if fn
+	// returns nil, and none of them are saved otherwise. Nested units of work are a part of the outer one
+	Atomic(fn func(tx State) error) error
 }
 
 type LevelDBState struct {
 	sync.Mutex
-	stateDb *leveldb.DB
+	// db is nil if the state is a unit of work, stateDb collects its changes then
+	db      *leveldb.DB
+	stateDb leveldbStorage
 	topic   string
 }
 
@@ -58,6 +109,7 @@ func NewLevelDBState(stateDbPath string, topic string) (State, error) {
 	}
 
 	state := &LevelDBState{
+		db:      db,
 		stateDb: db,
 		topic:   topic,
 	}
@@ -90,6 +142,11 @@ func NewLevelDBState(stateDbPath string, topic string) (State, error) {
 	return state, nil
 }
 
+// Close closes the state database
+func (s *LevelDBState) Close() error {
+	return s.db.Close()
+}
+
 func (s *LevelDBState) initJsonKey(key []byte, data interface{}) error {
 	if _, err := s.stateDb.Get(key, nil); err != nil {
 		operationsBz, err := json.Marshal(data)
@@ -359,3 +416,416 @@ func (s *LevelDBState) SaveSignature(signature types.ReconstructedSignature) err
 
 	return nil
 }
+
+// pubPolyKey returns a key of the public polynomial, the DKG rounds of different topics can have the same IDs
+func (s *LevelDBState) pubPolyKey(dkgRoundID string) []byte {
+	return makeCompositeKey(pubPolyKeyPrefix, s.topic+"_"+dkgRoundID)
+}
+
+// SavePubPolyCommitments saves the commitments of the DKG public polynomial for the given DKG round
+func (s *LevelDBState) SavePubPolyCommitments(dkgRoundID string, commitments [][]byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	commitmentsJSON, err := json.Marshal(commitments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal commitments: %w", err)
+	}
+
+	if err := s.stateDb.Put(s.pubPolyKey(dkgRoundID), commitmentsJSON, nil); err != nil {
+		return fmt.Errorf("failed to save commitments: %w", err)
+	}
+
+	return nil
+}
+
+// LoadPubPolyCommitments returns the commitments of the DKG public polynomial for the given DKG round
+func (s *LevelDBState) LoadPubPolyCommitments(dkgRoundID string) ([][]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	bz, err := s.stateDb.Get(s.pubPolyKey(dkgRoundID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commitments for dkgID %s: %w", dkgRoundID, err)
+	}
+
+	var commitments [][]byte
+	if err := json.Unmarshal(bz, &commitments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal commitments: %w", err)
+	}
+
+	return commitments, nil
+}
+
+// PutWebhookDelivery adds the delivery to the webhooks queue or updates it if it's already there
+func (s *LevelDBState) PutWebhookDelivery(delivery types.WebhookDelivery) error {
+	s.Lock()
+	defer s.Unlock()
+
+	deliveryJSON, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook delivery: %w", err)
+	}
+
+	if err := s.stateDb.Put(makeCompositeKey(webhooksKeyPrefix, delivery.ID), deliveryJSON, nil); err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWebhookDelivery removes the delivery from the webhooks queue
+func (s *LevelDBState) DeleteWebhookDelivery(deliveryID string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.stateDb.Delete(makeCompositeKey(webhooksKeyPrefix, deliveryID), nil); err != nil {
+		return fmt.Errorf("failed to delete webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries returns all queued webhook deliveries ordered by their creation time
+func (s *LevelDBState) GetWebhookDeliveries() ([]types.WebhookDelivery, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	iter := s.stateDb.NewIterator(util.BytesPrefix(makeCompositeKey(webhooksKeyPrefix, "")), nil)
+	defer iter.Release()
+
+	var deliveries []types.WebhookDelivery
+	for iter.Next() {
+		var delivery types.WebhookDelivery
+		if err := json.Unmarshal(iter.Value(), &delivery); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over webhook deliveries: %w", err)
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+
+	return deliveries, nil
+}
+
+// deadLetterKey returns a key of the failed message, the messages of different topics can have the same IDs
+func (s *LevelDBState) deadLetterKey(messageID string) []byte {
+	return makeCompositeKey(deadLetterKeyPrefix, s.topic+"_"+messageID)
+}
+
+// PutDeadLetter adds the failed message to the dead-letter list or updates it if it's already there
+func (s *LevelDBState) PutDeadLetter(letter types.DeadLetter) error {
+	s.Lock()
+	defer s.Unlock()
+
+	letterJSON, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	if err := s.stateDb.Put(s.deadLetterKey(letter.Message.ID), letterJSON, nil); err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDeadLetter removes the message from the dead-letter list
+func (s *LevelDBState) DeleteDeadLetter(messageID string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.stateDb.Delete(s.deadLetterKey(messageID), nil); err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeadLetters returns all failed messages of the topic ordered by their offsets
+func (s *LevelDBState) GetDeadLetters() ([]types.DeadLetter, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	iter := s.stateDb.NewIterator(util.BytesPrefix(s.deadLetterKey("")), nil)
+	defer iter.Release()
+
+	var letters []types.DeadLetter
+	for iter.Next() {
+		var letter types.DeadLetter
+		if err := json.Unmarshal(iter.Value(), &letter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over dead letters: %w", err)
+	}
+
+	sort.SliceStable(letters, func(i, j int) bool {
+		return letters[i].Message.Offset < letters[j].Message.Offset
+	})
+
+	return letters, nil
+}
+
+// offsetOrderedKey returns a key of the value related to the offset, keys are ordered by offsets
+func (s *LevelDBState) offsetOrderedKey(prefix string, offset uint64) []byte {
+	return makeCompositeKey(prefix, fmt.Sprintf("%s_%020d", s.topic, offset))
+}
+
+// SaveHeadHash saves the head hash of the first offset messages of the append-only log
+func (s *LevelDBState) SaveHeadHash(offset uint64, hash []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.stateDb.Put(s.offsetOrderedKey(headHashKeyPrefix, offset), hash, nil); err != nil {
+		return fmt.Errorf("failed to save head hash: %w", err)
+	}
+
+	return nil
+}
+
+// GetHeadHash returns the head hash of the first offset messages of the append-only log, the hash of the empty
+// log is storage.GenesisHeadHash. It returns nil if the messages were skipped by moving the offset
+func (s *LevelDBState) GetHeadHash(offset uint64) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if offset == 0 {
+		return storage.GenesisHeadHash, nil
+	}
+
+	hash, err := s.stateDb.Get(s.offsetOrderedKey(headHashKeyPrefix, offset), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get head hash: %w", err)
+	}
+
+	return hash, nil
+}
+
+// PutLogCheckpoint saves the head hash published by a participant
+func (s *LevelDBState) PutLogCheckpoint(checkpoint types.LogCheckpoint) error {
+	s.Lock()
+	defer s.Unlock()
+
+	checkpointJSON, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log checkpoint: %w", err)
+	}
+
+	if err := s.stateDb.Put(s.offsetOrderedKey(checkpointKeyPrefix, checkpoint.MessageOffset), checkpointJSON, nil); err != nil {
+		return fmt.Errorf("failed to save log checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// GetLogCheckpoints returns the published head hashes ordered by offsets of their messages
+func (s *LevelDBState) GetLogCheckpoints() ([]types.LogCheckpoint, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	iter := s.stateDb.NewIterator(util.BytesPrefix(makeCompositeKey(checkpointKeyPrefix, s.topic+"_")), nil)
+	defer iter.Release()
+
+	var checkpoints []types.LogCheckpoint
+	for iter.Next() {
+		var checkpoint types.LogCheckpoint
+		if err := json.Unmarshal(iter.Value(), &checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal log checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over log checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// stepKey returns a key of the value related to a step of the participant in the DKG round
+func (s *LevelDBState) stepKey(prefix, dkgRoundID, username, step string) []byte {
+	return makeCompositeKey(prefix, fmt.Sprintf("%s_%s_%s_%s", s.topic, dkgRoundID, username, step))
+}
+
+// PutStepMessage saves the first message of the participant for the step of the DKG round
+func (s *LevelDBState) PutStepMessage(dkgRoundID, username, step string, message storage.Message) error {
+	s.Lock()
+	defer s.Unlock()
+
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if err := s.stateDb.Put(s.stepKey(stepMessagePrefix, dkgRoundID, username, step), messageJSON, nil); err != nil {
+		return fmt.Errorf("failed to save step message: %w", err)
+	}
+
+	return nil
+}
+
+// GetStepMessage returns the first message of the participant for the step of the DKG round
+func (s *LevelDBState) GetStepMessage(dkgRoundID, username, step string) (*storage.Message, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	messageJSON, err := s.stateDb.Get(s.stepKey(stepMessagePrefix, dkgRoundID, username, step), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get step message: %w", err)
+	}
+
+	var message storage.Message
+	if err = json.Unmarshal(messageJSON, &message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal step message: %w", err)
+	}
+
+	return &message, nil
+}
+
+// PutEquivocation saves the evidence of the equivocation, there is one evidence for a step of a participant
+func (s *LevelDBState) PutEquivocation(equivocation types.Equivocation) error {
+	s.Lock()
+	defer s.Unlock()
+
+	equivocationJSON, err := json.Marshal(equivocation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal equivocation: %w", err)
+	}
+
+	key := s.stepKey(equivocationPrefix, equivocation.DKGRoundID, equivocation.Username, equivocation.Step)
+	if err := s.stateDb.Put(key, equivocationJSON, nil); err != nil {
+		return fmt.Errorf("failed to save equivocation: %w", err)
+	}
+
+	return nil
+}
+
+// GetEquivocation returns the evidence of the equivocation of the participant at the step
+func (s *LevelDBState) GetEquivocation(dkgRoundID, username, step string) (*types.Equivocation, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	equivocationJSON, err := s.stateDb.Get(s.stepKey(equivocationPrefix, dkgRoundID, username, step), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get equivocation: %w", err)
+	}
+
+	var equivocation types.Equivocation
+	if err = json.Unmarshal(equivocationJSON, &equivocation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal equivocation: %w", err)
+	}
+
+	return &equivocation, nil
+}
+
+// GetEquivocations returns all evidences of equivocations ordered by the time they were detected
+func (s *LevelDBState) GetEquivocations() ([]types.Equivocation, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	iter := s.stateDb.NewIterator(util.BytesPrefix(makeCompositeKey(equivocationPrefix, s.topic+"_")), nil)
+	defer iter.Release()
+
+	var equivocations []types.Equivocation
+	for iter.Next() {
+		var equivocation types.Equivocation
+		if err := json.Unmarshal(iter.Value(), &equivocation); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal equivocation: %w", err)
+		}
+		equivocations = append(equivocations, equivocation)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over equivocations: %w", err)
+	}
+
+	sort.SliceStable(equivocations, func(i, j int) bool {
+		return equivocations[i].DetectedAt.Before(equivocations[j].DetectedAt)
+	})
+
+	return equivocations, nil
+}
+
+func (s *LevelDBState) fsmTransitionKey(dkgRoundID string, offset uint64, seq int) []byte {
+	return makeCompositeKey(fsmTransitionPrefix, fmt.Sprintf("%s_%s_%020d_%04d", s.topic, dkgRoundID, offset, seq))
+}
+
+// PutFSMTransition saves the transition, a transition with the same offset and number is overwritten,
+// so the history stays the same if a message is processed again
+func (s *LevelDBState) PutFSMTransition(transition types.FSMTransition) error {
+	s.Lock()
+	defer s.Unlock()
+
+	transitionJSON, err := json.Marshal(transition)
+	if err != nil {
+		return fmt.Errorf("failed to marshal FSM transition: %w", err)
+	}
+
+	key := s.fsmTransitionKey(transition.DKGRoundID, transition.Offset, transition.Seq)
+	if err := s.stateDb.Put(key, transitionJSON, nil); err != nil {
+		return fmt.Errorf("failed to save FSM transition: %w", err)
+	}
+
+	return nil
+}
+
+// GetFSMTransitions returns the state history of the DKG round FSM
+func (s *LevelDBState) GetFSMTransitions(dkgRoundID string) ([]types.FSMTransition, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	prefix := makeCompositeKey(fsmTransitionPrefix, fmt.Sprintf("%s_%s_", s.topic, dkgRoundID))
+	iter := s.stateDb.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	var transitions []types.FSMTransition
+	for iter.Next() {
+		var transition types.FSMTransition
+		if err := json.Unmarshal(iter.Value(), &transition); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal FSM transition: %w", err)
+		}
+		transitions = append(transitions, transition)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over FSM transitions: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// Atomic collects the changes made by fn into a LevelDB batch and writes it at once. Other writes of
+// operations, signatures and commitments wait until the batch is written, so fn must use tx only
+func (s *LevelDBState) Atomic(fn func(tx State) error) error {
+	if s.db == nil {
+		return fn(s)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	batch := newStateBatch(s.db)
+	if err := fn(&LevelDBState{stateDb: batch, topic: s.topic}); err != nil {
+		return err
+	}
+
+	if err := batch.commit(); err != nil {
+		return fmt.Errorf("failed to write state batch: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/comparer"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// leveldbStorage is a part of the LevelDB API used by LevelDBState, it's implemented by the database itself
+// and by stateBatch, which collects the changes of a unit of work
+type leveldbStorage interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	Put(key, value []byte, wo *opt.WriteOptions) error
+	Delete(key []byte, wo *opt.WriteOptions) error
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+// stateBatch collects writes into a LevelDB batch, so they can be written atomically.
+// Reads see the collected writes on top of the database
+type stateBatch struct {
+	db    *leveldb.DB
+	batch *leveldb.Batch
+	// writes keeps the values written to the batch, a nil value means that the key is deleted
+	writes map[string][]byte
+}
+
+func newStateBatch(db *leveldb.DB) *stateBatch {
+	return &stateBatch{
+		db:     db,
+		batch:  new(leveldb.Batch),
+		writes: make(map[string][]byte),
+	}
+}
+
+func (b *stateBatch) Get(key []byte, ro *opt.ReadOptions) ([]byte, error) {
+	value, ok := b.writes[string(key)]
+	if !ok {
+		return b.db.Get(key, ro)
+	}
+	if value == nil {
+		return nil, leveldb.ErrNotFound
+	}
+	return append([]byte{}, value...), nil
+}
+
+func (b *stateBatch) Put(key, value []byte, _ *opt.WriteOptions) error {
+	b.batch.Put(key, value)
+	b.writes[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (b *stateBatch) Delete(key []byte, _ *opt.WriteOptions) error {
+	b.batch.Delete(key)
+	b.writes[string(key)] = nil
+	return nil
+}
+
+// NewIterator returns an iterator over a snapshot of the range with the collected writes applied
+func (b *stateBatch) NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator {
+	snapshot := memdb.New(comparer.DefaultComparer, 0)
+
+	iter := b.db.NewIterator(slice, ro)
+	for iter.Next() {
+		if err := snapshot.Put(iter.Key(), iter.Value()); err != nil {
+			iter.Release()
+			return iterator.NewEmptyIterator(err)
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return iterator.NewEmptyIterator(err)
+	}
+
+	for key, value := range b.writes {
+		if slice != nil && !inRange(slice, []byte(key)) {
+			continue
+		}
+		var err error
+		if value == nil {
+			err = snapshot.Delete([]byte(key))
+		} else {
+			err = snapshot.Put([]byte(key), value)
+		}
+		if err != nil && err != memdb.ErrNotFound {
+			return iterator.NewEmptyIterator(err)
+		}
+	}
+
+	return snapshot.NewIterator(slice)
+}
+
+func inRange(slice *util.Range, key []byte) bool {
+	if slice.Start != nil && comparer.DefaultComparer.Compare(key, slice.Start) < 0 {
+		return false
+	}
+	return slice.Limit == nil || comparer.DefaultComparer.Compare(key, slice.Limit) < 0
+}
+
+// commit writes the collected changes to the database at once
+func (b *stateBatch) commit() error {
+	// the offset is a part of the batch, so the batch must survive a crash of the machine
+	return b.db.Write(b.batch, &opt.WriteOptions{Sync: true})
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/types"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/stretchr/testify/require"
+)
+
+var errInjectedCrash = errors.New("injected crash")
+
+// crashingState fails the crashAt-th write made inside a unit of work, either with a panic, which stands for
+// a crash of the node, or with an error
+type crashingState struct {
+	State
+	crashAt int
+	panics  bool
+	writes  *int
+}
+
+func (s *crashingState) write() error {
+	*s.writes++
+	if *s.writes != s.crashAt {
+		return nil
+	}
+	if s.panics {
+		panic(errInjectedCrash)
+	}
+	return errInjectedCrash
+}
+
+func (s *crashingState) Atomic(fn func(tx State) error) error {
+	return s.State.Atomic(func(tx State) error {
+		return fn(&crashingState{State: tx, crashAt: s.crashAt, panics: s.panics, writes: s.writes})
+	})
+}
+
+func (s *crashingState) SaveOffset(offset uint64) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.State.SaveOffset(offset)
+}
+
+func (s *crashingState) SaveFSM(dkgRoundID string, dump []byte) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.State.SaveFSM(dkgRoundID, dump)
+}
+
+func (s *crashingState) PutOperation(operation *types.Operation) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.State.PutOperation(operation)
+}
+
+func (s *crashingState) DeleteOperation(operationID string) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.State.DeleteOperation(operationID)
+}
+
+func (s *crashingState) SaveSignature(signature types.ReconstructedSignature) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.State.SaveSignature(signature)
+}
+
+func (s *crashingState) SavePubPolyCommitments(dkgRoundID string, commitments [][]byte) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.State.SavePubPolyCommitments(dkgRoundID, commitments)
+}
+
+// stateSnapshot returns everything a message can change in the state
+func stateSnapshot(req *require.Assertions, state State, dkgRoundID string) string {
+	offset, err := state.LoadOffset()
+	req.NoError(err)
+	operations, err := state.GetOperations()
+	req.NoError(err)
+	fsmInstances, err := state.GetAllFSM()
+	req.NoError(err)
+	dumps := make(map[string]string, len(fsmInstances))
+	for id, fsmInstance := range fsmInstances {
+		dump, err := fsmInstance.Dump()
+		req.NoError(err)
+		dumps[id] = string(dump)
+	}
+	signatures, err := state.GetSignatures(dkgRoundID)
+	req.NoError(err)
+
+	snapshot, err := json.Marshal([]interface{}{offset, operations, dumps, signatures})
+	req.NoError(err)
+	return string(snapshot)
+}
+
+func TestBaseClient_ProcessMessageCrash(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_state_crash")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+	baseClient.SkipCommKeysVerification = true
+	state := baseClient.state
+	writes := 0
+	crashing := &crashingState{State: state, writes: &writes}
+	baseClient.state = crashing
+
+	dkgRoundID := "dkg_round_id"
+	// the pending operation of another round must survive crashes too
+	req.NoError(state.PutOperation(types.NewOperation("other_dkg_round_id", []byte("payload"),
+		spf.StateAwaitParticipantsConfirmations)))
+
+	initData, err := json.Marshal(round.initRequest)
+	req.NoError(err)
+
+	// processes the message with a crash at every write until the message is processed without crashes,
+	// the state must be left untouched by every crash
+	processWithCrashes := func(message storage.Message, panics bool) {
+		crashing.panics = panics
+		for crashAt := 1; ; crashAt++ {
+			before := stateSnapshot(req, state, dkgRoundID)
+			writes, crashing.crashAt = 0, crashAt
+
+			var processErr error
+			crashed := func() (crashed bool) {
+				defer func() {
+					if r := recover(); r != nil {
+						req.Equal(errInjectedCrash, r)
+						crashed = true
+					}
+				}()
+				processErr = baseClient.processMessageAtomically(message, func(tx State) error {
+					return tx.SaveOffset(message.Offset + 1)
+				})
+				return false
+			}()
+			if !crashed && processErr == nil {
+				req.True(crashAt > 2, "the message must be saved with several writes")
+				req.Equal(crashAt-1, writes)
+				break
+			}
+			if !crashed {
+				req.True(errors.Is(processErr, errInjectedCrash), processErr)
+			}
+			req.Equal(before, stateSnapshot(req, state, dkgRoundID), fmt.Sprintf("crash at write %d", crashAt))
+		}
+		crashing.crashAt = 0
+	}
+
+	processWithCrashes(storage.Message{
+		ID:         "init",
+		DkgRoundID: dkgRoundID,
+		Offset:     0,
+		Event:      string(spf.EventInitProposal),
+		Data:       initData,
+		SenderAddr: "alice",
+	}, true)
+
+	offset, err := state.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(1), offset)
+	operations, err := state.GetOperations()
+	req.NoError(err)
+	req.Len(operations, 2)
+	fsmInstance, ok, err := state.LoadFSM(dkgRoundID)
+	req.NoError(err)
+	req.True(ok)
+	fsmState, err := fsmInstance.State()
+	req.NoError(err)
+	req.Equal(spf.StateAwaitParticipantsConfirmations, fsmState)
+
+	bobID, err := fsmInstance.GetIDByUsername("bob")
+	req.NoError(err)
+	declineData, err := json.Marshal(requests.SignatureProposalParticipantRequest{
+		ParticipantId: bobID,
+		Reason:        "not today",
+		CreatedAt:     time.Now(),
+	})
+	req.NoError(err)
+
+	// the decline cancels the proposal, so the pending operation of the round is dropped
+	processWithCrashes(storage.Message{
+		ID:         "decline",
+		DkgRoundID: dkgRoundID,
+		Offset:     1,
+		Event:      string(spf.EventDeclineProposal),
+		Data:       declineData,
+		SenderAddr: "bob",
+	}, false)
+
+	offset, err = state.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(2), offset)
+	operations, err = state.GetOperations()
+	req.NoError(err)
+	req.Len(operations, 1)
+}
@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -119,3 +120,104 @@ func TestLevelDBState_DeleteOperation(t *testing.T) {
 	_, err = stg.GetOperationByID(operation.ID)
 	req.Error(err)
 }
+
+func TestLevelDBState_SavePubPolyCommitments(t *testing.T) {
+	var (
+		req    = require.New(t)
+		dbPath = "/tmp/dc4bc_test_SavePubPolyCommitments"
+		topic  = "test_topic"
+	)
+	defer os.RemoveAll(dbPath)
+
+	stg, err := client.NewLevelDBState(dbPath, topic)
+	req.NoError(err)
+
+	_, err = stg.LoadPubPolyCommitments("dkg_round_id")
+	req.Error(err)
+
+	commitments := [][]byte{[]byte("commitment_1"), []byte("commitment_2")}
+	err = stg.SavePubPolyCommitments("dkg_round_id", commitments)
+	req.NoError(err)
+
+	loadedCommitments, err := stg.LoadPubPolyCommitments("dkg_round_id")
+	req.NoError(err)
+	req.Equal(commitments, loadedCommitments)
+
+	// The DKG rounds of another topic can have the same IDs.
+	req.NoError(stg.(*client.LevelDBState).Close())
+	stg, err = client.NewLevelDBState(dbPath, "another_topic")
+	req.NoError(err)
+	defer stg.(*client.LevelDBState).Close()
+
+	_, err = stg.LoadPubPolyCommitments("dkg_round_id")
+	req.Error(err)
+}
+
+func TestLevelDBState_Atomic(t *testing.T) {
+	var (
+		req    = require.New(t)
+		dbPath = "/tmp/dc4bc_test_Atomic"
+		topic  = "test_topic"
+	)
+	defer os.RemoveAll(dbPath)
+
+	stg, err := client.NewLevelDBState(dbPath, topic)
+	req.NoError(err)
+
+	operation := &types.Operation{
+		ID:        "operation_id",
+		Type:      types.DKGCommits,
+		Payload:   []byte("operation_payload"),
+		CreatedAt: time.Now(),
+	}
+	req.NoError(stg.PutOperation(operation))
+
+	// the changes are dropped if the unit of work fails
+	err = stg.Atomic(func(tx client.State) error {
+		req.NoError(tx.DeleteOperation(operation.ID))
+		req.NoError(tx.SaveOffset(10))
+		_, err := tx.GetOperationByID(operation.ID)
+		req.Error(err)
+		return errors.New("failed")
+	})
+	req.Error(err)
+	_, err = stg.GetOperationByID(operation.ID)
+	req.NoError(err)
+	offset, err := stg.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(0), offset)
+
+	newOperation := &types.Operation{
+		ID:        "new_operation_id",
+		Type:      types.DKGCommits,
+		Payload:   []byte("operation_payload"),
+		CreatedAt: time.Now(),
+	}
+	req.NoError(stg.Atomic(func(tx client.State) error {
+		// the unit of work reads its own changes
+		if err := tx.DeleteOperation(operation.ID); err != nil {
+			return err
+		}
+		if err := tx.PutOperation(newOperation); err != nil {
+			return err
+		}
+		if err := tx.PutWebhookDelivery(types.WebhookDelivery{ID: "delivery_id"}); err != nil {
+			return err
+		}
+		deliveries, err := tx.GetWebhookDeliveries()
+		req.NoError(err)
+		req.Len(deliveries, 1)
+		return tx.SaveOffset(10)
+	}))
+
+	operations, err := stg.GetOperations()
+	req.NoError(err)
+	req.Len(operations, 1)
+	req.Contains(operations, newOperation.ID)
+	offset, err = stg.LoadOffset()
+	req.NoError(err)
+	req.Equal(uint64(10), offset)
+	deliveries, err := stg.GetWebhookDeliveries()
+	req.NoError(err)
+	req.Len(deliveries, 1)
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/lidofinance/dc4bc/transcript"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// fsmTransitions records the transitions of a DKG round FSM caused by a message
+type fsmTransitions struct {
+	state   State
+	message storage.Message
+	seq     int
+}
+
+func newFSMTransitions(state State, message storage.Message) *fsmTransitions {
+	return &fsmTransitions{state: state, message: message}
+}
+
+// save records the next transition caused by the message, auto is set for the events sent by the client itself
+func (t *fsmTransitions) save(event fsm.Event, auto bool, from, to fsm.State) error {
+	transition := types.FSMTransition{
+		DKGRoundID: t.message.DkgRoundID,
+		Offset:     t.message.Offset,
+		Seq:        t.seq,
+		MessageID:  t.message.ID,
+		Sender:     t.message.SenderAddr,
+		Event:      event,
+		Auto:       auto,
+		From:       from,
+		To:         to,
+	}
+	t.seq++
+	if err := t.state.PutFSMTransition(transition); err != nil {
+		return fmt.Errorf("failed to save FSM transition: %w", err)
+	}
+	return nil
+}
+
+// ExportTranscript collects the roster, the messages, the state history, the master public key and the signings
+// of the DKG round into an archive signed by the client's key, see transcript.VerifyArchive
+func (c *BaseClient) ExportTranscript(dkgRoundID string) (*transcript.Archive, error) {
+	messages, err := c.storage.GetMessages(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	ceremony := transcript.Ceremony{
+		DKGRoundID: dkgRoundID,
+		ExportedAt: time.Now().UTC(),
+		Messages:   []storage.Message{},
+		Signings:   []transcript.Signing{},
+	}
+	for _, message := range messages {
+		if message.DkgRoundID != dkgRoundID {
+			continue
+		}
+		ceremony.Messages = append(ceremony.Messages, message)
+		if fsm.Event(message.Event) == spf.EventInitProposal && ceremony.Participants == nil {
+			if ceremony.Participants, ceremony.Threshold, err = transcript.Roster(message); err != nil {
+				return nil, fmt.Errorf("failed to get roster: %w", err)
+			}
+		}
+	}
+	if ceremony.Participants == nil {
+		return nil, fmt.Errorf("DKG proposal of round %s is not found in the log", dkgRoundID)
+	}
+
+	if ceremony.StateHistory, err = c.state.GetFSMTransitions(dkgRoundID); err != nil {
+		return nil, fmt.Errorf("failed to get FSM transitions: %w", err)
+	}
+	fsmInstance, err := c.getFSMInstance(c.state, dkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FSM instance for DKG round ID %s: %w", dkgRoundID, err)
+	}
+	ceremony.State = fsmInstance.FSMDump().State
+
+	pubPoly, err := c.loadPubPoly(c.state, bls12381.NewBLS12381Suite(nil), dkgRoundID)
+	switch {
+	case errors.Is(err, leveldb.ErrNotFound):
+		// the DKG is not finished, so there is no master public key and no signings
+	case err != nil:
+		return nil, fmt.Errorf("failed to load public polynomial: %w", err)
+	default:
+		if ceremony.MasterPubKey, err = pubPoly.Commit().MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("failed to marshal master public key: %w", err)
+		}
+	}
+
+	signatures, err := c.state.GetSignatures(dkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetSignatures: %w", err)
+	}
+	for signingID, signingSignatures := range signatures {
+		signing := transcript.Signing{SigningID: signingID, ReconstructedBy: []string{}}
+		for _, signature := range signingSignatures {
+			// only valid signatures are saved without an error, and they are equal
+			if signature.VerificationError != "" {
+				continue
+			}
+			signing.Payload = signature.SrcPayload
+			if len(signature.Signature) != 0 {
+				signing.Signature = signature.Signature
+				signing.ReconstructedBy = append(signing.ReconstructedBy, signature.Username)
+			}
+		}
+		ceremony.Signings = append(ceremony.Signings, signing)
+	}
+	sort.Slice(ceremony.Signings, func(i, j int) bool {
+		return ceremony.Signings[i].SigningID < ceremony.Signings[j].SigningID
+	})
+
+	return transcript.NewArchive(ceremony, c.GetUsername(), c.signMessage)
+}
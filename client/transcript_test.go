@@ -0,0 +1,94 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+	"github.com/lidofinance/dc4bc/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_ExportTranscript(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_export_transcript")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+
+	dkgRoundID := "dkg_round_id"
+	signedMessage := func(username string, event string, request interface{}) storage.Message {
+		data, err := json.Marshal(request)
+		req.NoError(err)
+		message := storage.Message{
+			DkgRoundID: dkgRoundID,
+			Event:      event,
+			Data:       data,
+			SenderAddr: username,
+		}
+		message.Signature = ed25519.Sign(round.keyPair(username).Priv, message.Bytes())
+		return message
+	}
+
+	fsmInstance, err := state_machines.Create(dkgRoundID)
+	req.NoError(err)
+	initRequest := round.initRequest
+	_, _, err = fsmInstance.Do(spf.EventInitProposal, initRequest)
+	req.NoError(err)
+	toSend := []storage.Message{signedMessage("alice", string(spf.EventInitProposal), initRequest)}
+	for _, participant := range initRequest.Participants {
+		username := participant.Username
+		participantID, err := fsmInstance.GetIDByUsername(username)
+		req.NoError(err)
+		toSend = append(toSend, signedMessage(username, string(spf.EventConfirmSignatureProposal),
+			requests.SignatureProposalParticipantRequest{
+				ParticipantId: participantID,
+				CreatedAt:     time.Now(),
+			}))
+	}
+	messages, err := round.storage.SendBatch(toSend...)
+	req.NoError(err)
+	for _, message := range messages {
+		baseClient.handleMessage(message)
+	}
+
+	archive, err := baseClient.ExportTranscript(dkgRoundID)
+	req.NoError(err)
+
+	var ceremony transcript.Ceremony
+	req.NoError(json.Unmarshal(archive.Ceremony, &ceremony))
+	req.Len(ceremony.Messages, len(messages))
+	req.Len(ceremony.Participants, len(initRequest.Participants))
+	req.Equal(dpf.StateDkgCommitsAwaitConfirmations, ceremony.State)
+	// every message and the DKG initialization by the client after the last confirmation
+	req.Len(ceremony.StateHistory, len(messages)+1)
+	req.True(ceremony.StateHistory[len(messages)].Auto)
+	req.Empty(ceremony.MasterPubKey)
+
+	// the archive may be reformatted, the signature is over the compact JSON
+	archiveBz, err := json.MarshalIndent(archive, "", "  ")
+	req.NoError(err)
+	var indented transcript.Archive
+	req.NoError(json.Unmarshal(archiveBz, &indented))
+
+	report := transcript.VerifyArchive(&indented)
+	req.True(report.SignatureValid)
+	req.Empty(report.Errors)
+	req.Empty(report.Round.InvalidMessages)
+	// the DKG is not finished yet, so there are no commits to verify the master key against
+	req.False(report.Valid)
+
+	ceremony.State = dpf.StateDkgMasterKeyCollected
+	archive.Ceremony, err = json.Marshal(ceremony)
+	req.NoError(err)
+	report = transcript.VerifyArchive(archive)
+	req.False(report.SignatureValid)
+	req.NotEmpty(report.Errors)
+}
@@ -23,6 +23,8 @@ type OperationType string
 const (
 	DKGCommits             OperationType = "dkg_commits"
 	SignatureReconstructed fsm.Event     = "signature_reconstructed"
+	LogCheckpointPublished fsm.Event     = "log_checkpoint_published"
+	EquivocationEvidence   fsm.Event     = "equivocation_evidence"
 )
 
 type ReconstructedSignature struct {
@@ -31,6 +33,71 @@ type ReconstructedSignature struct {
 	Signature  []byte
 	Username   string
 	DKGRoundID string
+	// VerificationError is set if the signature is invalid or conflicts with other signatures of the same signing
+	VerificationError string
+}
+
+// WebhookDelivery is a webhook request waiting in the persisted queue until the receiver accepts it
+type WebhookDelivery struct {
+	ID          string
+	URL         string
+	EventType   string
+	Body        []byte
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+	NextAttempt time.Time
+}
+
+// DeadLetter is a message from the append-only log which the client failed to process
+type DeadLetter struct {
+	Message storage.Message
+	Error   string
+	// Retryable is set if the message arrived too early for the FSM, such messages are retried automatically
+	Retryable   bool
+	Attempts    int
+	CreatedAt   time.Time
+	LastAttempt time.Time
+}
+
+// LogCheckpoint is a head hash of the append-only log published by a participant of a DKG round,
+// the checkpoints of all participants are equal unless they see different logs
+type LogCheckpoint struct {
+	DKGRoundID string
+	Username   string
+	// Offset is the number of the first messages of the log HeadHash is computed over
+	Offset   uint64
+	HeadHash []byte
+	// MessageOffset is the offset of the checkpoint message in the log
+	MessageOffset uint64
+}
+
+// Equivocation is an evidence that a participant has signed two different messages for the same step
+// of a DKG round, e.g. two different commits or two different deals for the same recipient
+type Equivocation struct {
+	DKGRoundID string
+	Username   string
+	Step       string
+	First      storage.Message
+	Second     storage.Message
+	// Reporter is a participant who has broadcast the evidence, it's empty if the client has found it itself
+	Reporter   string
+	DetectedAt time.Time
+}
+
+// FSMTransition is a change of the state of a DKG round FSM caused by a message of the log. A message may cause
+// several transitions, the ones made by the client itself after the message have Auto set and the event it has sent
+type FSMTransition struct {
+	DKGRoundID string
+	Offset     uint64
+	// Seq is the number of the transition among the transitions caused by the message
+	Seq       int
+	MessageID string
+	Sender    string
+	Event     fsm.Event
+	Auto      bool
+	From      fsm.State
+	To        fsm.State
 }
 
 // Operation is the type for any Operation that might be required for
@@ -147,8 +214,32 @@ func FSMRequestFromMessage(message storage.Message) (interface{}, error) {
 			return fmt.Errorf("failed to unmarshal fsm req: %v", err), nil
 		}
 		resolvedValue = req
+	case signature_proposal_fsm.EventDeclineProposal:
+		var req requests.SignatureProposalParticipantRequest
+		if err := json.Unmarshal(message.Data, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal fsm req: %v", err), nil
+		}
+		resolvedValue = req
+	case signature_proposal_fsm.EventAbortProposal:
+		var req requests.SignatureProposalAbortRequest
+		if err := json.Unmarshal(message.Data, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal fsm req: %v", err), nil
+		}
+		resolvedValue = req
+	case signing_proposal_fsm.EventDeclineSigningConfirmation:
+		var req requests.SigningProposalParticipantRequest
+		if err := json.Unmarshal(message.Data, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal fsm req: %v", err), nil
+		}
+		resolvedValue = req
+	case signing_proposal_fsm.EventAbortSigning:
+		var req requests.SigningProposalAbortRequest
+		if err := json.Unmarshal(message.Data, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal fsm req: %v", err), nil
+		}
+		resolvedValue = req
 	case signing_proposal_fsm.EventSigningPartialSignError:
-		var req requests.SignatureProposalConfirmationErrorRequest
+		var req requests.SigningProposalConfirmationErrorRequest
 		if err := json.Unmarshal(message.Data, &req); err != nil {
 			return fmt.Errorf("failed to unmarshal fsm req: %v", err), nil
 		}
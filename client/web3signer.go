@@ -0,0 +1,407 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/eth2"
+	sipf "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	web3SignerPublicKeysPath = "/api/v1/eth2/publicKeys"
+	web3SignerSignPath       = "/api/v1/eth2/sign/"
+	web3SignerUpcheckPath    = "/upcheck"
+
+	// web3SignerProposalGrace is how long a sent proposal is remembered before the client sees it on the log,
+	// so repeated requests of a validator client don't start the same signing again
+	web3SignerProposalGrace = time.Minute
+)
+
+// Web3SignerConfig configures the remote signing API compatible with Web3Signer
+type Web3SignerConfig struct {
+	ListenAddr string
+
+	TLSCertFile  string
+	TLSKeyFile   string
+	ClientCAFile string
+
+	// Credentials and AuditLogPath are the same as of the HTTP API, signing requires ScopeOperator
+	Credentials  []APICredential
+	AuditLogPath string
+
+	// Timeout is how long a signing request waits for the threshold signature, the pending status
+	// is returned after it
+	Timeout time.Duration
+}
+
+// web3SignerRequest is the part of a Web3Signer signing request used by the client. Blocks and attestations
+// are proposed with their signing objects, so airgapped machines can apply slashing protection, other types
+// are signed by the signing root as is
+type web3SignerRequest struct {
+	Type           string                 `json:"type"`
+	SigningRoot    string                 `json:"signing_root"`
+	SigningRootOld string                 `json:"signingRoot"`
+	ForkInfo       *eth2.ForkInfo         `json:"fork_info"`
+	Attestation    *eth2.AttestationData  `json:"attestation"`
+	BeaconBlock    *web3SignerBeaconBlock `json:"beacon_block"`
+}
+
+type web3SignerBeaconBlock struct {
+	Version     string                  `json:"version"`
+	BlockHeader *eth2.BeaconBlockHeader `json:"block_header"`
+}
+
+// signingObject returns the signing object of a block or an attestation request, it's nil for other types
+func (r *web3SignerRequest) signingObject() (*eth2.SigningObject, error) {
+	switch r.Type {
+	case "BLOCK":
+		return nil, errors.New("BLOCK requests are not supported, use BLOCK_V2 with the block header")
+	case "BLOCK_V2":
+		if r.ForkInfo == nil || r.BeaconBlock == nil || r.BeaconBlock.BlockHeader == nil {
+			return nil, errors.New("BLOCK_V2 request must have the fork info and the block header")
+		}
+		return eth2.NewBlockSigningObject(*r.BeaconBlock.BlockHeader, *r.ForkInfo), nil
+	case "ATTESTATION":
+		if r.ForkInfo == nil || r.Attestation == nil {
+			return nil, errors.New("ATTESTATION request must have the fork info and the attestation")
+		}
+		return eth2.NewAttestationSigningObject(*r.Attestation, *r.ForkInfo), nil
+	}
+	return nil, nil
+}
+
+// web3SignerPending is returned instead of the signature while the threshold signing is in progress
+type web3SignerPending struct {
+	Status     string `json:"status"`
+	DKGRoundID string `json:"dkg_round_id"`
+	SigningID  string `json:"signing_id,omitempty"`
+}
+
+type web3Signer struct {
+	sync.Mutex
+	client  *BaseClient
+	timeout time.Duration
+	// proposed are the signings proposed by the signer which the client may not have seen on the log yet
+	proposed map[string]web3SignerProposal
+}
+
+type web3SignerProposal struct {
+	signingID  string
+	proposedAt time.Time
+}
+
+// StartWeb3SignerServer serves the public key listing and the signing endpoints of the Web3Signer API, signing
+// requests are turned into signing proposals of the DKG rounds with the requested master keys.
+// It blocks until the listener fails
+func (c *BaseClient) StartWeb3SignerServer(config Web3SignerConfig) error {
+	tlsConfig, err := serverTLSConfig(config.TLSCertFile, config.TLSKeyFile, config.ClientCAFile)
+	if err != nil {
+		return fmt.Errorf("failed to init TLS config: %w", err)
+	}
+
+	auth := &apiAuth{
+		credentials: config.Credentials,
+		logger:      c.Logger,
+	}
+	if config.AuditLogPath != "" {
+		if auth.audit, err = newAuditLog(config.AuditLogPath); err != nil {
+			return fmt.Errorf("failed to init audit log: %w", err)
+		}
+	}
+
+	server := &http.Server{
+		Addr:      config.ListenAddr,
+		Handler:   auth.middleware(c.newWeb3Signer(config.Timeout).handler()),
+		TLSConfig: tlsConfig,
+	}
+	if tlsConfig != nil {
+		c.Logger.Log("Web3Signer HTTPS server started on address: %s", config.ListenAddr)
+		return server.ListenAndServeTLS("", "")
+	}
+	c.Logger.Log("Web3Signer HTTP server started on address: %s", config.ListenAddr)
+	return server.ListenAndServe()
+}
+
+func (c *BaseClient) newWeb3Signer(timeout time.Duration) *web3Signer {
+	return &web3Signer{
+		client:   c,
+		timeout:  timeout,
+		proposed: make(map[string]web3SignerProposal),
+	}
+}
+
+func (s *web3Signer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(web3SignerPublicKeysPath, s.publicKeysHandler)
+	mux.HandleFunc(web3SignerSignPath, s.signHandler)
+	mux.HandleFunc(web3SignerUpcheckPath, func(w http.ResponseWriter, r *http.Request) {
+		web3SignerResponse(w, http.StatusOK, "OK")
+	})
+	return mux
+}
+
+// web3SignerResponse writes the body as plain text, Web3Signer responds with plain text errors
+func web3SignerResponse(w http.ResponseWriter, statusCode int, body string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(body))
+}
+
+func web3SignerJSONResponse(w http.ResponseWriter, statusCode int, body interface{}) {
+	bodyBz, err := json.Marshal(body)
+	if err != nil {
+		web3SignerResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to marshal response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(bodyBz)
+}
+
+// masterKeys returns the DKG rounds with finished DKG by their hex encoded master keys
+func (s *web3Signer) masterKeys() (map[string]string, error) {
+	fsmInstances, err := s.client.state.GetAllFSM()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all FSM instances: %w", err)
+	}
+
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+	keys := make(map[string]string)
+	for dkgRoundID := range fsmInstances {
+		pubPoly, err := s.client.loadPubPoly(s.client.state, blsSuite, dkgRoundID)
+		if errors.Is(err, leveldb.ErrNotFound) {
+			// the DKG is not finished yet
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load public polynomial of DKG round %s: %w", dkgRoundID, err)
+		}
+		masterKey, err := pubPoly.Commit().MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal master key: %w", err)
+		}
+		keys["0x"+hex.EncodeToString(masterKey)] = dkgRoundID
+	}
+	return keys, nil
+}
+
+func (s *web3Signer) publicKeysHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		web3SignerResponse(w, http.StatusMethodNotAllowed, "Wrong HTTP method")
+		return
+	}
+	keys, err := s.masterKeys()
+	if err != nil {
+		web3SignerResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	publicKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		publicKeys = append(publicKeys, key)
+	}
+	web3SignerJSONResponse(w, http.StatusOK, publicKeys)
+}
+
+func (s *web3Signer) signHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		web3SignerResponse(w, http.StatusMethodNotAllowed, "Wrong HTTP method")
+		return
+	}
+	keys, err := s.masterKeys()
+	if err != nil {
+		web3SignerResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	identifier := strings.ToLower(strings.TrimPrefix(r.URL.Path, web3SignerSignPath))
+	if !strings.HasPrefix(identifier, "0x") {
+		identifier = "0x" + identifier
+	}
+	dkgRoundID, ok := keys[identifier]
+	if !ok {
+		web3SignerResponse(w, http.StatusNotFound, "Public Key not found")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		web3SignerResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	var req web3SignerRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		web3SignerResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to unmarshal request: %v", err))
+		return
+	}
+	signingRootHex := req.SigningRoot
+	if signingRootHex == "" {
+		signingRootHex = req.SigningRootOld
+	}
+	signingRoot, err := hex.DecodeString(strings.TrimPrefix(signingRootHex, "0x"))
+	if err != nil || (len(signingRoot) != 32 && len(signingRoot) != 0) {
+		web3SignerResponse(w, http.StatusBadRequest, "signing root must be 32 bytes long")
+		return
+	}
+	signingObject, err := req.signingObject()
+	if err != nil {
+		web3SignerResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var signingObjectBz []byte
+	if signingObject != nil {
+		objectRoot, err := signingObject.SigningRoot()
+		if err != nil {
+			web3SignerResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(signingRoot) != 0 && !bytes.Equal(signingRoot, objectRoot[:]) {
+			web3SignerResponse(w, http.StatusBadRequest, "signing root doesn't match the signed object")
+			return
+		}
+		signingRoot = objectRoot[:]
+		if signingObjectBz, err = json.Marshal(signingObject); err != nil {
+			web3SignerResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to marshal signing object: %v", err))
+			return
+		}
+	}
+	if len(signingRoot) == 0 {
+		web3SignerResponse(w, http.StatusBadRequest, "request must have the signing root")
+		return
+	}
+
+	signature, pending, err := s.sign(r, dkgRoundID, signingRoot, signingObjectBz)
+	if err != nil {
+		web3SignerResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if signature == nil {
+		web3SignerJSONResponse(w, http.StatusAccepted, pending)
+		return
+	}
+	signatureHex := "0x" + hex.EncodeToString(signature)
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		web3SignerJSONResponse(w, http.StatusOK, map[string]string{"signature": signatureHex})
+		return
+	}
+	web3SignerResponse(w, http.StatusOK, signatureHex)
+}
+
+// sign returns the reconstructed signature of the signing root. The signing is proposed if there is
+// no signing of the root yet, and the signature is awaited until the timeout, the pending status is returned then
+func (s *web3Signer) sign(r *http.Request, dkgRoundID string, signingRoot []byte,
+	signingObject []byte) ([]byte, *web3SignerPending, error) {
+	// subscribe before the check, so the signature reconstructed in between is not missed
+	events, unsubscribe := s.client.SubscribeEvents()
+	defer unsubscribe()
+
+	signature, err := s.client.reconstructedSignature(dkgRoundID, signingRoot)
+	if err != nil || signature != nil {
+		return signature, nil, err
+	}
+
+	pending := &web3SignerPending{Status: "pending", DKGRoundID: dkgRoundID}
+	if pending.SigningID, err = s.propose(dkgRoundID, signingRoot, signingObject); err != nil {
+		return nil, nil, err
+	}
+
+	timeout := time.NewTimer(s.timeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil, pending, nil
+			}
+			if event.Type == api.EventSignatureReconstructed && event.DKGRoundID == dkgRoundID &&
+				event.Signature != nil && bytes.Equal(event.Signature.SrcPayload, signingRoot) {
+				return event.Signature.Signature, nil, nil
+			}
+		case <-timeout.C:
+			return nil, pending, nil
+		case <-r.Context().Done():
+			return nil, pending, nil
+		}
+	}
+}
+
+// propose starts a signing of the signing root unless a signing of it is in progress or has just been proposed
+func (s *web3Signer) propose(dkgRoundID string, signingRoot []byte, signingObject []byte) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	signingID, err := s.client.activeSigning(dkgRoundID, signingRoot)
+	if err != nil || signingID != "" {
+		return signingID, err
+	}
+
+	key := dkgRoundID + "_" + hex.EncodeToString(signingRoot)
+	now := time.Now()
+	for proposedKey, proposal := range s.proposed {
+		if now.Sub(proposal.proposedAt) > web3SignerProposalGrace {
+			delete(s.proposed, proposedKey)
+		}
+	}
+	if proposal, ok := s.proposed[key]; ok {
+		return proposal.signingID, nil
+	}
+
+	if signingID, err = s.client.proposeSignObject(dkgRoundID, signingRoot, signingObject); err != nil {
+		return "", fmt.Errorf("failed to propose signing: %w", err)
+	}
+	s.proposed[key] = web3SignerProposal{signingID: signingID, proposedAt: now}
+	s.client.Logger.Log("Proposed signing %s of signing root %x requested over Web3Signer API", signingID,
+		signingRoot)
+	return signingID, nil
+}
+
+// reconstructedSignature returns the accepted signature of the payload in the DKG round or nil if there is none
+func (c *BaseClient) reconstructedSignature(dkgRoundID string, payload []byte) ([]byte, error) {
+	signatures, err := c.state.GetSignatures(dkgRoundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetSignatures: %w", err)
+	}
+	for _, signingSignatures := range signatures {
+		for _, signature := range signingSignatures {
+			if signature.VerificationError == "" && len(signature.Signature) != 0 &&
+				bytes.Equal(signature.SrcPayload, payload) {
+				return signature.Signature, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// activeSigning returns the ID of the signing of the payload which is queued or in progress,
+// it's empty if there is no such signing
+func (c *BaseClient) activeSigning(dkgRoundID string, payload []byte) (string, error) {
+	fsmInstance, err := c.getFSMInstance(c.state, dkgRoundID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get FSM instance: %w", err)
+	}
+	dump := fsmInstance.FSMDump()
+	for signingID, session := range dump.Payload.SigningProposals {
+		if session.Payload == nil || !bytes.Equal(session.Payload.SrcPayload, payload) || session.Payload.IsExpired() {
+			continue
+		}
+		if session.State == sipf.StateSigningAwaitConfirmations || session.State == sipf.StateSigningAwaitPartialSigns {
+			return signingID, nil
+		}
+	}
+	for _, request := range dump.Payload.SigningProposalsQueue {
+		if bytes.Equal(request.SrcPayload, payload) {
+			return request.SigningID, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/share"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeb3Signer(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_web3signer")
+	defer round.close()
+
+	baseClient := round.newClient("alice")
+	state := baseClient.state
+
+	dkgRoundID := "dkg_round_id"
+	fsmInstance, err := state_machines.Create(dkgRoundID)
+	req.NoError(err)
+	_, _, err = fsmInstance.Do(spf.EventInitProposal, round.initRequest)
+	req.NoError(err)
+	fsmDump, err := fsmInstance.Dump()
+	req.NoError(err)
+	req.NoError(state.SaveFSM(dkgRoundID, fsmDump))
+
+	// a DKG round without the master key is not served
+	otherFSM, err := state_machines.Create("other_dkg_round_id")
+	req.NoError(err)
+	otherDump, err := otherFSM.Dump()
+	req.NoError(err)
+	req.NoError(state.SaveFSM("other_dkg_round_id", otherDump))
+
+	suite := bls12381.NewBLS12381Suite(nil)
+	priPoly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+	commitments := make([][]byte, 0, len(commits))
+	for _, commit := range commits {
+		commitBz, err := commit.MarshalBinary()
+		req.NoError(err)
+		commitments = append(commitments, commitBz)
+	}
+	req.NoError(state.SavePubPolyCommitments(dkgRoundID, commitments))
+	masterKey := "0x" + hex.EncodeToString(commitments[0])
+
+	signedRoot := bytes.Repeat([]byte{1}, 32)
+	signature := bytes.Repeat([]byte{2}, 96)
+	req.NoError(state.SaveSignature(types.ReconstructedSignature{
+		SigningID:  "signing_id",
+		SrcPayload: signedRoot,
+		Signature:  signature,
+		Username:   "bob",
+		DKGRoundID: dkgRoundID,
+	}))
+
+	server := httptest.NewServer(baseClient.newWeb3Signer(50 * time.Millisecond).handler())
+	defer server.Close()
+
+	sign := func(pubKey string, signingRoot []byte, accept string) (int, []byte) {
+		body, err := json.Marshal(map[string]string{
+			"type":         "RANDAO_REVEAL",
+			"signing_root": "0x" + hex.EncodeToString(signingRoot),
+		})
+		req.NoError(err)
+		request, err := http.NewRequest(http.MethodPost, server.URL+web3SignerSignPath+pubKey, bytes.NewReader(body))
+		req.NoError(err)
+		request.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(request)
+		req.NoError(err)
+		defer resp.Body.Close()
+		respBody, err := ioutil.ReadAll(resp.Body)
+		req.NoError(err)
+		return resp.StatusCode, respBody
+	}
+
+	resp, err := http.Get(server.URL + web3SignerPublicKeysPath)
+	req.NoError(err)
+	var publicKeys []string
+	req.NoError(json.NewDecoder(resp.Body).Decode(&publicKeys))
+	resp.Body.Close()
+	req.Equal([]string{masterKey}, publicKeys)
+
+	statusCode, _ := sign("0x"+hex.EncodeToString(make([]byte, 48)), signedRoot, "")
+	req.Equal(http.StatusNotFound, statusCode)
+
+	statusCode, body := sign(masterKey, signedRoot, "")
+	req.Equal(http.StatusOK, statusCode)
+	req.Equal("0x"+hex.EncodeToString(signature), string(body))
+
+	statusCode, body = sign(masterKey, signedRoot, "application/json")
+	req.Equal(http.StatusOK, statusCode)
+	var signatureResp map[string]string
+	req.NoError(json.Unmarshal(body, &signatureResp))
+	req.Equal("0x"+hex.EncodeToString(signature), signatureResp["signature"])
+
+	statusCode, _ = sign(masterKey, []byte{1, 2, 3}, "")
+	req.Equal(http.StatusBadRequest, statusCode)
+
+	// an unsigned root is proposed for signing once, repeated requests get the pending status
+	unsignedRoot := bytes.Repeat([]byte{3}, 32)
+	var signingIDs []string
+	for i := 0; i < 2; i++ {
+		statusCode, body = sign(masterKey, unsignedRoot, "")
+		req.Equal(http.StatusAccepted, statusCode)
+		var pending web3SignerPending
+		req.NoError(json.Unmarshal(body, &pending))
+		req.Equal("pending", pending.Status)
+		req.Equal(dkgRoundID, pending.DKGRoundID)
+		signingIDs = append(signingIDs, pending.SigningID)
+	}
+	req.NotEmpty(signingIDs[0])
+	req.Equal(signingIDs[0], signingIDs[1])
+	messages, err := round.storage.GetMessages(0)
+	req.NoError(err)
+	req.Len(messages, 1)
+	var proposal requests.SigningProposalStartRequest
+	req.NoError(json.Unmarshal(messages[0].Data, &proposal))
+	req.Equal(unsignedRoot, proposal.SrcPayload)
+	req.Equal(signingIDs[0], proposal.SigningID)
+	req.Empty(proposal.SigningObject)
+
+	// attestations are proposed with the signing object, so airgapped machines can apply slashing protection
+	attestationRequest := func(signingRoot string) (int, []byte) {
+		body := `{"type":"ATTESTATION",` + signingRoot + `
+			"fork_info":{"fork":{"previous_version":"0x00000000","current_version":"0x00000000","epoch":"0"},
+				"genesis_validators_root":"0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"},
+			"attestation":{"slot":"32","index":"0",
+				"beacon_block_root":"0x0101010101010101010101010101010101010101010101010101010101010101",
+				"source":{"epoch":"0","root":"0x0000000000000000000000000000000000000000000000000000000000000000"},
+				"target":{"epoch":"1","root":"0x0101010101010101010101010101010101010101010101010101010101010101"}}}`
+		resp, err := http.Post(server.URL+web3SignerSignPath+masterKey, "application/json", strings.NewReader(body))
+		req.NoError(err)
+		defer resp.Body.Close()
+		respBody, err := ioutil.ReadAll(resp.Body)
+		req.NoError(err)
+		return resp.StatusCode, respBody
+	}
+	statusCode, _ = attestationRequest(`"signing_root":"0x` + hex.EncodeToString(unsignedRoot) + `",`)
+	req.Equal(http.StatusBadRequest, statusCode)
+	statusCode, _ = attestationRequest("")
+	req.Equal(http.StatusAccepted, statusCode)
+
+	messages, err = round.storage.GetMessages(0)
+	req.NoError(err)
+	req.Len(messages, 2)
+	req.NoError(json.Unmarshal(messages[1].Data, &proposal))
+	signingObject, err := eth2.ParseSigningObject(proposal.SigningObject)
+	req.NoError(err)
+	req.Equal(eth2.SigningObjectAttestation, signingObject.Type)
+	objectRoot, err := signingObject.SigningRoot()
+	req.NoError(err)
+	req.Equal(objectRoot[:], proposal.SrcPayload)
+}
+
+func TestWeb3Signer_Auth(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_web3signer_auth")
+	defer round.close()
+
+	audit, err := newAuditLog(filepath.Join(round.dir, "audit.log"))
+	req.NoError(err)
+
+	baseClient := round.newClient("alice")
+	auth := &apiAuth{
+		credentials: []APICredential{
+			{Name: "monitoring", Token: "read-token", Scope: ScopeReadOnly},
+			{Name: "validator", Token: "operator-token", Scope: ScopeOperator},
+		},
+		audit:  audit,
+		logger: baseClient.Logger,
+	}
+	server := httptest.NewServer(auth.middleware(baseClient.newWeb3Signer(50 * time.Millisecond).handler()))
+	defer server.Close()
+
+	signPath := web3SignerSignPath + "0x" + hex.EncodeToString(make([]byte, 48))
+	body := `{"type":"RANDAO_REVEAL","signing_root":"0x` + hex.EncodeToString(make([]byte, 32)) + `"}`
+	testCases := []struct {
+		method     string
+		path       string
+		token      string
+		statusCode int
+	}{
+		{http.MethodPost, signPath, "", http.StatusUnauthorized},
+		{http.MethodPost, signPath, "wrong-token", http.StatusUnauthorized},
+		{http.MethodPost, signPath, "read-token", http.StatusForbidden},
+		// the master key is unknown, so an authorized request reaches the signer and isn't found
+		{http.MethodPost, signPath, "operator-token", http.StatusNotFound},
+		{http.MethodGet, web3SignerPublicKeysPath, "", http.StatusUnauthorized},
+		{http.MethodGet, web3SignerPublicKeysPath, "read-token", http.StatusOK},
+		{http.MethodGet, web3SignerUpcheckPath, "read-token", http.StatusOK},
+	}
+	for _, tc := range testCases {
+		request, err := http.NewRequest(tc.method, server.URL+tc.path, strings.NewReader(body))
+		req.NoError(err)
+		request.Header.Set("Content-Type", "application/json")
+		if tc.token != "" {
+			request.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		resp, err := http.DefaultClient.Do(request)
+		req.NoError(err)
+		resp.Body.Close()
+		req.Equal(tc.statusCode, resp.StatusCode, "%s %s with token %q", tc.method, tc.path, tc.token)
+	}
+
+	auditBz, err := ioutil.ReadFile(filepath.Join(round.dir, "audit.log"))
+	req.NoError(err)
+	auditLines := strings.Split(strings.TrimSpace(string(auditBz)), "\n")
+	req.Len(auditLines, len(testCases))
+	var record auditRecord
+	req.NoError(json.Unmarshal([]byte(auditLines[0]), &record))
+	req.Equal("anonymous", record.Credential)
+	req.Equal(signPath, record.Path)
+	req.Equal(http.StatusUnauthorized, record.StatusCode)
+}
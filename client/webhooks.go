@@ -0,0 +1,318 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	"github.com/lidofinance/dc4bc/fsm/state_machines"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+)
+
+const (
+	// WebhookSignatureHeader contains "sha256=" followed by the hex-encoded HMAC-SHA256 of the request body
+	WebhookSignatureHeader = "X-DC4BC-Signature"
+	// WebhookEventHeader contains the type of the event sent in the request body
+	WebhookEventHeader = "X-DC4BC-Event"
+	// WebhookDeliveryHeader contains the delivery ID, it's the same for all attempts to deliver a webhook
+	WebhookDeliveryHeader = "X-DC4BC-Delivery"
+
+	defaultWebhookMaxAttempts   = 10
+	defaultWebhookRetryInterval = 10 * time.Second
+	maxWebhookRetryInterval     = time.Hour
+	webhookRequestTimeout       = 10 * time.Second
+	deadlineCheckPeriod         = time.Minute
+)
+
+// webhookEventTypes are the types of the events sent to the webhooks
+var webhookEventTypes = map[api.EventType]bool{
+	api.EventNewOperation:         true,
+	api.EventFSMStateChanged:      true,
+	api.EventDeadlineApproaching:  true,
+	api.EventEquivocationDetected: true,
+}
+
+// WebhookConfig configures notifications of the node operator
+type WebhookConfig struct {
+	URLs []string
+	// Secret is a key of HMAC signatures of webhook requests
+	Secret string
+	// DeadlineWarning is how long before a deadline a notification is sent, zero disables deadline notifications
+	DeadlineWarning time.Duration
+	// MaxAttempts is a number of attempts to deliver a webhook before it's dropped
+	MaxAttempts int
+	// RetryInterval is a delay before the first retry, it's doubled after every failed attempt
+	RetryInterval time.Duration
+}
+
+// webhookSender posts events to the configured URLs. Events are put into the queue persisted in the state
+// first, so deliveries survive restarts of the node
+type webhookSender struct {
+	client     *BaseClient
+	config     WebhookConfig
+	httpClient *http.Client
+	wakeUp     chan struct{}
+	// notifiedDeadlines are keys of deadlines which are already notified about
+	notifiedDeadlines map[string]bool
+}
+
+// SignWebhook returns a value of WebhookSignatureHeader for the body, receivers should compare it
+// with the header using hmac.Equal
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// StartWebhooks starts to send new operations, FSM state changes and approaching deadlines to the webhook URLs
+// and to deliver the webhooks left in the queue, it returns immediately and stops when the client context is done.
+// It must be called before the client starts polling messages
+func (c *BaseClient) StartWebhooks(config WebhookConfig) error {
+	if len(config.URLs) == 0 {
+		return errors.New("no webhook URLs")
+	}
+	if config.Secret == "" {
+		return errors.New("webhook secret must not be empty")
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultWebhookRetryInterval
+	}
+
+	sender := &webhookSender{
+		client:            c,
+		config:            config,
+		httpClient:        &http.Client{Timeout: webhookRequestTimeout},
+		wakeUp:            make(chan struct{}, 1),
+		notifiedDeadlines: make(map[string]bool),
+	}
+
+	// deliveries of the processed messages are put into the queue in the same unit of work as the state changes
+	c.webhooks = sender
+	go sender.deliver(c.ctx)
+	if config.DeadlineWarning > 0 {
+		go sender.watchDeadlines(c.ctx)
+	}
+
+	return nil
+}
+
+// enqueue puts a delivery to every URL of each event sent to the webhooks into the queue of the given state,
+// so the deliveries are saved or discarded together with the changes which produced the events
+func (s *webhookSender) enqueue(state State, events ...api.Event) error {
+	now := time.Now()
+	for _, event := range events {
+		if !webhookEventTypes[event.Type] {
+			continue
+		}
+
+		body, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+
+		for _, url := range s.config.URLs {
+			id := sha256.Sum256(append([]byte(url), body...))
+			delivery := types.WebhookDelivery{
+				ID:          hex.EncodeToString(id[:16]),
+				URL:         url,
+				EventType:   string(event.Type),
+				Body:        body,
+				CreatedAt:   now,
+				NextAttempt: now,
+			}
+			if err := state.PutWebhookDelivery(delivery); err != nil {
+				return fmt.Errorf("failed to put webhook delivery: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// notify wakes up the sender to deliver the newly queued webhooks
+func (s *webhookSender) notify() {
+	select {
+	case s.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+func (s *webhookSender) deliver(ctx context.Context) {
+	ticker := time.NewTicker(pollingPeriod)
+	defer ticker.Stop()
+	for {
+		if err := s.deliverQueued(ctx, time.Now()); err != nil {
+			s.client.Logger.Log("Failed to deliver webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wakeUp:
+		}
+	}
+}
+
+// deliverQueued makes an attempt to deliver every queued webhook which is due
+func (s *webhookSender) deliverQueued(ctx context.Context, now time.Time) error {
+	deliveries, err := s.client.state.GetWebhookDeliveries()
+	if err != nil {
+		return fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.NextAttempt.After(now) {
+			continue
+		}
+
+		err := s.post(ctx, delivery)
+		if err == nil {
+			if err := s.client.state.DeleteWebhookDelivery(delivery.ID); err != nil {
+				return fmt.Errorf("failed to delete webhook delivery: %w", err)
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		delivery.Attempts++
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= s.config.MaxAttempts {
+			s.client.Logger.Log("Dropping webhook %s to %s after %d attempts: %v",
+				delivery.ID, delivery.URL, delivery.Attempts, err)
+			if err := s.client.state.DeleteWebhookDelivery(delivery.ID); err != nil {
+				return fmt.Errorf("failed to delete webhook delivery: %w", err)
+			}
+			continue
+		}
+
+		retryInterval := s.config.RetryInterval << (delivery.Attempts - 1)
+		if retryInterval <= 0 || retryInterval > maxWebhookRetryInterval {
+			retryInterval = maxWebhookRetryInterval
+		}
+		delivery.NextAttempt = now.Add(retryInterval)
+		s.client.Logger.Log("Failed to deliver webhook %s to %s, retrying in %s: %v",
+			delivery.ID, delivery.URL, retryInterval, err)
+		if err := s.client.state.PutWebhookDelivery(delivery); err != nil {
+			return fmt.Errorf("failed to put webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *webhookSender) post(ctx context.Context, delivery types.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(s.config.Secret, delivery.Body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *webhookSender) watchDeadlines(ctx context.Context) {
+	ticker := time.NewTicker(deadlineCheckPeriod)
+	defer ticker.Stop()
+	for {
+		if err := s.checkDeadlines(time.Now()); err != nil {
+			s.client.Logger.Log("Failed to check deadlines: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDeadlines enqueues a notification about every deadline which is closer than DeadlineWarning.
+// Notified deadlines are kept in memory, so a notification can be repeated once after a restart
+func (s *webhookSender) checkDeadlines(now time.Time) error {
+	fsmInstances, err := s.client.state.GetAllFSM()
+	if err != nil {
+		return fmt.Errorf("failed to get FSM instances: %w", err)
+	}
+
+	for dkgRoundID, fsmInstance := range fsmInstances {
+		for _, event := range fsmDeadlines(dkgRoundID, fsmInstance.FSMDump()) {
+			if event.Deadline.Before(now) || event.Deadline.Sub(now) > s.config.DeadlineWarning {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s/%s/%d", event.DKGRoundID, event.SigningID, event.State, event.Deadline.Unix())
+			if s.notifiedDeadlines[key] {
+				continue
+			}
+			event.Time = now
+			if err := s.enqueue(s.client.state, event); err != nil {
+				return fmt.Errorf("failed to enqueue deadline notification: %w", err)
+			}
+			s.notifiedDeadlines[key] = true
+			s.notify()
+		}
+	}
+
+	return nil
+}
+
+// fsmDeadlines returns deadlines of the DKG round and its signing sessions which wait for the participants
+func fsmDeadlines(dkgRoundID string, dump *state_machines.FSMDump) []api.Event {
+	if dump == nil || dump.Payload == nil {
+		return nil
+	}
+
+	var events []api.Event
+	newEvent := func(state fsm.State, signingID string, deadline time.Time) api.Event {
+		return api.Event{
+			Type:       api.EventDeadlineApproaching,
+			DKGRoundID: dkgRoundID,
+			State:      string(state),
+			SigningID:  signingID,
+			Deadline:   &deadline,
+		}
+	}
+
+	switch {
+	case dump.State == spf.StateAwaitParticipantsConfirmations && dump.Payload.SignatureProposalPayload != nil:
+		events = append(events, newEvent(dump.State, "", dump.Payload.SignatureProposalPayload.ExpiresAt))
+	case roundAwaitStates[dump.State] && dump.Payload.DKGProposalPayload != nil:
+		events = append(events, newEvent(dump.State, "", dump.Payload.DKGProposalPayload.ExpiresAt))
+	}
+
+	for signingID, session := range dump.Payload.SigningProposals {
+		if session == nil || session.Payload == nil {
+			continue
+		}
+		if signingAwaitStates[session.State] {
+			events = append(events, newEvent(session.State, signingID, session.Payload.ExpiresAt))
+		}
+	}
+
+	return events
+}
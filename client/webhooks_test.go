@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClient_StartWebhooks(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_webhooks")
+	defer round.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	baseClient := round.newClient("user_name")
+	baseClient.ctx = ctx
+	state := baseClient.state
+
+	var (
+		mu          sync.Mutex
+		events      []api.Event
+		deliveryIDs []string
+		failed      bool
+	)
+	secret := "webhook_secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		req.NoError(err)
+		req.True(hmac.Equal([]byte(SignWebhook(secret, body)), []byte(r.Header.Get(WebhookSignatureHeader))))
+
+		mu.Lock()
+		defer mu.Unlock()
+		deliveryIDs = append(deliveryIDs, r.Header.Get(WebhookDeliveryHeader))
+		// the receiver is down on the first attempt
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event api.Event
+		req.NoError(json.Unmarshal(body, &event))
+		req.Equal(string(event.Type), r.Header.Get(WebhookEventHeader))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	// the delivery left in the queue by the previous run of the node
+	queuedEvent, err := json.Marshal(api.Event{Type: api.EventFSMStateChanged, DKGRoundID: "dkg_round_id"})
+	req.NoError(err)
+	req.NoError(state.PutWebhookDelivery(types.WebhookDelivery{
+		ID:        "queued",
+		URL:       server.URL,
+		EventType: string(api.EventFSMStateChanged),
+		Body:      queuedEvent,
+		Attempts:  1,
+		CreatedAt: time.Now().Add(-time.Hour),
+	}))
+
+	req.Error(baseClient.StartWebhooks(WebhookConfig{URLs: []string{server.URL}}))
+	req.NoError(baseClient.StartWebhooks(WebhookConfig{
+		URLs:          []string{server.URL},
+		Secret:        secret,
+		RetryInterval: time.Millisecond,
+	}))
+
+	operation := types.NewOperation("dkg_round_id", []byte("payload"), "state_sig_proposal_await_participants_confirmations")
+	req.NoError(state.Atomic(func(tx State) error {
+		return baseClient.webhooks.enqueue(tx,
+			api.Event{Type: api.EventMessageRejected, DKGRoundID: "dkg_round_id"},
+			api.Event{Type: api.EventNewOperation, DKGRoundID: "dkg_round_id", Operation: operation})
+	}))
+	baseClient.webhooks.notify()
+
+	req.Eventually(func() bool {
+		deliveries, err := state.GetWebhookDeliveries()
+		req.NoError(err)
+		mu.Lock()
+		defer mu.Unlock()
+		return len(deliveries) == 0 && len(events) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// the queued delivery fails once and is retried with the same ID
+	req.Len(deliveryIDs, 3)
+	req.Equal("queued", deliveryIDs[0])
+	req.Contains(deliveryIDs[1:], "queued")
+	eventsByType := make(map[api.EventType]api.Event)
+	for _, event := range events {
+		eventsByType[event.Type] = event
+	}
+	req.Contains(eventsByType, api.EventFSMStateChanged)
+	req.Equal(operation.ID, eventsByType[api.EventNewOperation].Operation.ID)
+}
+
+func TestWebhookSender_EnqueueAtomically(t *testing.T) {
+	req := require.New(t)
+
+	round := newTestRound(t, "dc4bc_webhooks")
+	defer round.close()
+
+	state := round.newClient("user_name").state
+	sender := &webhookSender{config: WebhookConfig{URLs: []string{"http://first", "http://second"}}}
+
+	// the unit of work fails after the events are produced, e.g. the node crashes before the commit
+	err := state.Atomic(func(tx State) error {
+		if err := sender.enqueue(tx, api.Event{Type: api.EventFSMStateChanged, DKGRoundID: "dkg_round_id"}); err != nil {
+			return err
+		}
+		return errors.New("failed to save the offset")
+	})
+	req.Error(err)
+	deliveries, err := state.GetWebhookDeliveries()
+	req.NoError(err)
+	req.Empty(deliveries)
+
+	// a burst of events is not limited by the buffer of the event bus
+	events := make([]api.Event, 0, 200)
+	for i := 0; i < cap(events); i++ {
+		events = append(events, api.Event{Type: api.EventNewOperation, DKGRoundID: fmt.Sprintf("dkg_round_%d", i)})
+	}
+	events = append(events, api.Event{Type: api.EventMessageRejected, DKGRoundID: "dkg_round_id"})
+	req.NoError(state.Atomic(func(tx State) error {
+		return sender.enqueue(tx, events...)
+	}))
+	deliveries, err = state.GetWebhookDeliveries()
+	req.NoError(err)
+	req.Len(deliveries, 2*200)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/lidofinance/dc4bc/airgapped"
 	client "github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/eth2"
 	"github.com/lidofinance/dc4bc/qr"
 	"github.com/syndtr/goleveldb/leveldb"
 	"golang.org/x/crypto/ssh/terminal"
@@ -68,7 +69,11 @@ func NewPrompt(machine *airgapped.Machine) (*prompt, error) {
 
 	p.addCommand("read_operation", &promptCommand{
 		commandHandler: p.readOperationCommand,
-		description:    "reads base64-encoded Operation, handles a decoded operation and returns the path to the GIF with operation's result",
+		description:    "reads base64-encoded Operation, shows what it is about, handles it after a confirmation and returns the path to the GIF with operation's result",
+	})
+	p.addCommand("decline_operation", &promptCommand{
+		commandHandler: p.declineOperationCommand,
+		description:    "reads an Operation inviting to a DKG round or to a signing, declines it and returns the path to the GIF with the decline",
 	})
 	p.addCommand("help", &promptCommand{
 		commandHandler: p.helpCommand,
@@ -102,6 +107,18 @@ func NewPrompt(machine *airgapped.Machine) (*prompt, error) {
 		commandHandler: p.changeConfigurationCommand,
 		description:    "changes a configuration variables (frames delay, chunk size, etc...)",
 	})
+	p.addCommand("export_slashing_protection", &promptCommand{
+		commandHandler: p.exportSlashingProtectionCommand,
+		description:    "exports the slashing protection history of master keys into a file in the EIP-3076 interchange format",
+	})
+	p.addCommand("import_slashing_protection", &promptCommand{
+		commandHandler: p.importSlashingProtectionCommand,
+		description:    "imports the slashing protection history of master keys from a file in the EIP-3076 interchange format",
+	})
+	p.addCommand("show_signing_policy_decisions", &promptCommand{
+		commandHandler: p.showSigningPolicyDecisionsCommand,
+		description:    "shows the decisions of the signing policy: when and why every signing was allowed or refused",
+	})
 	p.addCommand("generate_dkg_pubkey_qr", &promptCommand{
 		commandHandler: p.generateDKGPubKeyQR,
 		description:    "generates and saves a QR with DKG public key that can be read by the Client node",
@@ -171,6 +188,22 @@ func (p *prompt) readOperationCommand() error {
 		return fmt.Errorf("failed to unmarshal Operation: %w", err)
 	}
 
+	preview, err := p.airgapped.PreviewOperation(operation)
+	if err != nil {
+		// the operation can still be checked by its hash, e.g. against the one shown by the client node
+		preview = airgapped.RawOperationPreview(operation, err)
+	}
+	p.print(preview.String())
+	p.print("> Process the operation? Type 'yes' to continue: ")
+	confirmation, err := p.reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	if strings.TrimSpace(confirmation) != "yes" {
+		p.println("Operation was not processed")
+		return nil
+	}
+
 	qrPath, err := p.airgapped.ProcessOperation(operation, true)
 	if err != nil {
 		return fmt.Errorf("failed to ProcessOperation: %w", err)
@@ -180,6 +213,39 @@ func (p *prompt) readOperationCommand() error {
 	return nil
 }
 
+func (p *prompt) declineOperationCommand() error {
+	p.print("> Enter the path to Operation JSON file: ")
+
+	operationPath, err := p.reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read operation path: %w", err)
+	}
+
+	operationBz, err := ioutil.ReadFile(strings.Trim(operationPath, " \n"))
+	if err != nil {
+		return fmt.Errorf("failed to read Operation file: %w", err)
+	}
+
+	var operation client.Operation
+	if err := json.Unmarshal(operationBz, &operation); err != nil {
+		return fmt.Errorf("failed to unmarshal Operation: %w", err)
+	}
+
+	p.print("> Enter the reason of the decline (optional): ")
+	reason, err := p.reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read reason: %w", err)
+	}
+
+	qrPath, err := p.airgapped.DeclineOperation(operation, strings.TrimSpace(reason))
+	if err != nil {
+		return fmt.Errorf("failed to DeclineOperation: %w", err)
+	}
+
+	p.printf("Operation was declined, the result Operation GIF was saved to: %s\n", qrPath)
+	return nil
+}
+
 func (p *prompt) showDKGPubKeyCommand() error {
 	pubkey := p.airgapped.GetPubKey()
 	pubkeyBz, err := pubkey.MarshalBinary()
@@ -224,6 +290,30 @@ func (p *prompt) showFinishedDKGCommand() error {
 	return nil
 }
 
+func (p *prompt) showSigningPolicyDecisionsCommand() error {
+	decisions, err := p.airgapped.GetSigningPolicyDecisions()
+	if err != nil {
+		return fmt.Errorf("failed to get signing policy decisions: %w", err)
+	}
+	if len(decisions) == 0 {
+		p.println("No signing policy decisions yet")
+		return nil
+	}
+	for _, decision := range decisions {
+		result := "allowed"
+		switch {
+		case decision.Allowed:
+		case decision.Rule != "":
+			result = fmt.Sprintf("refused by %s: %s", decision.Rule, decision.Reason)
+		default:
+			result = fmt.Sprintf("refused: %s", decision.Reason)
+		}
+		p.printf("%s %s of signing %s of DKG round %s: %s\n", decision.Time.Format(time.RFC3339),
+			decision.Step, decision.SigningID, decision.DKGRoundID, result)
+	}
+	return nil
+}
+
 func (p *prompt) replayOperationLogCommand() error {
 	p.print("> Enter the DKGRoundIdentifier: ")
 	dkgRoundIdentifier, err := p.reader.ReadString('\n')
@@ -372,6 +462,53 @@ func (p *prompt) verifySignCommand() error {
 	return nil
 }
 
+func (p *prompt) exportSlashingProtectionCommand() error {
+	p.print("> Enter the path to save the interchange file: ")
+	path, err := p.reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read path: %w", err)
+	}
+
+	interchange, err := p.airgapped.ExportSlashingProtection()
+	if err != nil {
+		return fmt.Errorf("failed to export slashing protection: %w", err)
+	}
+	interchangeBz, err := json.MarshalIndent(interchange, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal interchange: %w", err)
+	}
+	if err = ioutil.WriteFile(strings.TrimSpace(path), interchangeBz, 0600); err != nil {
+		return fmt.Errorf("failed to save interchange: %w", err)
+	}
+	p.printf("Slashing protection history of %d keys was saved to: %s\n", len(interchange.Data),
+		strings.TrimSpace(path))
+	return nil
+}
+
+func (p *prompt) importSlashingProtectionCommand() error {
+	p.print("> Enter the path to the interchange file: ")
+	path, err := p.reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read path: %w", err)
+	}
+
+	interchangeFile, err := os.Open(strings.TrimSpace(path))
+	if err != nil {
+		return fmt.Errorf("failed to open interchange: %w", err)
+	}
+	defer interchangeFile.Close()
+
+	interchange, err := eth2.ReadInterchange(interchangeFile)
+	if err != nil {
+		return err
+	}
+	if err = p.airgapped.ImportSlashingProtection(interchange); err != nil {
+		return fmt.Errorf("failed to import slashing protection: %w", err)
+	}
+	p.printf("Slashing protection history of %d keys was imported\n", len(interchange.Data))
+	return nil
+}
+
 func (p *prompt) generateDKGPubKeyQR() error {
 	dkgPubKey := p.airgapped.GetPubKey()
 	dkgPubKeyBz, err := dkgPubKey.MarshalBinary()
@@ -541,6 +678,7 @@ var (
 	framesDelay        int
 	chunkSize          int
 	qrCodesFolder      string
+	signingPolicyPath  string
 )
 
 func init() {
@@ -549,6 +687,7 @@ func init() {
 	flag.IntVar(&framesDelay, "frames_delay", 10, "Delay times between frames in 100ths of a second")
 	flag.IntVar(&chunkSize, "chunk_size", 256, "QR-code's chunk size")
 	flag.StringVar(&qrCodesFolder, "qr_codes_folder", "/tmp/", "Folder to save result QR codes")
+	flag.StringVar(&signingPolicyPath, "signing_policy", "", "Path to JSON file with the signing policy, signings are not restricted if empty")
 }
 
 func main() {
@@ -565,6 +704,13 @@ func main() {
 	}
 	air.SetQRProcessorChunkSize(chunkSize)
 	air.SetResultQRFolder(qrCodesFolder)
+	if signingPolicyPath != "" {
+		policy, err := airgapped.LoadSigningPolicy(signingPolicyPath)
+		if err != nil {
+			log.Fatalf("failed to load signing policy: %v", err)
+		}
+		air.SetSigningPolicy(policy)
+	}
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lidofinance/dc4bc/eth2"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagWithdrawalCredentials = "withdrawal_credentials"
+	flagAmount                = "amount"
+	flagForkVersion           = "fork_version"
+	flagValidatorIndex        = "validator_index"
+	flagEpoch                 = "epoch"
+	flagExecutionAddress      = "execution_address"
+	flagGenesisValidatorsRoot = "genesis_validators_root"
+)
+
+// addDepositFlags adds the flags of the deposit message, the same flags must be given to propose
+// and to build the deposit data
+func addDepositFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagWithdrawalCredentials, "", "Hex encoded withdrawal credentials of the validator")
+	cmd.Flags().Uint64(flagAmount, eth2.MaxEffectiveBalance, "Amount of the deposit in Gwei")
+	cmd.Flags().String(flagForkVersion, "00000000", "Hex encoded genesis fork version of the network")
+	_ = cmd.MarkFlagRequired(flagWithdrawalCredentials)
+}
+
+// getMasterKey returns the master public key of the DKG round confirmed by its participants
+func getMasterKey(ctx context.Context, dkgID string) ([]byte, error) {
+	dump, err := apiClient.GetFSMDump(ctx, dkgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FSM dump: %w", err)
+	}
+	if dump.Payload.DKGProposalPayload == nil {
+		return nil, fmt.Errorf("DKG round %s has not started", dkgID)
+	}
+
+	var masterKey []byte
+	for _, participant := range dump.Payload.DKGProposalPayload.Quorum {
+		if len(participant.DkgMasterKey) == 0 {
+			return nil, fmt.Errorf("participant %s has not confirmed the master key", participant.Username)
+		}
+		if masterKey != nil && !bytes.Equal(masterKey, participant.DkgMasterKey) {
+			return nil, errors.New("participants have confirmed different master keys")
+		}
+		masterKey = participant.DkgMasterKey
+	}
+	if masterKey == nil {
+		return nil, fmt.Errorf("DKG round %s has no participants", dkgID)
+	}
+	return masterKey, nil
+}
+
+// getReconstructedSignature returns the signature of the signing root reconstructed in the DKG round
+func getReconstructedSignature(ctx context.Context, dkgID string, signingRoot [32]byte) ([]byte, error) {
+	signatures, err := apiClient.GetSignatures(ctx, dkgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signatures: %w", err)
+	}
+	for _, signingSignatures := range signatures {
+		for _, reconstructed := range signingSignatures {
+			if bytes.Equal(reconstructed.SrcPayload, signingRoot[:]) && len(reconstructed.Signature) != 0 {
+				return reconstructed.Signature, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("signature of the signing root %s is not reconstructed yet",
+		hex.EncodeToString(signingRoot[:]))
+}
+
+// proposeSigningRoot proposes to sign the signing root of the object in the DKG round, the object is sent
+// with the root, so signers can check the root against it
+func proposeSigningRoot(ctx context.Context, dkgID string, signingObject *eth2.SigningObject) error {
+	signingRoot, err := signingObject.SigningRoot()
+	if err != nil {
+		return fmt.Errorf("failed to compute signing root: %w", err)
+	}
+	signingObjectBz, err := json.Marshal(signingObject)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signing object: %w", err)
+	}
+	if err = apiClient.ProposeSignObject(ctx, dkgID, signingRoot[:], signingObjectBz); err != nil {
+		return fmt.Errorf("failed to make HTTP request to propose message to sign: %w", err)
+	}
+	fmt.Printf("Proposed to sign the signing root %s\n", hex.EncodeToString(signingRoot[:]))
+	return nil
+}
+
+// saveJSON saves the object as indented JSON to the file
+func saveJSON(path string, object interface{}) error {
+	objectBz, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %T: %w", object, err)
+	}
+	if err = ioutil.WriteFile(path, objectBz, 0644); err != nil {
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	return nil
+}
+
+// depositMessageFromFlags builds the deposit message of the master key of the DKG round
+func depositMessageFromFlags(cmd *cobra.Command, dkgID string) (eth2.DepositMessage, [4]byte, error) {
+	var (
+		message     eth2.DepositMessage
+		forkVersion [4]byte
+	)
+	credentials, err := cmd.Flags().GetString(flagWithdrawalCredentials)
+	if err != nil {
+		return message, forkVersion, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	if message.WithdrawalCredentials, err = hex.DecodeString(strings.TrimPrefix(credentials, "0x")); err != nil {
+		return message, forkVersion, fmt.Errorf("failed to decode withdrawal credentials: %w", err)
+	}
+	if message.Amount, err = cmd.Flags().GetUint64(flagAmount); err != nil {
+		return message, forkVersion, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	forkVersionHex, err := cmd.Flags().GetString(flagForkVersion)
+	if err != nil {
+		return message, forkVersion, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	if forkVersion, err = eth2.ParseForkVersion(forkVersionHex); err != nil {
+		return message, forkVersion, err
+	}
+
+	if message.PubKey, err = getMasterKey(cmd.Context(), dkgID); err != nil {
+		return message, forkVersion, err
+	}
+	if err = message.Validate(); err != nil {
+		return message, forkVersion, fmt.Errorf("invalid deposit message: %w", err)
+	}
+	return message, forkVersion, nil
+}
+
+func proposeDepositDataCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose_deposit_data [dkg_id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "sends a propose message to sign the deposit of the validator with the master key of the DKG round",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			message, forkVersion, err := depositMessageFromFlags(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Validator public key: %s\n", hex.EncodeToString(message.PubKey))
+			return proposeSigningRoot(cmd.Context(), args[0], eth2.NewDepositSigningObject(message, forkVersion))
+		},
+	}
+	addDepositFlags(cmd)
+	return cmd
+}
+
+func buildDepositDataCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build_deposit_data [dkg_id] [file]",
+		Args:  cobra.ExactArgs(2),
+		Short: "saves deposit_data.json with the reconstructed signature of the deposit proposed with propose_deposit_data",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			message, forkVersion, err := depositMessageFromFlags(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			signingRoot := message.SigningRoot(forkVersion)
+
+			signature, err := getReconstructedSignature(cmd.Context(), args[0], signingRoot)
+			if err != nil {
+				return err
+			}
+
+			entry, err := eth2.NewDepositDataJSON(eth2.DepositData{DepositMessage: message, Signature: signature},
+				forkVersion)
+			if err != nil {
+				return fmt.Errorf("failed to make deposit data: %w", err)
+			}
+			if err = entry.Verify(); err != nil {
+				return fmt.Errorf("failed to verify deposit data: %w", err)
+			}
+			if err = saveJSON(args[1], []*eth2.DepositDataJSON{entry}); err != nil {
+				return err
+			}
+			fmt.Printf("Deposit data root %s is saved to %s\n", entry.DepositDataRoot, args[1])
+			return nil
+		},
+	}
+	addDepositFlags(cmd)
+	return cmd
+}
+
+// addDomainFlags adds the flags of the network the object is signed for
+func addDomainFlags(cmd *cobra.Command, forkVersionUsage string) {
+	cmd.Flags().String(flagForkVersion, "", forkVersionUsage)
+	cmd.Flags().String(flagGenesisValidatorsRoot, "", "Hex encoded genesis validators root of the network")
+	_ = cmd.MarkFlagRequired(flagForkVersion)
+	_ = cmd.MarkFlagRequired(flagGenesisValidatorsRoot)
+}
+
+// domainFromFlags returns the fork version and the genesis validators root of the network
+func domainFromFlags(cmd *cobra.Command) ([4]byte, [32]byte, error) {
+	var (
+		forkVersion           [4]byte
+		genesisValidatorsRoot [32]byte
+	)
+	forkVersionHex, err := cmd.Flags().GetString(flagForkVersion)
+	if err != nil {
+		return forkVersion, genesisValidatorsRoot, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	if forkVersion, err = eth2.ParseForkVersion(forkVersionHex); err != nil {
+		return forkVersion, genesisValidatorsRoot, err
+	}
+	genesisValidatorsRootHex, err := cmd.Flags().GetString(flagGenesisValidatorsRoot)
+	if err != nil {
+		return forkVersion, genesisValidatorsRoot, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	if genesisValidatorsRoot, err = eth2.ParseRoot(genesisValidatorsRootHex); err != nil {
+		return forkVersion, genesisValidatorsRoot, fmt.Errorf("invalid genesis validators root: %w", err)
+	}
+	return forkVersion, genesisValidatorsRoot, nil
+}
+
+// addVoluntaryExitFlags adds the flags of the voluntary exit, the same flags must be given to propose
+// and to build the exit
+func addVoluntaryExitFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64(flagValidatorIndex, 0, "Index of the validator")
+	cmd.Flags().Uint64(flagEpoch, 0, "Earliest epoch the validator can exit at")
+	_ = cmd.MarkFlagRequired(flagValidatorIndex)
+	addDomainFlags(cmd, "Hex encoded fork version of the network, the Capella fork version since Deneb")
+}
+
+// voluntaryExitFromFlags builds the voluntary exit of the validator with the master key of the DKG round
+func voluntaryExitFromFlags(cmd *cobra.Command) (eth2.VoluntaryExit, [32]byte, error) {
+	var exit eth2.VoluntaryExit
+	validatorIndex, err := cmd.Flags().GetUint64(flagValidatorIndex)
+	if err != nil {
+		return exit, [32]byte{}, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	epoch, err := cmd.Flags().GetUint64(flagEpoch)
+	if err != nil {
+		return exit, [32]byte{}, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	exit = eth2.VoluntaryExit{Epoch: epoch, ValidatorIndex: validatorIndex}
+
+	forkVersion, genesisValidatorsRoot, err := domainFromFlags(cmd)
+	if err != nil {
+		return exit, [32]byte{}, err
+	}
+	return exit, exit.SigningRoot(forkVersion, genesisValidatorsRoot), nil
+}
+
+func proposeVoluntaryExitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose_voluntary_exit [dkg_id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "sends a propose message to sign the voluntary exit of the validator with the master key of the DKG round",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exit, _, err := voluntaryExitFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			forkVersion, genesisValidatorsRoot, err := domainFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			return proposeSigningRoot(cmd.Context(), args[0],
+				eth2.NewVoluntaryExitSigningObject(exit, forkVersion, genesisValidatorsRoot))
+		},
+	}
+	addVoluntaryExitFlags(cmd)
+	return cmd
+}
+
+func buildVoluntaryExitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build_voluntary_exit [dkg_id] [file]",
+		Args:  cobra.ExactArgs(2),
+		Short: "saves the SignedVoluntaryExit proposed with propose_voluntary_exit for the beacon node API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exit, signingRoot, err := voluntaryExitFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			signature, err := getReconstructedSignature(cmd.Context(), args[0], signingRoot)
+			if err != nil {
+				return err
+			}
+			pubKey, err := getMasterKey(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			forkVersion, genesisValidatorsRoot, err := domainFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			signedExit, err := eth2.NewSignedVoluntaryExitJSON(exit, pubKey, signature, forkVersion,
+				genesisValidatorsRoot)
+			if err != nil {
+				return fmt.Errorf("failed to make signed voluntary exit: %w", err)
+			}
+			if err = saveJSON(args[1], signedExit); err != nil {
+				return err
+			}
+			fmt.Printf("Signed voluntary exit of validator %d is saved to %s\n", exit.ValidatorIndex, args[1])
+			return nil
+		},
+	}
+	addVoluntaryExitFlags(cmd)
+	return cmd
+}
+
+// addBLSToExecutionChangeFlags adds the flags of the withdrawal credentials change, the same flags must be given
+// to propose and to build the change
+func addBLSToExecutionChangeFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64(flagValidatorIndex, 0, "Index of the validator")
+	cmd.Flags().String(flagExecutionAddress, "", "Hex encoded execution address to withdraw to")
+	_ = cmd.MarkFlagRequired(flagValidatorIndex)
+	_ = cmd.MarkFlagRequired(flagExecutionAddress)
+	addDomainFlags(cmd, "Hex encoded genesis fork version of the network")
+}
+
+// blsToExecutionChangeFromFlags builds the change of the withdrawal credentials of the validator
+// from the master key of the DKG round to the execution address
+func blsToExecutionChangeFromFlags(cmd *cobra.Command, dkgID string) (eth2.BLSToExecutionChange, [32]byte, error) {
+	var change eth2.BLSToExecutionChange
+	validatorIndex, err := cmd.Flags().GetUint64(flagValidatorIndex)
+	if err != nil {
+		return change, [32]byte{}, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	address, err := cmd.Flags().GetString(flagExecutionAddress)
+	if err != nil {
+		return change, [32]byte{}, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	change.ValidatorIndex = validatorIndex
+	if change.ToExecutionAddress, err = hex.DecodeString(strings.TrimPrefix(address, "0x")); err != nil {
+		return change, [32]byte{}, fmt.Errorf("failed to decode execution address: %w", err)
+	}
+	if change.FromBLSPubKey, err = getMasterKey(cmd.Context(), dkgID); err != nil {
+		return change, [32]byte{}, err
+	}
+	if err = change.Validate(); err != nil {
+		return change, [32]byte{}, fmt.Errorf("invalid BLS to execution change: %w", err)
+	}
+
+	genesisForkVersion, genesisValidatorsRoot, err := domainFromFlags(cmd)
+	if err != nil {
+		return change, [32]byte{}, err
+	}
+	return change, change.SigningRoot(genesisForkVersion, genesisValidatorsRoot), nil
+}
+
+func proposeBLSToExecutionChangeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose_bls_to_execution_change [dkg_id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "sends a propose message to sign the change of the withdrawal credentials of the validator from the master key of the DKG round to an execution address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, _, err := blsToExecutionChangeFromFlags(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			genesisForkVersion, genesisValidatorsRoot, err := domainFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			return proposeSigningRoot(cmd.Context(), args[0],
+				eth2.NewBLSToExecutionChangeSigningObject(change, genesisForkVersion, genesisValidatorsRoot))
+		},
+	}
+	addBLSToExecutionChangeFlags(cmd)
+	return cmd
+}
+
+func buildBLSToExecutionChangeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build_bls_to_execution_change [dkg_id] [file]",
+		Args:  cobra.ExactArgs(2),
+		Short: "saves the SignedBLSToExecutionChange proposed with propose_bls_to_execution_change for the beacon node API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, signingRoot, err := blsToExecutionChangeFromFlags(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			signature, err := getReconstructedSignature(cmd.Context(), args[0], signingRoot)
+			if err != nil {
+				return err
+			}
+			genesisForkVersion, genesisValidatorsRoot, err := domainFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			signedChange, err := eth2.NewSignedBLSToExecutionChangeJSON(change, signature, genesisForkVersion,
+				genesisValidatorsRoot)
+			if err != nil {
+				return fmt.Errorf("failed to make signed BLS to execution change: %w", err)
+			}
+			// the beacon node API accepts a list of changes
+			if err = saveJSON(args[1], []*eth2.SignedBLSToExecutionChangeJSON{signedChange}); err != nil {
+				return err
+			}
+			fmt.Printf("Signed BLS to execution change of validator %d is saved to %s\n", change.ValidatorIndex,
+				args[1])
+			return nil
+		},
+	}
+	addBLSToExecutionChangeFlags(cmd)
+	return cmd
+}
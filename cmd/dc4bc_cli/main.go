@@ -2,19 +2,26 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/lidofinance/dc4bc/fsm/state_machines"
@@ -23,9 +30,11 @@ import (
 	"github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
 	"github.com/lidofinance/dc4bc/fsm/types/responses"
 
-	"github.com/lidofinance/dc4bc/client"
+	"github.com/lidofinance/dc4bc/client/api"
+	"github.com/lidofinance/dc4bc/client/types"
 	"github.com/lidofinance/dc4bc/fsm/types/requests"
 	"github.com/lidofinance/dc4bc/qr"
+	"github.com/lidofinance/dc4bc/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -34,18 +43,81 @@ const (
 	flagFramesDelay   = "frames_delay"
 	flagChunkSize     = "chunk_size"
 	flagQRCodesFolder = "qr_codes_folder"
+	flagAPIToken      = "api_token"
+	flagTLSCA         = "tls_ca"
+	flagTLSCert       = "tls_cert"
+	flagTLSKey        = "tls_key"
 )
 
+const apiRequestTimeout = 30 * time.Second
+
 func init() {
 	rootCmd.PersistentFlags().String(flagListenAddr, "localhost:8080", "Listen Address")
 	rootCmd.PersistentFlags().Int(flagFramesDelay, 10, "Delay times between frames in 100ths of a second")
 	rootCmd.PersistentFlags().Int(flagChunkSize, 256, "QR-code's chunk size")
 	rootCmd.PersistentFlags().String(flagQRCodesFolder, "/tmp", "Folder to save QR codes")
+	rootCmd.PersistentFlags().String(flagAPIToken, "", "Bearer token to access the node HTTP API")
+	rootCmd.PersistentFlags().String(flagTLSCA, "", "Path to CA certificate of the node HTTP API, enables HTTPS")
+	rootCmd.PersistentFlags().String(flagTLSCert, "", "Path to client TLS certificate for mutual TLS")
+	rootCmd.PersistentFlags().String(flagTLSKey, "", "Path to client TLS key for mutual TLS")
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "dc4bc_cli",
-	Short: "dc4bc client cli utilities implementation",
+	Use:               "dc4bc_cli",
+	Short:             "dc4bc client cli utilities implementation",
+	PersistentPreRunE: initAPIClient,
+}
+
+// apiClient is a client of the node HTTP API, it's created before every command from the connection flags
+var apiClient *api.Client
+
+// initAPIClient configures the client of the node API according to the address, TLS and token flags
+func initAPIClient(cmd *cobra.Command, args []string) error {
+	listenAddr, err := cmd.Flags().GetString(flagListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration: %v", err)
+	}
+	token, err := cmd.Flags().GetString(flagAPIToken)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration: %v", err)
+	}
+
+	caPath, _ := cmd.Flags().GetString(flagTLSCA)
+	certPath, _ := cmd.Flags().GetString(flagTLSCert)
+	keyPath, _ := cmd.Flags().GetString(flagTLSKey)
+	if caPath == "" {
+		if certPath != "" || keyPath != "" {
+			return fmt.Errorf("client TLS certificate requires --%s", flagTLSCA)
+		}
+		apiClient = api.NewClient("http://" + listenAddr)
+		apiClient.SetToken(token)
+		return nil
+	}
+
+	caCert, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		return fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return fmt.Errorf("failed to parse CA certificate")
+	}
+	tlsConfig := &tls.Config{RootCAs: pool}
+	if certPath != "" || keyPath != "" {
+		certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load client TLS key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	apiClient = api.NewClient("https://" + listenAddr)
+	apiClient.SetHTTPClient(&http.Client{
+		Timeout:   apiRequestTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	})
+	apiClient.SetToken(token)
+	return nil
 }
 
 func main() {
@@ -55,57 +127,52 @@ func main() {
 		readOperationResultCommand(),
 		startDKGCommand(),
 		proposeSignMessageCommand(),
+		declineDKGCommand(),
+		abortDKGCommand(),
+		declineSigningCommand(),
+		abortSigningCommand(),
 		getUsernameCommand(),
 		getPubKeyCommand(),
 		getHashOfStartDKGCommand(),
 		getSignaturesCommand(),
 		getSignatureCommand(),
+		getRejectedSignaturesCommand(),
 		saveOffsetCommand(),
 		getOffsetCommand(),
 		getFSMStatusCommand(),
+		getRoundStatusCommand(),
+		getDeadLettersCommand(),
+		retryDeadLetterCommand(),
+		discardDeadLetterCommand(),
+		getLogCheckpointsCommand(),
+		publishLogCheckpointCommand(),
+		getEquivocationsCommand(),
+		exportTranscriptCommand(),
+		proposeDepositDataCommand(),
+		buildDepositDataCommand(),
+		proposeVoluntaryExitCommand(),
+		buildVoluntaryExitCommand(),
+		proposeBLSToExecutionChangeCommand(),
+		buildBLSToExecutionChangeCommand(),
 		getFSMListCommand(),
 		getSignatureDataCommand(),
+		watchCommand(),
 	)
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Failed to execute root command: %v", err)
 	}
 }
 
-func getOperationsRequest(host string) (*OperationsResponse, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/getOperations", host))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get operations: %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body: %w", err)
-	}
-
-	var response OperationsResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-	return &response, nil
-}
-
 func getOperationsCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get_operations",
 		Short: "returns all operations that should be processed on the airgapped machine",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-			operations, err := getOperationsRequest(listenAddr)
+			operations, err := apiClient.GetOperations(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("failed to get operations: %w", err)
 			}
-			if operations.ErrorMessage != "" {
-				return fmt.Errorf("failed to get operations: %s", operations.ErrorMessage)
-			}
-			for _, operation := range operations.Result {
+			for _, operation := range operations {
 				fmt.Printf("DKG round ID: %s\n", operation.DKGIdentifier)
 				fmt.Printf("Operation ID: %s\n", operation.ID)
 				fmt.Printf("Description: %s\n", getShortOperationDescription(operation.Type))
@@ -132,42 +199,17 @@ func getOperationsCommand() *cobra.Command {
 	}
 }
 
-func getSignaturesRequest(host string, dkgID string) (*SignaturesResponse, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/getSignatures?dkgID=%s", host, dkgID))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get signatures: %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body: %w", err)
-	}
-
-	var response SignaturesResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-	return &response, nil
-}
-
 func getSignaturesCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get_signatures [dkgID]",
 		Args:  cobra.ExactArgs(1),
-		Short: "returns all signatures for the given DKG round that were reconstructed on the airgapped machine",
+		Short: "returns all signatures for the given DKG round that were reconstructed by participants",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-			signatures, err := getSignaturesRequest(listenAddr, args[0])
+			signatures, err := apiClient.GetSignatures(cmd.Context(), args[0])
 			if err != nil {
 				return fmt.Errorf("failed to get signatures: %w", err)
 			}
-			if signatures.ErrorMessage != "" {
-				return fmt.Errorf("failed to get signatures: %s", signatures.ErrorMessage)
-			}
-			for sigID, signature := range signatures.Result {
+			for sigID, signature := range signatures {
 				fmt.Printf("Signing ID: %s\n", sigID)
 				for _, participantSig := range signature {
 					fmt.Printf("\tDKG round ID: %s\n", participantSig.DKGRoundID)
@@ -181,22 +223,29 @@ func getSignaturesCommand() *cobra.Command {
 	}
 }
 
-func getSignatureRequest(host string, dkgID, dataHash string) (*SignatureResponse, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/getSignatureByID?dkgID=%s&id=%s", host, dkgID, dataHash))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get signatures: %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body: %w", err)
-	}
-
-	var response SignatureResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+func getRejectedSignaturesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get_rejected_signatures [dkgID]",
+		Args:  cobra.ExactArgs(1),
+		Short: "returns broadcasted signatures for the given DKG round that are invalid or conflicting",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			signatures, err := apiClient.GetRejectedSignatures(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get rejected signatures: %w", err)
+			}
+			for sigID, signature := range signatures {
+				fmt.Printf("Signing ID: %s\n", sigID)
+				for _, participantSig := range signature {
+					fmt.Printf("\tDKG round ID: %s\n", participantSig.DKGRoundID)
+					fmt.Printf("\tParticipant: %s\n", participantSig.Username)
+					fmt.Printf("\tSignature: %s\n", base64.StdEncoding.EncodeToString(participantSig.Signature))
+					fmt.Printf("\tReason: %s\n", participantSig.VerificationError)
+					fmt.Println()
+				}
+			}
+			return nil
+		},
 	}
-	return &response, nil
 }
 
 func getSignatureCommand() *cobra.Command {
@@ -205,18 +254,11 @@ func getSignatureCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 		Short: "returns a list of reconstructed signatures of the signed data broadcasted by users",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-			signatures, err := getSignatureRequest(listenAddr, args[0], args[1])
+			signatures, err := apiClient.GetSignatureByID(cmd.Context(), args[0], args[1])
 			if err != nil {
 				return fmt.Errorf("failed to get signatures: %w", err)
 			}
-			if signatures.ErrorMessage != "" {
-				return fmt.Errorf("failed to get signatures: %s", signatures.ErrorMessage)
-			}
-			for _, participantSig := range signatures.Result {
+			for _, participantSig := range signatures {
 				fmt.Printf("\tParticipant: %s\n", participantSig.Username)
 				fmt.Printf("\tReconstructed signature for the data: %s\n", base64.StdEncoding.EncodeToString(participantSig.Signature))
 				fmt.Println()
@@ -232,53 +274,24 @@ func getSignatureDataCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 		Short: "returns a data which was signed",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-			signatures, err := getSignatureRequest(listenAddr, args[0], args[1])
+			signatures, err := apiClient.GetSignatureByID(cmd.Context(), args[0], args[1])
 			if err != nil {
 				return fmt.Errorf("failed to get signatures: %w", err)
 			}
-			if signatures.ErrorMessage != "" {
-				return fmt.Errorf("failed to get signatures: %s", signatures.ErrorMessage)
-			}
-			if len(signatures.Result) > 0 {
-				fmt.Println(string(signatures.Result[0].SrcPayload))
+			if len(signatures) > 0 {
+				fmt.Println(string(signatures[0].SrcPayload))
 			}
 			return nil
 		},
 	}
 }
 
-func getOperationRequest(host string, operationID string) (*OperationResponse, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/getOperation?operationID=%s", host, operationID))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get operation: %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body: %w", err)
-	}
-
-	var response OperationResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-	return &response, nil
-}
-
 func getOperationQRPathCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get_operation_qr [operationID]",
 		Args:  cobra.ExactArgs(1),
 		Short: "returns path to QR codes which contains the operation",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
 			framesDelay, err := cmd.Flags().GetInt(flagFramesDelay)
 			if err != nil {
 				return fmt.Errorf("failed to read configuration: %w", err)
@@ -293,13 +306,10 @@ func getOperationQRPathCommand() *cobra.Command {
 			}
 
 			operationID := args[0]
-			operation, err := getOperationRequest(listenAddr, operationID)
+			operationJSON, err := apiClient.GetOperationJSON(cmd.Context(), operationID)
 			if err != nil {
 				return fmt.Errorf("failed to get operations: %w", err)
 			}
-			if operation.ErrorMessage != "" {
-				return fmt.Errorf("failed to get operations: %s", operation.ErrorMessage)
-			}
 
 			operationQRPath := filepath.Join(qrCodeFolder, fmt.Sprintf("dc4bc_qr_%s-request", operationID))
 
@@ -309,7 +319,7 @@ func getOperationQRPathCommand() *cobra.Command {
 			processor.SetChunkSize(chunkSize)
 			processor.SetDelay(framesDelay)
 
-			if err = processor.WriteQR(qrPath, operation.Result); err != nil {
+			if err = processor.WriteQR(qrPath, operationJSON); err != nil {
 				return fmt.Errorf("failed to save QR gif: %w", err)
 			}
 
@@ -319,42 +329,16 @@ func getOperationQRPathCommand() *cobra.Command {
 	}
 }
 
-func rawGetRequest(url string) (*client.Response, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get operations for node %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body %w", err)
-	}
-
-	var response client.Response
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-	return &response, nil
-}
-
 func getPubKeyCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get_pubkey",
 		Short: "returns client's pubkey",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
-			resp, err := rawGetRequest(fmt.Sprintf("http://%s//getPubKey", listenAddr))
+			pubKey, err := apiClient.GetPubKey(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("failed to get client's pubkey: %w", err)
 			}
-			if resp.ErrorMessage != "" {
-				return fmt.Errorf("failed to get client's pubkey: %v", resp.ErrorMessage)
-			}
-			fmt.Println(resp.Result.(string))
+			fmt.Println(base64.StdEncoding.EncodeToString(pubKey))
 			return nil
 		},
 	}
@@ -366,28 +350,14 @@ func saveOffsetCommand() *cobra.Command {
 		Short: "saves a new offset for a storage",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
 			offset, err := strconv.ParseUint(args[0], 10, 64)
 			if err != nil {
 				return fmt.Errorf("failed to parse uint: %w", err)
 			}
-			req := map[string]uint64{"offset": offset}
-			data, err := json.Marshal(req)
-			if err != nil {
-				return fmt.Errorf("failed to create request: %w", err)
-			}
-			resp, err := rawPostRequest(fmt.Sprintf("http://%s/saveOffset", listenAddr), "application/json", data)
-			if err != nil {
+			if err = apiClient.SaveOffset(cmd.Context(), offset); err != nil {
 				return fmt.Errorf("failed to save offset: %w", err)
 			}
-			if resp.ErrorMessage != "" {
-				return fmt.Errorf("failed to save offset: %v", resp.ErrorMessage)
-			}
-			fmt.Println(resp.Result.(string))
+			fmt.Println("ok")
 			return nil
 		},
 	}
@@ -398,46 +368,22 @@ func getOffsetCommand() *cobra.Command {
 		Use:   "get_offset",
 		Short: "returns a current offset for the storage",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
-			resp, err := rawGetRequest(fmt.Sprintf("http://%s//getOffset", listenAddr))
+			offset, err := apiClient.GetOffset(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("failed to get offset: %w", err)
 			}
-			if resp.ErrorMessage != "" {
-				return fmt.Errorf("failed to get offset: %v", resp.ErrorMessage)
-			}
-			fmt.Println(uint64(resp.Result.(float64)))
+			fmt.Println(offset)
 			return nil
 		},
 	}
 }
 
-func getUsername(listenAddr string) (string, error) {
-	resp, err := rawGetRequest(fmt.Sprintf("http://%s//getUsername", listenAddr))
-	if err != nil {
-		return "", fmt.Errorf("failed to do HTTP request: %w", err)
-	}
-	if resp.ErrorMessage != "" {
-		return "", fmt.Errorf("failed to get client's username: %v", resp.ErrorMessage)
-	}
-	return resp.Result.(string), nil
-}
-
 func getUsernameCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get_username",
 		Short: "returns client's username",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
-			username, err := getUsername(listenAddr)
+			username, err := apiClient.GetUsername(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("failed to get client's username: %w", err)
 			}
@@ -447,49 +393,24 @@ func getUsernameCommand() *cobra.Command {
 	}
 }
 
-func rawPostRequest(url string, contentType string, data []byte) (*client.Response, error) {
-	resp, err := http.Post(url,
-		contentType, bytes.NewReader(data))
-	if err != nil {
-		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body %w", err)
-	}
-
-	var response client.Response
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
-	}
-	return &response, nil
-}
-
 func readOperationResultCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "read_operation_result",
 		Short: "given the path to Operation JSON file, decodes and processes it",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
 			operationBz, err := ioutil.ReadFile(strings.Trim(args[0], " \n"))
 			if err != nil {
 				return fmt.Errorf("failed to read Operation file: %w", err)
 			}
 
-			resp, err := rawPostRequest(fmt.Sprintf("http://%s/handleProcessedOperationJSON", listenAddr),
-				"application/json", operationBz)
-			if err != nil {
-				return fmt.Errorf("failed to handle processed operation: %w", err)
+			var operation types.Operation
+			if err = json.Unmarshal(operationBz, &operation); err != nil {
+				return fmt.Errorf("failed to unmarshal Operation: %w", err)
 			}
 
-			if resp.ErrorMessage != "" {
-				return fmt.Errorf("failed to handle processed operation: %v", resp.ErrorMessage)
+			if err = apiClient.HandleProcessedOperation(cmd.Context(), operation); err != nil {
+				return fmt.Errorf("failed to handle processed operation: %w", err)
 			}
 
 			return nil
@@ -503,11 +424,6 @@ func startDKGCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		Short: "sends a propose message to start a DKG process",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
 			dkgProposeFileData, err := ioutil.ReadFile(args[0])
 			if err != nil {
 				return fmt.Errorf("failed to read file: %w", err)
@@ -527,14 +443,9 @@ func startDKGCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to marshal SignatureProposalParticipantsListRequest: %v", err)
 			}
-			resp, err := rawPostRequest(fmt.Sprintf("http://%s/startDKG", listenAddr),
-				"application/json", messageDataBz)
-			if err != nil {
+			if _, err = apiClient.StartDKG(cmd.Context(), messageDataBz); err != nil {
 				return fmt.Errorf("failed to make HTTP request to start DKG: %w", err)
 			}
-			if resp.ErrorMessage != "" {
-				return fmt.Errorf("failed to make HTTP request to start DKG: %v", resp.ErrorMessage)
-			}
 			return nil
 		},
 	}
@@ -587,86 +498,102 @@ func proposeSignMessageCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 		Short: "sends a propose message to sign the data in the file",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
-			dkgID, err := hex.DecodeString(args[0])
-			if err != nil {
-				return fmt.Errorf("failed to decode dkgID: %w", err)
-			}
-
 			data, err := ioutil.ReadFile(args[1])
 			if err != nil {
 				return fmt.Errorf("failed to read the file")
 			}
 
-			messageDataBz, err := json.Marshal(map[string][]byte{"data": data,
-				"dkgID": dkgID})
-			if err != nil {
-				return fmt.Errorf("failed to marshal SigningProposalStartRequest: %v", err)
+			if err = apiClient.ProposeSign(cmd.Context(), args[0], data); err != nil {
+				return fmt.Errorf("failed to make HTTP request to propose message to sign: %w", err)
 			}
+			return nil
+		},
+	}
+}
 
-			resp, err := rawPostRequest(fmt.Sprintf("http://%s/proposeSignMessage", listenAddr),
-				"application/json", messageDataBz)
-			if err != nil {
-				return fmt.Errorf("failed to make HTTP request to propose message to sign: %w", err)
+func declineDKGCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "decline_dkg [dkg_id] [reason]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "declines a participation in the DKG proposal, optionally with a reason",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiClient.DeclineDKG(cmd.Context(), args[0], optionalArg(args, 1)); err != nil {
+				return fmt.Errorf("failed to decline DKG: %w", err)
 			}
-			if resp.ErrorMessage != "" {
-				return fmt.Errorf("failed to make HTTP request to propose message to sign: %v", resp.ErrorMessage)
+			return nil
+		},
+	}
+}
+
+func abortDKGCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "abort_dkg [dkg_id] [reason]",
+		Args:  cobra.ExactArgs(2),
+		Short: "aborts the DKG proposal which awaits confirmations of participants, only the initiator can abort it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiClient.AbortDKG(cmd.Context(), args[0], args[1]); err != nil {
+				return fmt.Errorf("failed to abort DKG: %w", err)
 			}
 			return nil
 		},
 	}
 }
 
-func getFSMDumpRequest(host string, dkgID string) (*FSMDumpResponse, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/getFSMDump?dkgID=%s", host, dkgID))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get FSM dump: %w", err)
+func declineSigningCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "decline_signing [dkg_id] [signing_id] [reason]",
+		Args:  cobra.RangeArgs(2, 3),
+		Short: "declines a participation in the signing proposal, optionally with a reason",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiClient.DeclineSigning(cmd.Context(), args[0], args[1], optionalArg(args, 2)); err != nil {
+				return fmt.Errorf("failed to decline signing: %w", err)
+			}
+			return nil
+		},
 	}
-	defer resp.Body.Close()
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body: %w", err)
+}
+
+func abortSigningCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "abort_signing [dkg_id] [signing_id] [reason]",
+		Args:  cobra.ExactArgs(3),
+		Short: "aborts the signing proposal, only the initiator of the signing is allowed to do it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiClient.AbortSigning(cmd.Context(), args[0], args[1], args[2]); err != nil {
+				return fmt.Errorf("failed to abort signing: %w", err)
+			}
+			return nil
+		},
 	}
+}
 
-	var response FSMDumpResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+func optionalArg(args []string, idx int) string {
+	if len(args) > idx {
+		return args[idx]
 	}
-	return &response, nil
+	return ""
 }
 
+// sendParticipantDecision sends a decline or an abort of a proposal to the node
 func getFSMStatusCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "show_fsm_status [dkg_id]",
 		Args:  cobra.ExactArgs(1),
 		Short: "shows the current status of FSM",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
-			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
-			}
-
-			fsmDumpResponse, err := getFSMDumpRequest(listenAddr, args[0])
+			dump, err := apiClient.GetFSMDump(cmd.Context(), args[0])
 			if err != nil {
 				return fmt.Errorf("failed to get FSM dump: %w", err)
 			}
-			if fsmDumpResponse.ErrorMessage != "" {
-				return fmt.Errorf("failed to get FSM dump: %v", fsmDumpResponse.ErrorMessage)
-			}
-			dump := fsmDumpResponse.Result
 
 			fmt.Printf("FSM current status is %s\n", dump.State)
 
-			quorum := make(map[int]state_machines.Participant)
-			if strings.HasPrefix(string(dump.State), "state_signing") {
-				for k, v := range dump.Payload.SigningProposalPayload.Quorum {
-					quorum[k] = v
-				}
+			username, err := apiClient.GetUsername(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get client's username: %w", err)
 			}
+
+			quorum := make(map[int]state_machines.Participant)
 			if strings.HasPrefix(string(dump.State), "state_dkg") {
 				for k, v := range dump.Payload.DKGProposalPayload.Quorum {
 					quorum[k] = v
@@ -677,69 +604,379 @@ func getFSMStatusCommand() *cobra.Command {
 					quorum[k] = v
 				}
 			}
+			printQuorumStatus(quorum, username, "")
+			if dump.Payload.SignatureProposalPayload != nil && dump.Payload.SignatureProposalPayload.AbortedBy != "" {
+				fmt.Printf("DKG proposal aborted by %s: %s\n", dump.Payload.SignatureProposalPayload.AbortedBy,
+					dump.Payload.SignatureProposalPayload.AbortReason)
+			}
+
+			signingIDs := make([]string, 0, len(dump.Payload.SigningProposals))
+			for signingID := range dump.Payload.SigningProposals {
+				signingIDs = append(signingIDs, signingID)
+			}
+			sort.Strings(signingIDs)
+			for _, signingID := range signingIDs {
+				session := dump.Payload.SigningProposals[signingID]
+				fmt.Printf("Signing ID: %s - status: %s\n", signingID, session.State)
+				if session.Payload == nil {
+					continue
+				}
+				quorum := make(map[int]state_machines.Participant)
+				for k, v := range session.Payload.Quorum {
+					quorum[k] = v
+				}
+				printQuorumStatus(quorum, username, "\t")
+				if session.Payload.AbortedBy != "" {
+					fmt.Printf("\tAborted by %s: %s\n", session.Payload.AbortedBy, session.Payload.AbortReason)
+				}
+			}
 
-			waiting := make([]string, 0)
-			confirmed := make([]string, 0)
-			failed := make([]string, 0)
+			if len(dump.Payload.SigningProposalsQueue) > 0 {
+				fmt.Println("Queued signings:")
+				for idx, proposal := range dump.Payload.SigningProposalsQueue {
+					fmt.Printf("\t%d. Signing ID: %s\n", idx+1, proposal.SigningID)
+				}
+			}
+
+			return nil
+		},
+	}
+}
 
-			username, err := getUsername(listenAddr)
+func getRoundStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "round_status [dkg_id]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "shows the progress of every participant in the DKG round and its signings, or in all rounds",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var dkgID string
+			if len(args) > 0 {
+				dkgID = args[0]
+			}
+			statuses, err := apiClient.GetRoundStatus(cmd.Context(), dkgID)
+			if err != nil {
+				return fmt.Errorf("failed to get round status: %w", err)
+			}
+			username, err := apiClient.GetUsername(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("failed to get client's username: %w", err)
 			}
 
-			for _, p := range quorum {
-				if strings.Contains(p.GetStatus().String(), "Await") {
-					// deals are private messages, so we don't need to wait messages from ourself
-					if p.GetStatus().String() == "DealAwaitConfirmation" && p.GetUsername() == username {
-						continue
-					}
-					waiting = append(waiting, p.GetUsername())
+			for _, status := range statuses {
+				fmt.Printf("DKG round %s: %s\n", status.DKGRoundID, status.State)
+				if status.AbortedBy != "" {
+					fmt.Printf("\tAborted by %s: %s\n", status.AbortedBy, status.AbortReason)
+				}
+				for _, phase := range status.Phases {
+					printPhaseStatus(phase, username, "\t")
 				}
-				if strings.Contains(p.GetStatus().String(), "Error") {
-					failed = append(failed, p.GetUsername())
+				if len(status.PendingOperations) > 0 {
+					fmt.Printf("\tPending operations: %s\n", strings.Join(status.PendingOperations, ", "))
 				}
-				if strings.Contains(p.GetStatus().String(), "Confirmed") {
-					confirmed = append(confirmed, p.GetUsername())
+				if status.NextAction != "" {
+					fmt.Printf("\tNext action: %s\n", status.NextAction)
 				}
+				for _, signing := range status.Signings {
+					fmt.Printf("\tSigning ID %s: %s\n", signing.SigningID, signing.State)
+					if signing.AbortedBy != "" {
+						fmt.Printf("\t\tAborted by %s: %s\n", signing.AbortedBy, signing.AbortReason)
+					}
+					printPhaseStatus(signing.Phase, username, "\t\t")
+					if signing.NextAction != "" {
+						fmt.Printf("\t\tNext action: %s\n", signing.NextAction)
+					}
+				}
+				fmt.Println()
 			}
+			return nil
+		},
+	}
+}
 
-			if len(waiting) > 0 {
-				fmt.Printf("Waiting for data from: %s\n", strings.Join(waiting, ", "))
+func getDeadLettersCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dead_letters",
+		Short: "shows messages from the append-only log which the node failed to process",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			letters, err := apiClient.GetDeadLetters(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get dead letters: %w", err)
+			}
+			for _, letter := range letters {
+				fmt.Printf("Message ID: %s\n", letter.Message.ID)
+				fmt.Printf("\tOffset: %d, DKG round ID: %s, event: %s, sender: %s\n", letter.Message.Offset,
+					letter.Message.DkgRoundID, letter.Message.Event, letter.Message.SenderAddr)
+				fmt.Printf("\tError: %s\n", letter.Error)
+				fmt.Printf("\tAttempts: %d, last attempt: %s\n", letter.Attempts, letter.LastAttempt.Format(time.RFC3339))
+				if letter.Retryable {
+					fmt.Println("\tThe message arrived too early and is retried automatically")
+				}
 			}
-			if len(confirmed) > 0 {
-				fmt.Printf("Received data from: %s\n", strings.Join(confirmed, ", "))
+			return nil
+		},
+	}
+}
+
+func retryDeadLetterCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry_dead_letter [message_id]",
+		Short: "processes the failed message again and removes it from the dead letters on success",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiClient.RetryDeadLetter(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("failed to retry dead letter: %w", err)
 			}
-			if len(failed) > 0 {
-				fmt.Printf("Participants who got some error during a process: %s\n", strings.Join(waiting, ", "))
+			fmt.Println("ok")
+			return nil
+		},
+	}
+}
+
+func discardDeadLetterCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "discard_dead_letter [message_id]",
+		Short: "removes the failed message from the dead letters without processing it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := apiClient.DiscardDeadLetter(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("failed to discard dead letter: %w", err)
 			}
+			fmt.Println("ok")
+			return nil
+		},
+	}
+}
 
+func getLogCheckpointsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "log_checkpoints",
+		Short: "shows the head hash of the append-only log processed by the node and the head hashes published by participants",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checkpoints, err := apiClient.GetLogCheckpoints(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get log checkpoints: %w", err)
+			}
+			fmt.Printf("Head of the log: %d messages, hash %x\n", checkpoints.Offset, checkpoints.HeadHash)
+			if !checkpoints.Verified {
+				fmt.Println("The head hash is unknown, new messages are not checked for forks until the state is rebuilt")
+			}
+			for _, checkpoint := range checkpoints.Checkpoints {
+				fmt.Printf("Checkpoint of %s at offset %d (DKG round ID: %s): %d messages, hash %x\n",
+					checkpoint.Username, checkpoint.MessageOffset, checkpoint.DKGRoundID, checkpoint.Offset,
+					checkpoint.HeadHash)
+			}
 			return nil
 		},
 	}
 }
 
-func getFSMListCommand() *cobra.Command {
+func publishLogCheckpointCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "get_fsm_list",
-		Short: "returns a list of all FSMs served by the client",
+		Use:   "publish_log_checkpoint [dkg_id]",
+		Short: "publishes the signed head hash of the append-only log processed by the node, participants who see a different log stop",
+		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
+			if err := apiClient.PublishLogCheckpoint(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("failed to publish log checkpoint: %w", err)
+			}
+			fmt.Println("ok")
+			return nil
+		},
+	}
+}
+
+func getEquivocationsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "equivocations",
+		Short: "shows participants who have signed different messages for the same step of a DKG round",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			equivocations, err := apiClient.GetEquivocations(cmd.Context())
 			if err != nil {
-				return fmt.Errorf("failed to read configuration: %v", err)
+				return fmt.Errorf("failed to get equivocations: %w", err)
+			}
+			for _, equivocation := range equivocations {
+				fmt.Printf("Participant %s, DKG round ID: %s, step: %s\n", equivocation.Username,
+					equivocation.DKGRoundID, equivocation.Step)
+				for _, message := range []storage.Message{equivocation.First, equivocation.Second} {
+					fmt.Printf("\tMessage ID: %s, offset: %d, event: %s, signature: %s\n", message.ID, message.Offset,
+						message.Event, base64.StdEncoding.EncodeToString(message.Signature))
+				}
+				fmt.Printf("\tDetected at: %s", equivocation.DetectedAt.Format(time.RFC3339))
+				if equivocation.Reporter != "" {
+					fmt.Printf(", reported by %s", equivocation.Reporter)
+				}
+				fmt.Println()
 			}
+			return nil
+		},
+	}
+}
 
-			resp, err := rawGetRequest(fmt.Sprintf("http://%s/getFSMList", listenAddr))
+func exportTranscriptCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export_transcript [dkg_id] [file]",
+		Args:  cobra.ExactArgs(2),
+		Short: "saves the archive of the DKG round signed by the node, verify it with dc4bc_verify archive",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archive, err := apiClient.ExportTranscript(cmd.Context(), args[0])
 			if err != nil {
-				return fmt.Errorf("failed to make HTTP request to get FSM list: %w", err)
+				return fmt.Errorf("failed to export transcript: %w", err)
 			}
-			if resp.ErrorMessage != "" {
-				return fmt.Errorf("failed to make HTTP request to get FSM list: %v", resp.ErrorMessage)
+			archiveBz, err := json.MarshalIndent(archive, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal archive: %w", err)
+			}
+			if err = ioutil.WriteFile(args[1], archiveBz, 0644); err != nil {
+				return fmt.Errorf("failed to save archive: %w", err)
+			}
+			fmt.Printf("Archive of DKG round %s is saved to %s\n", args[0], args[1])
+			return nil
+		},
+	}
+}
+
+// printPhaseStatus prints the progress of every participant in the phase
+func printPhaseStatus(phase api.PhaseStatus, username string, indent string) {
+	timeLeft := "expired"
+	if phase.TimeLeft > 0 {
+		timeLeft = fmt.Sprintf("%s left", phase.TimeLeft.Truncate(time.Minute))
+	}
+	fmt.Printf("%s%s: %d/%d acted, deadline %s (%s)\n", indent, phase.Name, phase.Acted(), len(phase.Participants),
+		phase.Deadline.Format(time.RFC3339), timeLeft)
+
+	for _, participant := range phase.Participants {
+		name := participant.Username
+		if name == username {
+			name += " (you)"
+		}
+		mark := "waiting"
+		if participant.Acted {
+			mark = "done"
+		}
+		line := fmt.Sprintf("%s\t%-7s %s: %s", indent, mark, name, participant.Status)
+		if participant.Error != "" {
+			line += fmt.Sprintf(" (%s)", participant.Error)
+		}
+		fmt.Println(line)
+	}
+}
+
+// printQuorumStatus prints participants grouped by their status
+func printQuorumStatus(quorum map[int]state_machines.Participant, username string, indent string) {
+	waiting := make([]string, 0)
+	confirmed := make([]string, 0)
+	failed := make([]string, 0)
+	declined := make([]string, 0)
+
+	for _, p := range quorum {
+		if strings.Contains(p.GetStatus().String(), "Declined") {
+			entry := p.GetUsername()
+			if d, ok := p.(interface{ GetDeclineReason() string }); ok && d.GetDeclineReason() != "" {
+				entry = fmt.Sprintf("%s (%s)", entry, d.GetDeclineReason())
+			}
+			declined = append(declined, entry)
+		}
+		if strings.Contains(p.GetStatus().String(), "Await") {
+			// deals are private messages, so we don't need to wait messages from ourself
+			if p.GetStatus().String() == "DealAwaitConfirmation" && p.GetUsername() == username {
+				continue
+			}
+			waiting = append(waiting, p.GetUsername())
+		}
+		if strings.Contains(p.GetStatus().String(), "Error") {
+			failed = append(failed, p.GetUsername())
+		}
+		if strings.Contains(p.GetStatus().String(), "Confirmed") {
+			confirmed = append(confirmed, p.GetUsername())
+		}
+	}
+
+	if len(waiting) > 0 {
+		fmt.Printf("%sWaiting for data from: %s\n", indent, strings.Join(waiting, ", "))
+	}
+	if len(confirmed) > 0 {
+		fmt.Printf("%sReceived data from: %s\n", indent, strings.Join(confirmed, ", "))
+	}
+	if len(failed) > 0 {
+		fmt.Printf("%sParticipants who got some error during a process: %s\n", indent, strings.Join(failed, ", "))
+	}
+	if len(declined) > 0 {
+		fmt.Printf("%sDeclined by: %s\n", indent, strings.Join(declined, ", "))
+	}
+}
+
+func getFSMListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get_fsm_list",
+		Short: "returns a list of all FSMs served by the client",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fsms, err := apiClient.GetFSMList(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to make HTTP request to get FSM list: %w", err)
 			}
-			fsms := resp.Result.(map[string]interface{})
 			for dkgID, state := range fsms {
-				fmt.Printf("DKG ID: %s - FSM state: %s\n", dkgID, state.(string))
+				fmt.Printf("DKG ID: %s - FSM state: %s\n", dkgID, state)
+			}
+			return nil
+		},
+	}
+}
+
+func watchCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "watch [dkg_id]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "shows FSM transitions, new operations, signatures and rejected messages live, optionally for one DKG round",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			sigs := make(chan os.Signal, 1)
+			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(sigs)
+			go func() {
+				select {
+				case <-sigs:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			err := apiClient.WatchEvents(ctx, optionalArg(args, 0), func(event api.Event) error {
+				printEvent(event)
+				return nil
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return fmt.Errorf("failed to watch events: %w", err)
 			}
 			return nil
 		},
 	}
 }
+
+// printEvent prints a one-line description of the event for an operator
+func printEvent(event api.Event) {
+	var description string
+	switch event.Type {
+	case api.EventFSMStateChanged:
+		description = fmt.Sprintf("state changed to %s by %s from %s", event.State, event.MessageEvent, event.Sender)
+	case api.EventNewOperation:
+		description = fmt.Sprintf("new operation %s: %s", event.Operation.ID,
+			getShortOperationDescription(event.Operation.Type))
+	case api.EventSignatureReconstructed:
+		description = fmt.Sprintf("signature reconstructed by %s", event.Sender)
+	case api.EventMessageRejected:
+		description = fmt.Sprintf("message %s from %s with offset %d rejected: %s", event.MessageEvent,
+			event.Sender, event.MessageOffset, event.Error)
+	case api.EventSigningRejected:
+		description = fmt.Sprintf("queued signing proposal rejected: %s", event.Error)
+	default:
+		description = string(event.Type)
+	}
+
+	subject := fmt.Sprintf("DKG round %s", event.DKGRoundID)
+	if event.SigningID != "" {
+		subject = fmt.Sprintf("%s, signing %s", subject, event.SigningID)
+	}
+	fmt.Printf("[%s] %s: %s\n", event.Time.Format("15:04:05"), subject, description)
+}
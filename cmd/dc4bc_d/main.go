@@ -3,11 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/lidofinance/dc4bc/client"
 	"github.com/lidofinance/dc4bc/qr"
@@ -31,6 +33,19 @@ const (
 	flagChunkSize                = "chunk_size"
 	flagConfig                   = "config"
 	flagSkipCommKeysVerification = "skip_comm_keys_verification"
+	flagHTTPTLSCert              = "http_tls_cert"
+	flagHTTPTLSKey               = "http_tls_key"
+	flagHTTPClientCA             = "http_client_ca"
+	flagHTTPCredentials          = "http_credentials"
+	flagHTTPAuditLog             = "http_audit_log"
+	flagGRPCListenAddr           = "grpc_listen_addr"
+	flagWebhookURL               = "webhook_url"
+	flagWebhookSecret            = "webhook_secret"
+	flagWebhookDeadlineWarning   = "webhook_deadline_warning"
+	flagVerifyOnly               = "verify_only"
+	flagBroadcastEquivocations   = "broadcast_equivocations"
+	flagWeb3SignerListenAddr     = "web3signer_listen_addr"
+	flagWeb3SignerTimeout        = "web3signer_timeout"
 )
 
 var (
@@ -53,6 +68,18 @@ func init() {
 	rootCmd.PersistentFlags().Int(flagChunkSize, 256, "QR-code's chunk size")
 	rootCmd.PersistentFlags().StringVar(&cfgFile, flagConfig, "", "path to your config file")
 	rootCmd.PersistentFlags().Bool(flagSkipCommKeysVerification, false, "verify messages from append-log or not")
+	rootCmd.PersistentFlags().String(flagHTTPTLSCert, "", "Path to TLS certificate of HTTP API, enables HTTPS")
+	rootCmd.PersistentFlags().String(flagHTTPTLSKey, "", "Path to TLS key of HTTP API")
+	rootCmd.PersistentFlags().String(flagHTTPClientCA, "", "Path to CA certificate of HTTP API clients, enables mutual TLS")
+	rootCmd.PersistentFlags().String(flagHTTPCredentials, "", "Path to JSON file with HTTP API credentials, enables authentication")
+	rootCmd.PersistentFlags().String(flagHTTPAuditLog, "", "Path to audit log of HTTP API calls")
+	rootCmd.PersistentFlags().String(flagGRPCListenAddr, "", "Listen address of gRPC API, the API is disabled if empty. TLS, credentials and audit log flags of HTTP API are applied to it as well")
+	rootCmd.PersistentFlags().StringSlice(flagWebhookURL, nil, "URL to POST webhooks about new operations, FSM state changes and approaching deadlines to, can be repeated")
+	rootCmd.PersistentFlags().String(flagWebhookSecret, "", "Key of HMAC-SHA256 signatures of webhooks, required if webhooks are enabled")
+	rootCmd.PersistentFlags().Duration(flagWebhookDeadlineWarning, 24*time.Hour, "How long before a deadline a webhook is sent, 0 disables deadline webhooks")
+	rootCmd.PersistentFlags().Bool(flagBroadcastEquivocations, false, "Send evidences of participants who sign different messages for the same step to the append-only log")
+	rootCmd.PersistentFlags().String(flagWeb3SignerListenAddr, "", "Listen address of Web3Signer compatible signing API, the API is disabled if empty. TLS flags of HTTP API are applied to it as well")
+	rootCmd.PersistentFlags().Duration(flagWeb3SignerTimeout, 10*time.Second, "How long a Web3Signer signing request waits for the threshold signature before the pending status is returned")
 
 	exitIfError(viper.BindPFlag(flagUserName, rootCmd.PersistentFlags().Lookup(flagUserName)))
 	exitIfError(viper.BindPFlag(flagListenAddr, rootCmd.PersistentFlags().Lookup(flagListenAddr)))
@@ -67,6 +94,18 @@ func init() {
 	exitIfError(viper.BindPFlag(flagChunkSize, rootCmd.PersistentFlags().Lookup(flagChunkSize)))
 	exitIfError(viper.BindPFlag(flagUserName, rootCmd.PersistentFlags().Lookup(flagUserName)))
 	exitIfError(viper.BindPFlag(flagSkipCommKeysVerification, rootCmd.PersistentFlags().Lookup(flagSkipCommKeysVerification)))
+	exitIfError(viper.BindPFlag(flagHTTPTLSCert, rootCmd.PersistentFlags().Lookup(flagHTTPTLSCert)))
+	exitIfError(viper.BindPFlag(flagHTTPTLSKey, rootCmd.PersistentFlags().Lookup(flagHTTPTLSKey)))
+	exitIfError(viper.BindPFlag(flagHTTPClientCA, rootCmd.PersistentFlags().Lookup(flagHTTPClientCA)))
+	exitIfError(viper.BindPFlag(flagHTTPCredentials, rootCmd.PersistentFlags().Lookup(flagHTTPCredentials)))
+	exitIfError(viper.BindPFlag(flagHTTPAuditLog, rootCmd.PersistentFlags().Lookup(flagHTTPAuditLog)))
+	exitIfError(viper.BindPFlag(flagGRPCListenAddr, rootCmd.PersistentFlags().Lookup(flagGRPCListenAddr)))
+	exitIfError(viper.BindPFlag(flagWebhookURL, rootCmd.PersistentFlags().Lookup(flagWebhookURL)))
+	exitIfError(viper.BindPFlag(flagWebhookSecret, rootCmd.PersistentFlags().Lookup(flagWebhookSecret)))
+	exitIfError(viper.BindPFlag(flagWebhookDeadlineWarning, rootCmd.PersistentFlags().Lookup(flagWebhookDeadlineWarning)))
+	exitIfError(viper.BindPFlag(flagBroadcastEquivocations, rootCmd.PersistentFlags().Lookup(flagBroadcastEquivocations)))
+	exitIfError(viper.BindPFlag(flagWeb3SignerListenAddr, rootCmd.PersistentFlags().Lookup(flagWeb3SignerListenAddr)))
+	exitIfError(viper.BindPFlag(flagWeb3SignerTimeout, rootCmd.PersistentFlags().Lookup(flagWeb3SignerTimeout)))
 }
 
 func exitIfError(err error) {
@@ -117,6 +156,33 @@ func parseKafkaAuthCredentials(creds string) (*storage.KafkaAuthCredentials, err
 	}, nil
 }
 
+func newKafkaStorage(ctx context.Context, storageTopic string) (storage.Storage, error) {
+	kafkaTrustStorePath := viper.GetString(flagKafkaTrustStorePath)
+	tlsConfig, err := storage.GetTLSConfig(kafkaTrustStorePath)
+	if err != nil {
+		return nil, fmt.Errorf("faile to create tls config: %w", err)
+	}
+
+	producerCredentials := viper.GetString(flagKafkaProducerCredentials)
+	producerCreds, err := parseKafkaAuthCredentials(producerCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kafka credentials: %w", err)
+	}
+
+	consumerCredentials := viper.GetString(flagKafkaConsumerCredentials)
+	consumerCreds, err := parseKafkaAuthCredentials(consumerCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kafka credentials: %w", err)
+	}
+
+	storageDBDSN := viper.GetString(flagStorageDBDSN)
+	stg, err := storage.NewKafkaStorage(ctx, storageDBDSN, storageTopic, tlsConfig, producerCreds, consumerCreds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init storage client: %w", err)
+	}
+	return stg, nil
+}
+
 func startClientCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "start",
@@ -132,28 +198,9 @@ func startClientCommand() *cobra.Command {
 				return fmt.Errorf("failed to init state client: %w", err)
 			}
 
-			kafkaTrustStorePath := viper.GetString(flagKafkaTrustStorePath)
-			tlsConfig, err := storage.GetTLSConfig(kafkaTrustStorePath)
-			if err != nil {
-				return fmt.Errorf("faile to create tls config: %w", err)
-			}
-
-			producerCredentials := viper.GetString(flagKafkaProducerCredentials)
-			producerCreds, err := parseKafkaAuthCredentials(producerCredentials)
-			if err != nil {
-				return fmt.Errorf("failed to parse kafka credentials: %w", err)
-			}
-
-			consumerCredentials := viper.GetString(flagKafkaConsumerCredentials)
-			consumerCreds, err := parseKafkaAuthCredentials(consumerCredentials)
-			if err != nil {
-				return fmt.Errorf("failed to parse kafka credentials: %w", err)
-			}
-
-			storageDBDSN := viper.GetString(flagStorageDBDSN)
-			stg, err := storage.NewKafkaStorage(ctx, storageDBDSN, storageTopic, tlsConfig, producerCreds, consumerCreds)
+			stg, err := newKafkaStorage(ctx, storageTopic)
 			if err != nil {
-				return fmt.Errorf("failed to init storage client: %w", err)
+				return err
 			}
 
 			username := viper.GetString(flagUserName)
@@ -175,6 +222,7 @@ func startClientCommand() *cobra.Command {
 				return fmt.Errorf("failed to init client: %w", err)
 			}
 			cli.SetSkipCommKeysVerification(viper.GetBool(flagSkipCommKeysVerification))
+			cli.SetBroadcastEquivocations(viper.GetBool(flagBroadcastEquivocations))
 
 			sigs := make(chan os.Signal, 1)
 			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
@@ -188,13 +236,65 @@ func startClientCommand() *cobra.Command {
 				os.Exit(0)
 			}()
 
-			listenAddress := viper.GetString(flagListenAddr)
+			httpConfig := client.HTTPServerConfig{
+				ListenAddr:   viper.GetString(flagListenAddr),
+				TLSCertFile:  viper.GetString(flagHTTPTLSCert),
+				TLSKeyFile:   viper.GetString(flagHTTPTLSKey),
+				ClientCAFile: viper.GetString(flagHTTPClientCA),
+				AuditLogPath: viper.GetString(flagHTTPAuditLog),
+			}
+			if credentialsPath := viper.GetString(flagHTTPCredentials); credentialsPath != "" {
+				if httpConfig.Credentials, err = client.LoadAPICredentials(credentialsPath); err != nil {
+					return fmt.Errorf("failed to load HTTP API credentials: %w", err)
+				}
+			}
 
 			go func() {
-				if err := cli.StartHTTPServer(listenAddress); err != nil {
+				if err := cli.StartHTTPServerWithConfig(httpConfig); err != nil {
 					log.Fatalf("HTTP server error: %v", err)
 				}
 			}()
+			if grpcListenAddr := viper.GetString(flagGRPCListenAddr); grpcListenAddr != "" {
+				grpcConfig := client.GRPCServerConfig{
+					ListenAddr:   grpcListenAddr,
+					TLSCertFile:  httpConfig.TLSCertFile,
+					TLSKeyFile:   httpConfig.TLSKeyFile,
+					ClientCAFile: httpConfig.ClientCAFile,
+					Credentials:  httpConfig.Credentials,
+					AuditLogPath: httpConfig.AuditLogPath,
+				}
+				go func() {
+					if err := cli.StartGRPCServer(grpcConfig); err != nil {
+						log.Fatalf("gRPC server error: %v", err)
+					}
+				}()
+			}
+			if web3SignerListenAddr := viper.GetString(flagWeb3SignerListenAddr); web3SignerListenAddr != "" {
+				web3SignerConfig := client.Web3SignerConfig{
+					ListenAddr:   web3SignerListenAddr,
+					TLSCertFile:  httpConfig.TLSCertFile,
+					TLSKeyFile:   httpConfig.TLSKeyFile,
+					ClientCAFile: httpConfig.ClientCAFile,
+					Credentials:  httpConfig.Credentials,
+					AuditLogPath: httpConfig.AuditLogPath,
+					Timeout:      viper.GetDuration(flagWeb3SignerTimeout),
+				}
+				go func() {
+					if err := cli.StartWeb3SignerServer(web3SignerConfig); err != nil {
+						log.Fatalf("Web3Signer server error: %v", err)
+					}
+				}()
+			}
+			if webhookURLs := viper.GetStringSlice(flagWebhookURL); len(webhookURLs) != 0 {
+				webhookConfig := client.WebhookConfig{
+					URLs:            webhookURLs,
+					Secret:          viper.GetString(flagWebhookSecret),
+					DeadlineWarning: viper.GetDuration(flagWebhookDeadlineWarning),
+				}
+				if err := cli.StartWebhooks(webhookConfig); err != nil {
+					return fmt.Errorf("failed to start webhooks: %w", err)
+				}
+			}
 			cli.GetLogger().Log("Client started to poll messages from append-only log")
 			cli.GetLogger().Log("Waiting for messages from append-only log...")
 			if err = cli.Poll(); err != nil {
@@ -206,6 +306,116 @@ func startClientCommand() *cobra.Command {
 	}
 }
 
+func rebuildStateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebuild_state",
+		Short: "replays the append-only log into a fresh state and replaces the state with it, the client must be stopped",
+		Long: `Replays all messages of the append-only log into a fresh state database, operations already answered on the log are dropped.
+The rebuilt state is compared with the current one and the differences are reported. The current state is kept as a backup
+next to the rebuilt one. With --verify_only the current state is not changed and the command fails if the states diverge.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verifyOnly, err := cmd.Flags().GetBool(flagVerifyOnly)
+			if err != nil {
+				return fmt.Errorf("failed to read %s flag: %w", flagVerifyOnly, err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			storageTopic := viper.GetString(flagStorageTopic)
+			stateDBDSN := viper.GetString(flagStateDBDSN)
+			state, err := client.NewLevelDBState(stateDBDSN, storageTopic)
+			if err != nil {
+				return fmt.Errorf("failed to init state client: %w", err)
+			}
+			defer closeState(state)
+
+			rebuiltDBDSN := fmt.Sprintf("%s.rebuild-%d", stateDBDSN, time.Now().Unix())
+			if verifyOnly {
+				defer os.RemoveAll(rebuiltDBDSN)
+			}
+			rebuilt, err := client.NewLevelDBState(rebuiltDBDSN, storageTopic)
+			if err != nil {
+				return fmt.Errorf("failed to init rebuilt state: %w", err)
+			}
+			defer closeState(rebuilt)
+
+			stg, err := newKafkaStorage(ctx, storageTopic)
+			if err != nil {
+				return err
+			}
+
+			username := viper.GetString(flagUserName)
+			keyStore, err := client.NewLevelDBKeyStore(username, viper.GetString(flagStoreDBDSN))
+			if err != nil {
+				return fmt.Errorf("failed to init key store: %w", err)
+			}
+
+			cli, err := client.NewClient(ctx, username, state, stg, keyStore, qr.NewCameraProcessor())
+			if err != nil {
+				return fmt.Errorf("failed to init client: %w", err)
+			}
+			cli.SetSkipCommKeysVerification(viper.GetBool(flagSkipCommKeysVerification))
+
+			report, err := cli.RebuildState(rebuilt)
+			if err != nil {
+				return fmt.Errorf("failed to rebuild state: %w", err)
+			}
+			printRebuildReport(report)
+
+			if verifyOnly {
+				if len(report.Differences) != 0 {
+					return fmt.Errorf("state diverges from the append-only log in %d places", len(report.Differences))
+				}
+				fmt.Println("State matches the append-only log")
+				return nil
+			}
+
+			closeState(state)
+			closeState(rebuilt)
+			backupDBDSN := fmt.Sprintf("%s.backup-%d", stateDBDSN, time.Now().Unix())
+			if err = os.Rename(stateDBDSN, backupDBDSN); err != nil {
+				return fmt.Errorf("failed to back up state: %w", err)
+			}
+			if err = os.Rename(rebuiltDBDSN, stateDBDSN); err != nil {
+				return fmt.Errorf("failed to replace state with the rebuilt one: %w", err)
+			}
+			fmt.Printf("State is rebuilt, the previous state is kept in %s\n", backupDBDSN)
+			return nil
+		},
+	}
+	cmd.Flags().Bool(flagVerifyOnly, false, "Only compare the state with the append-only log, don't replace it")
+	return cmd
+}
+
+// closeState closes the state database, it's safe to call it more than once
+func closeState(state client.State) {
+	if closer, ok := state.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+func printRebuildReport(report *client.RebuildReport) {
+	fmt.Printf("Replayed messages: %d\n", report.Messages)
+	fmt.Printf("Offset: current %d, rebuilt %d\n", report.CurrentOffset, report.RebuiltOffset)
+	fmt.Printf("Operations already answered on the log: %d\n", len(report.AnsweredOperations))
+	if len(report.DeadLetters) != 0 {
+		fmt.Printf("Messages failed during the replay: %d\n", len(report.DeadLetters))
+		for _, letter := range report.DeadLetters {
+			fmt.Printf("\t%s (offset %d, event %s): %s\n", letter.Message.ID, letter.Message.Offset,
+				letter.Message.Event, letter.Error)
+		}
+	}
+	if len(report.Differences) == 0 {
+		fmt.Println("No differences between the current and the rebuilt state")
+		return
+	}
+	fmt.Printf("Differences between the current and the rebuilt state: %d\n", len(report.Differences))
+	for _, difference := range report.Differences {
+		fmt.Printf("\t%s\n", difference)
+	}
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "dc4bc_d",
 	Short: "dc4bc client daemon implementation",
@@ -215,6 +425,7 @@ func main() {
 	rootCmd.AddCommand(
 		startClientCommand(),
 		genKeyPairCommand(),
+		rebuildStateCommand(),
 	)
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Failed to execute root command: %v", err)
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/lidofinance/dc4bc/transcript"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagDKGRoundID = "dkg_round_id"
+	flagReport     = "report"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "dc4bc_verify",
+	Short: "offline verification of dc4bc ceremony transcripts",
+}
+
+func verifyLogCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log [file]",
+		Short: "verifies signatures of messages and the distributed public key of DKG rounds in an exported log",
+		Long: "verifies an append-only log exported from the FileStorage or dumped from Kafka as JSON lines or a JSON " +
+			"array of messages: gaps and commitments to head hashes of the log, signatures of all messages of DKG " +
+			"rounds against their rosters and master keys confirmed by participants against the distributed " +
+			"public key recomputed from the broadcast commits. Exits with a non-zero code if the log is invalid",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dkgRoundIDs, err := cmd.Flags().GetStringSlice(flagDKGRoundID)
+			if err != nil {
+				return fmt.Errorf("failed to read configuration: %w", err)
+			}
+			reportPath, err := cmd.Flags().GetString(flagReport)
+			if err != nil {
+				return fmt.Errorf("failed to read configuration: %w", err)
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open log: %w", err)
+			}
+			defer f.Close()
+
+			messages, err := transcript.ReadLog(f)
+			if err != nil {
+				return err
+			}
+			report := transcript.VerifyLog(messages, dkgRoundIDs...)
+			if err = writeReport(report, reportPath); err != nil {
+				return err
+			}
+			if !report.Valid {
+				return fmt.Errorf("log is invalid")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSlice(flagDKGRoundID, nil, "IDs of DKG rounds to verify, all proposed rounds by default")
+	cmd.Flags().String(flagReport, "", "Path to save the verification report, it's printed to stdout by default")
+	return cmd
+}
+
+func verifyArchiveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive [file]",
+		Short: "verifies a ceremony archive exported with dc4bc_cli export_transcript",
+		Long: "verifies the signature of a ceremony archive against the roster of the DKG round, the messages of the " +
+			"round as the log command does, the FSM state history against the messages and the reconstructed " +
+			"signatures against the master public key. Exits with a non-zero code if the archive is invalid",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reportPath, err := cmd.Flags().GetString(flagReport)
+			if err != nil {
+				return fmt.Errorf("failed to read configuration: %w", err)
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open archive: %w", err)
+			}
+			defer f.Close()
+
+			archive, err := transcript.ReadArchive(f)
+			if err != nil {
+				return err
+			}
+			report := transcript.VerifyArchive(archive)
+			if err = writeReport(report, reportPath); err != nil {
+				return err
+			}
+			if !report.Valid {
+				return fmt.Errorf("archive is invalid")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(flagReport, "", "Path to save the verification report, it's printed to stdout by default")
+	return cmd
+}
+
+// writeReport writes the report as JSON to the file or to stdout if the path is empty
+func writeReport(report interface{}, path string) error {
+	reportBz, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if path == "" {
+		fmt.Println(string(reportBz))
+		return nil
+	}
+	if err = ioutil.WriteFile(path, reportBz, 0644); err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+	return nil
+}
+
+func main() {
+	rootCmd.AddCommand(
+		verifyLogCommand(),
+		verifyArchiveCommand(),
+	)
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatalf("Failed to execute root command: %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package eth2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SlotsPerEpoch is the number of slots in an epoch of the mainnet and the testnets
+const SlotsPerEpoch = 32
+
+var (
+	// DomainBeaconProposer is the type of the signature domain of blocks
+	DomainBeaconProposer = [4]byte{0x00, 0x00, 0x00, 0x00}
+	// DomainBeaconAttester is the type of the signature domain of attestations
+	DomainBeaconAttester = [4]byte{0x01, 0x00, 0x00, 0x00}
+)
+
+// Root is a 32 bytes long root, it's encoded in JSON as a hex string with the 0x prefix like in the beacon node API
+type Root [32]byte
+
+func (r Root) MarshalText() ([]byte, error) {
+	return []byte(encodeHex(r[:])), nil
+}
+
+func (r *Root) UnmarshalText(text []byte) error {
+	root, err := ParseRoot(string(text))
+	if err != nil {
+		return err
+	}
+	*r = root
+	return nil
+}
+
+// Version is a fork version, it's encoded in JSON as a hex string with the 0x prefix like in the beacon node API
+type Version [4]byte
+
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(encodeHex(v[:])), nil
+}
+
+func (v *Version) UnmarshalText(text []byte) error {
+	version, err := ParseForkVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = version
+	return nil
+}
+
+// Bytes is a byte slice of a variable length, e.g. a public key, it's encoded in JSON as a hex string
+// with the 0x prefix like in the beacon node API
+type Bytes []byte
+
+func (b Bytes) MarshalText() ([]byte, error) {
+	return []byte(encodeHex(b)), nil
+}
+
+func (b *Bytes) UnmarshalText(text []byte) error {
+	bz, err := decodeHex(string(text))
+	if err != nil {
+		return err
+	}
+	*b = bz
+	return nil
+}
+
+// BeaconBlockHeader is a header of a beacon block, the block root is the root of its header, so signing
+// the header signs the block
+type BeaconBlockHeader struct {
+	Slot          uint64 `json:"slot,string"`
+	ProposerIndex uint64 `json:"proposer_index,string"`
+	ParentRoot    Root   `json:"parent_root"`
+	StateRoot     Root   `json:"state_root"`
+	BodyRoot      Root   `json:"body_root"`
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the header
+func (h BeaconBlockHeader) HashTreeRoot() [32]byte {
+	return merkleize(uint64Root(h.Slot), uint64Root(h.ProposerIndex), h.ParentRoot, h.StateRoot, h.BodyRoot)
+}
+
+// Checkpoint is an epoch boundary block
+type Checkpoint struct {
+	Epoch uint64 `json:"epoch,string"`
+	Root  Root   `json:"root"`
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the checkpoint
+func (c Checkpoint) HashTreeRoot() [32]byte {
+	return merkleize(uint64Root(c.Epoch), c.Root)
+}
+
+// AttestationData is a vote of the validator signed in an attestation
+type AttestationData struct {
+	Slot            uint64     `json:"slot,string"`
+	Index           uint64     `json:"index,string"`
+	BeaconBlockRoot Root       `json:"beacon_block_root"`
+	Source          Checkpoint `json:"source"`
+	Target          Checkpoint `json:"target"`
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the attestation data
+func (d AttestationData) HashTreeRoot() [32]byte {
+	return merkleize(uint64Root(d.Slot), uint64Root(d.Index), d.BeaconBlockRoot, d.Source.HashTreeRoot(),
+		d.Target.HashTreeRoot())
+}
+
+// Fork is the fork of the network at an epoch
+type Fork struct {
+	PreviousVersion Version `json:"previous_version"`
+	CurrentVersion  Version `json:"current_version"`
+	Epoch           uint64  `json:"epoch,string"`
+}
+
+// ForkInfo is the fork of the network with its genesis validators root, it's sent by validator clients
+// to remote signers with every signing request
+type ForkInfo struct {
+	Fork                  Fork `json:"fork"`
+	GenesisValidatorsRoot Root `json:"genesis_validators_root"`
+}
+
+// VersionAt returns the fork version which objects of the epoch are signed with
+func (f ForkInfo) VersionAt(epoch uint64) Version {
+	if epoch < f.Fork.Epoch {
+		return f.Fork.PreviousVersion
+	}
+	return f.Fork.CurrentVersion
+}
+
+const (
+	SigningObjectBlock                = "BLOCK"
+	SigningObjectAttestation          = "ATTESTATION"
+	SigningObjectDeposit              = "DEPOSIT"
+	SigningObjectVoluntaryExit        = "VOLUNTARY_EXIT"
+	SigningObjectBLSToExecutionChange = "BLS_TO_EXECUTION_CHANGE"
+)
+
+// SigningObject is a beacon object whose signing root is proposed for signing. It's carried by the signing
+// proposal next to the signing root, so signers can check the root and apply slashing protection to blocks
+// and attestations. Deposits and BLS to execution changes are signed with the genesis fork version,
+// deposits are signed with the empty genesis validators root
+type SigningObject struct {
+	Type                  string                `json:"type"`
+	ForkVersion           Version               `json:"fork_version"`
+	GenesisValidatorsRoot Root                  `json:"genesis_validators_root"`
+	Block                 *BeaconBlockHeader    `json:"block,omitempty"`
+	Attestation           *AttestationData      `json:"attestation,omitempty"`
+	Deposit               *DepositMessage       `json:"deposit,omitempty"`
+	VoluntaryExit         *VoluntaryExit        `json:"voluntary_exit,omitempty"`
+	BLSToExecutionChange  *BLSToExecutionChange `json:"bls_to_execution_change,omitempty"`
+}
+
+// NewBlockSigningObject returns the signing object of the block header, the domain is computed with the fork
+// version of the epoch of the block
+func NewBlockSigningObject(header BeaconBlockHeader, forkInfo ForkInfo) *SigningObject {
+	return &SigningObject{
+		Type:                  SigningObjectBlock,
+		ForkVersion:           forkInfo.VersionAt(header.Slot / SlotsPerEpoch),
+		GenesisValidatorsRoot: forkInfo.GenesisValidatorsRoot,
+		Block:                 &header,
+	}
+}
+
+// NewAttestationSigningObject returns the signing object of the attestation data, the domain is computed
+// with the fork version of the target epoch
+func NewAttestationSigningObject(data AttestationData, forkInfo ForkInfo) *SigningObject {
+	return &SigningObject{
+		Type:                  SigningObjectAttestation,
+		ForkVersion:           forkInfo.VersionAt(data.Target.Epoch),
+		GenesisValidatorsRoot: forkInfo.GenesisValidatorsRoot,
+		Attestation:           &data,
+	}
+}
+
+// NewDepositSigningObject returns the signing object of the deposit message for the genesis fork version
+func NewDepositSigningObject(message DepositMessage, genesisForkVersion [4]byte) *SigningObject {
+	return &SigningObject{
+		Type:        SigningObjectDeposit,
+		ForkVersion: genesisForkVersion,
+		Deposit:     &message,
+	}
+}
+
+// NewVoluntaryExitSigningObject returns the signing object of the voluntary exit
+func NewVoluntaryExitSigningObject(exit VoluntaryExit, forkVersion [4]byte,
+	genesisValidatorsRoot [32]byte) *SigningObject {
+	return &SigningObject{
+		Type:                  SigningObjectVoluntaryExit,
+		ForkVersion:           forkVersion,
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+		VoluntaryExit:         &exit,
+	}
+}
+
+// NewBLSToExecutionChangeSigningObject returns the signing object of the change for the genesis fork version
+func NewBLSToExecutionChangeSigningObject(change BLSToExecutionChange, genesisForkVersion [4]byte,
+	genesisValidatorsRoot [32]byte) *SigningObject {
+	return &SigningObject{
+		Type:                  SigningObjectBLSToExecutionChange,
+		ForkVersion:           genesisForkVersion,
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+		BLSToExecutionChange:  &change,
+	}
+}
+
+// ParseSigningObject decodes the JSON encoded signing object and validates it
+func ParseSigningObject(bz []byte) (*SigningObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bz))
+	decoder.DisallowUnknownFields()
+
+	var object SigningObject
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("failed to decode signing object: %w", err)
+	}
+	if err := object.Validate(); err != nil {
+		return nil, err
+	}
+	return &object, nil
+}
+
+// Validate checks that the signing object has exactly the object of its type
+func (o *SigningObject) Validate() error {
+	objects := 0
+	for _, set := range []bool{o.Block != nil, o.Attestation != nil, o.Deposit != nil, o.VoluntaryExit != nil,
+		o.BLSToExecutionChange != nil} {
+		if set {
+			objects++
+		}
+	}
+	switch o.Type {
+	case SigningObjectBlock:
+		if o.Block == nil || objects != 1 {
+			return errors.New("block signing object must have only the block")
+		}
+	case SigningObjectAttestation:
+		if o.Attestation == nil || objects != 1 {
+			return errors.New("attestation signing object must have only the attestation")
+		}
+		if o.Attestation.Source.Epoch > o.Attestation.Target.Epoch {
+			return fmt.Errorf("source epoch %d is greater than target epoch %d", o.Attestation.Source.Epoch,
+				o.Attestation.Target.Epoch)
+		}
+	case SigningObjectDeposit:
+		if o.Deposit == nil || objects != 1 {
+			return errors.New("deposit signing object must have only the deposit message")
+		}
+		if o.GenesisValidatorsRoot != (Root{}) {
+			return errors.New("deposit signing object must have the empty genesis validators root")
+		}
+		return o.Deposit.Validate()
+	case SigningObjectVoluntaryExit:
+		if o.VoluntaryExit == nil || objects != 1 {
+			return errors.New("voluntary exit signing object must have only the voluntary exit")
+		}
+	case SigningObjectBLSToExecutionChange:
+		if o.BLSToExecutionChange == nil || objects != 1 {
+			return errors.New("BLS to execution change signing object must have only the change")
+		}
+		return o.BLSToExecutionChange.Validate()
+	default:
+		return fmt.Errorf("unknown signing object type %q", o.Type)
+	}
+	return nil
+}
+
+// SigningRoot returns the root which is signed for the object
+func (o *SigningObject) SigningRoot() ([32]byte, error) {
+	if err := o.Validate(); err != nil {
+		return [32]byte{}, err
+	}
+	switch {
+	case o.Block != nil:
+		return SigningRoot(o.Block.HashTreeRoot(), ComputeDomain(DomainBeaconProposer, o.ForkVersion,
+			o.GenesisValidatorsRoot)), nil
+	case o.Deposit != nil:
+		return o.Deposit.SigningRoot(o.ForkVersion), nil
+	case o.VoluntaryExit != nil:
+		return o.VoluntaryExit.SigningRoot(o.ForkVersion, o.GenesisValidatorsRoot), nil
+	case o.BLSToExecutionChange != nil:
+		return o.BLSToExecutionChange.SigningRoot(o.ForkVersion, o.GenesisValidatorsRoot), nil
+	}
+	return SigningRoot(o.Attestation.HashTreeRoot(), ComputeDomain(DomainBeaconAttester, o.ForkVersion,
+		o.GenesisValidatorsRoot)), nil
+}
+
+// Slashable reports whether the object is a block or an attestation, which are checked by slashing protection
+func (o *SigningObject) Slashable() bool {
+	return o.Block != nil || o.Attestation != nil
+}
@@ -0,0 +1,176 @@
+package eth2
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSigningObject(t *testing.T) {
+	// attestation and fork info as they come in a signing request of a validator client
+	var request struct {
+		ForkInfo    ForkInfo        `json:"fork_info"`
+		Attestation AttestationData `json:"attestation"`
+	}
+	requestJSON := `{
+		"fork_info": {
+			"fork": {"previous_version": "0x02000000", "current_version": "0x03000000", "epoch": "194048"},
+			"genesis_validators_root": "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"
+		},
+		"attestation": {
+			"slot": "6220000", "index": "7",
+			"beacon_block_root": "0x0101010101010101010101010101010101010101010101010101010101010101",
+			"source": {"epoch": "194373", "root": "0x0202020202020202020202020202020202020202020202020202020202020202"},
+			"target": {"epoch": "194374", "root": "0x0303030303030303030303030303030303030303030303030303030303030303"}
+		}
+	}`
+	if err := json.Unmarshal([]byte(requestJSON), &request); err != nil {
+		t.Fatalf("failed to unmarshal request: %v", err)
+	}
+	if request.Attestation.Slot != 6220000 || request.Attestation.Target.Root[0] != 0x03 {
+		t.Fatalf("unexpected attestation: %+v", request.Attestation)
+	}
+
+	object := NewAttestationSigningObject(request.Attestation, request.ForkInfo)
+	if object.ForkVersion != (Version{0x03}) {
+		t.Errorf("attestation must be signed with the current fork version, got %x", object.ForkVersion)
+	}
+	objectBz, err := json.Marshal(object)
+	if err != nil {
+		t.Fatalf("failed to marshal signing object: %v", err)
+	}
+	if !strings.Contains(string(objectBz), `"fork_version":"0x03000000"`) {
+		t.Errorf("unexpected JSON of the signing object: %s", objectBz)
+	}
+	parsed, err := ParseSigningObject(objectBz)
+	if err != nil {
+		t.Fatalf("failed to parse signing object: %v", err)
+	}
+	root, err := object.SigningRoot()
+	if err != nil {
+		t.Fatalf("failed to compute signing root: %v", err)
+	}
+	parsedRoot, err := parsed.SigningRoot()
+	if err != nil {
+		t.Fatalf("failed to compute signing root: %v", err)
+	}
+	if root != parsedRoot {
+		t.Error("signing root changed after JSON round trip")
+	}
+	expected := SigningRoot(request.Attestation.HashTreeRoot(), ComputeDomain(DomainBeaconAttester,
+		object.ForkVersion, object.GenesisValidatorsRoot))
+	if root != expected {
+		t.Error("unexpected signing root of the attestation")
+	}
+
+	block := NewBlockSigningObject(BeaconBlockHeader{Slot: 194047 * SlotsPerEpoch}, request.ForkInfo)
+	if block.ForkVersion != (Version{0x02}) {
+		t.Errorf("block before the fork must be signed with the previous fork version, got %x", block.ForkVersion)
+	}
+	blockRoot, err := block.SigningRoot()
+	if err != nil {
+		t.Fatalf("failed to compute signing root: %v", err)
+	}
+	block.ForkVersion = Version{0x03}
+	if otherRoot, _ := block.SigningRoot(); otherRoot == blockRoot {
+		t.Error("signing root must depend on the fork version")
+	}
+
+	invalid := []string{
+		`{"type":"BLOCK","attestation":{}}`,
+		`{"type":"ATTESTATION","attestation":{"source":{"epoch":"2"},"target":{"epoch":"1"}}}`,
+		`{"type":"EXIT"}`,
+		`{"type":"BLOCK","block":{},"unknown":1}`,
+	}
+	for _, objectJSON := range invalid {
+		if _, err := ParseSigningObject([]byte(objectJSON)); err == nil {
+			t.Errorf("signing object %s must be invalid", objectJSON)
+		}
+	}
+}
+
+func TestSigningObject_NonSlashable(t *testing.T) {
+	forkVersion, gvr := [4]byte{0x03}, [32]byte{0x01}
+	deposit := DepositMessage{PubKey: make([]byte, PubKeyLength),
+		WithdrawalCredentials: make([]byte, WithdrawalCredentialsLength), Amount: MinDepositAmount}
+	exit := VoluntaryExit{Epoch: 1, ValidatorIndex: 42}
+	change := BLSToExecutionChange{ValidatorIndex: 42, FromBLSPubKey: make([]byte, PubKeyLength),
+		ToExecutionAddress: make([]byte, ExecutionAddressLength)}
+
+	for _, tc := range []struct {
+		object *SigningObject
+		root   [32]byte
+	}{
+		{NewDepositSigningObject(deposit, forkVersion), deposit.SigningRoot(forkVersion)},
+		{NewVoluntaryExitSigningObject(exit, forkVersion, gvr), exit.SigningRoot(forkVersion, gvr)},
+		{NewBLSToExecutionChangeSigningObject(change, forkVersion, gvr), change.SigningRoot(forkVersion, gvr)},
+	} {
+		objectBz, err := json.Marshal(tc.object)
+		if err != nil {
+			t.Fatalf("failed to marshal signing object: %v", err)
+		}
+		parsed, err := ParseSigningObject(objectBz)
+		if err != nil {
+			t.Fatalf("failed to parse signing object %s: %v", objectBz, err)
+		}
+		root, err := parsed.SigningRoot()
+		if err != nil {
+			t.Fatalf("failed to compute signing root: %v", err)
+		}
+		if root != tc.root {
+			t.Errorf("unexpected signing root of the %s signing object", parsed.Type)
+		}
+		if parsed.Slashable() {
+			t.Errorf("%s signing object must not be slashable", parsed.Type)
+		}
+	}
+
+	invalid := []string{
+		`{"type":"DEPOSIT","voluntary_exit":{}}`,
+		`{"type":"DEPOSIT","deposit":{"pubkey":"0x01","withdrawal_credentials":"0x01","amount":"1"}}`,
+		`{"type":"VOLUNTARY_EXIT","voluntary_exit":{},"block":{}}`,
+		`{"type":"BLS_TO_EXECUTION_CHANGE","bls_to_execution_change":{"from_bls_pubkey":"0x01"}}`,
+	}
+	for _, objectJSON := range invalid {
+		if _, err := ParseSigningObject([]byte(objectJSON)); err == nil {
+			t.Errorf("signing object %s must be invalid", objectJSON)
+		}
+	}
+	depositObject := NewDepositSigningObject(deposit, forkVersion)
+	depositObject.GenesisValidatorsRoot = gvr
+	if _, err := depositObject.SigningRoot(); err == nil {
+		t.Error("deposit signing object must have the empty genesis validators root")
+	}
+}
+
+func TestReadInterchange(t *testing.T) {
+	interchangeJSON := `{
+		"metadata": {
+			"interchange_format_version": "5",
+			"genesis_validators_root": "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+		},
+		"data": [{
+			"pubkey": "0xB845089A1457F811BFC000588FBB4E713669BE8CE060EA6BE3C6ECE09AFC3794106C91CA73ACDA5E5457122D58723BED",
+			"signed_blocks": [{"slot": "81952", "signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"}, {"slot": "81951"}],
+			"signed_attestations": [{"source_epoch": "2290", "target_epoch": "3007"}]
+		}]
+	}`
+	interchange, err := ReadInterchange(strings.NewReader(interchangeJSON))
+	if err != nil {
+		t.Fatalf("failed to read interchange: %v", err)
+	}
+	data := interchange.Data[0]
+	if data.PubKey != strings.ToLower(data.PubKey) {
+		t.Errorf("public key must be normalized, got %s", data.PubKey)
+	}
+	if data.SignedBlocks[0].Slot != 81952 || data.SignedBlocks[0].SigningRoot == nil || data.SignedBlocks[1].SigningRoot != nil {
+		t.Errorf("unexpected signed blocks: %+v", data.SignedBlocks)
+	}
+	if data.SignedAttestations[0].TargetEpoch != 3007 {
+		t.Errorf("unexpected signed attestations: %+v", data.SignedAttestations)
+	}
+
+	if _, err = ReadInterchange(strings.NewReader(strings.Replace(interchangeJSON, `"5"`, `"4"`, 1))); err == nil {
+		t.Error("interchange of an unsupported version must be refused")
+	}
+}
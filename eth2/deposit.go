@@ -0,0 +1,159 @@
+package eth2
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	WithdrawalCredentialsLength = 32
+
+	// MinDepositAmount is the minimal amount of a deposit in Gwei
+	MinDepositAmount uint64 = 1000000000
+	// MaxEffectiveBalance is the amount of a full validator deposit in Gwei
+	MaxEffectiveBalance uint64 = 32000000000
+
+	// DepositCLIVersion is the version of the official deposit CLI whose deposit_data.json format is produced,
+	// the launchpad rejects files of older versions
+	DepositCLIVersion = "1.2.0"
+)
+
+var (
+	// networks are the names of the networks with known genesis fork versions
+	networks = map[[4]byte]string{
+		{0x00, 0x00, 0x00, 0x00}: "mainnet",
+		{0x00, 0x00, 0x20, 0x09}: "pyrmont",
+		{0x00, 0x00, 0x10, 0x20}: "prater",
+	}
+)
+
+// DepositMessage is a deposit of the validator with the public key PubKey, Amount is in Gwei
+type DepositMessage struct {
+	PubKey                Bytes  `json:"pubkey"`
+	WithdrawalCredentials Bytes  `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount,string"`
+}
+
+// Validate checks the lengths of the keys and the amount of the deposit
+func (m DepositMessage) Validate() error {
+	if len(m.PubKey) != PubKeyLength {
+		return fmt.Errorf("public key must be %d bytes long, got %d", PubKeyLength, len(m.PubKey))
+	}
+	if len(m.WithdrawalCredentials) != WithdrawalCredentialsLength {
+		return fmt.Errorf("withdrawal credentials must be %d bytes long, got %d", WithdrawalCredentialsLength,
+			len(m.WithdrawalCredentials))
+	}
+	if m.Amount < MinDepositAmount {
+		return fmt.Errorf("amount must be at least %d Gwei, got %d", MinDepositAmount, m.Amount)
+	}
+	return nil
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the message, which is the deposit_message_root
+func (m DepositMessage) HashTreeRoot() [32]byte {
+	return merkleize(bytesRoot(m.PubKey), chunk(m.WithdrawalCredentials), uint64Root(m.Amount))
+}
+
+// DepositData is a signed deposit message
+type DepositData struct {
+	DepositMessage
+	Signature []byte
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the deposit data, which is the deposit_data_root
+// checked by the deposit contract
+func (d DepositData) HashTreeRoot() [32]byte {
+	return merkleize(bytesRoot(d.PubKey), chunk(d.WithdrawalCredentials), uint64Root(d.Amount),
+		bytesRoot(d.Signature))
+}
+
+// ComputeDepositDomain returns the signature domain of deposits for the genesis fork version of a network.
+// Deposits are valid across forks, so the genesis validators root is always empty
+func ComputeDepositDomain(forkVersion [4]byte) [32]byte {
+	return ComputeDomain(DomainDeposit, forkVersion, [32]byte{})
+}
+
+// SigningRoot returns the root of the deposit message in the domain, which is signed with the validator key
+func (m DepositMessage) SigningRoot(forkVersion [4]byte) [32]byte {
+	return SigningRoot(m.HashTreeRoot(), ComputeDepositDomain(forkVersion))
+}
+
+// Verify checks the signature of the deposit against its public key
+func (d DepositData) Verify(forkVersion [4]byte) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	return VerifySignature(d.PubKey, d.SigningRoot(forkVersion), d.Signature)
+}
+
+// DepositDataJSON is an entry of the deposit_data.json file made by the official deposit CLI,
+// all bytes are hex encoded without a prefix
+type DepositDataJSON struct {
+	PubKey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	Signature             string `json:"signature"`
+	DepositMessageRoot    string `json:"deposit_message_root"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+	ForkVersion           string `json:"fork_version"`
+	NetworkName           string `json:"eth2_network_name,omitempty"`
+	DepositCLIVersion     string `json:"deposit_cli_version"`
+}
+
+// NewDepositDataJSON verifies the signature of the deposit and returns its deposit_data.json entry
+func NewDepositDataJSON(data DepositData, forkVersion [4]byte) (*DepositDataJSON, error) {
+	if err := data.Verify(forkVersion); err != nil {
+		return nil, err
+	}
+
+	messageRoot := data.DepositMessage.HashTreeRoot()
+	dataRoot := data.HashTreeRoot()
+	return &DepositDataJSON{
+		PubKey:                hex.EncodeToString(data.PubKey),
+		WithdrawalCredentials: hex.EncodeToString(data.WithdrawalCredentials),
+		Amount:                data.Amount,
+		Signature:             hex.EncodeToString(data.Signature),
+		DepositMessageRoot:    hex.EncodeToString(messageRoot[:]),
+		DepositDataRoot:       hex.EncodeToString(dataRoot[:]),
+		ForkVersion:           hex.EncodeToString(forkVersion[:]),
+		NetworkName:           networks[forkVersion],
+		DepositCLIVersion:     DepositCLIVersion,
+	}, nil
+}
+
+// Verify decodes the entry, checks its signature and that the roots are computed from its fields
+func (j DepositDataJSON) Verify() error {
+	var (
+		data        DepositData
+		forkVersion [4]byte
+		err         error
+	)
+	if data.PubKey, err = decodeHex(j.PubKey); err != nil {
+		return fmt.Errorf("failed to decode pubkey: %w", err)
+	}
+	if data.WithdrawalCredentials, err = decodeHex(j.WithdrawalCredentials); err != nil {
+		return fmt.Errorf("failed to decode withdrawal credentials: %w", err)
+	}
+	if data.Signature, err = decodeHex(j.Signature); err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+	if forkVersion, err = ParseForkVersion(j.ForkVersion); err != nil {
+		return err
+	}
+	data.Amount = j.Amount
+
+	if err = data.Verify(forkVersion); err != nil {
+		return err
+	}
+	messageRoot := data.DepositMessage.HashTreeRoot()
+	if !strings.EqualFold(j.DepositMessageRoot, hex.EncodeToString(messageRoot[:])) {
+		return errors.New("deposit_message_root does not match the deposit message")
+	}
+	dataRoot := data.HashTreeRoot()
+	if !strings.EqualFold(j.DepositDataRoot, hex.EncodeToString(dataRoot[:])) {
+		return errors.New("deposit_data_root does not match the deposit data")
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package eth2
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/sign/bls"
+)
+
+func TestComputeDepositDomain(t *testing.T) {
+	// the deposit domain of the mainnet
+	expected := "03000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9"
+	domain := ComputeDepositDomain([4]byte{})
+	if hex.EncodeToString(domain[:]) != expected {
+		t.Errorf("expected domain %s, got %x", expected, domain)
+	}
+}
+
+func TestMerkleize(t *testing.T) {
+	chunks := [][32]byte{{1}, {2}, {3}, {4}, {5}}
+	// five chunks are padded with three zero chunks
+	expected := hashPair(
+		hashPair(hashPair(chunks[0], chunks[1]), hashPair(chunks[2], chunks[3])),
+		hashPair(hashPair(chunks[4], [32]byte{}), hashPair([32]byte{}, [32]byte{})),
+	)
+	if merkleize(chunks...) != expected {
+		t.Error("unexpected root of five chunks")
+	}
+}
+
+func TestNewDepositDataJSON(t *testing.T) {
+	suite := bls12381.NewBLS12381Suite(nil).(pairing.Suite)
+	private, public := bls.NewKeyPair(suite, suite.RandomStream())
+	pubKey, err := public.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+
+	forkVersion, err := ParseForkVersion("0x00001020")
+	if err != nil {
+		t.Fatalf("failed to parse fork version: %v", err)
+	}
+	message := DepositMessage{
+		PubKey:                pubKey,
+		WithdrawalCredentials: make([]byte, WithdrawalCredentialsLength),
+		Amount:                MaxEffectiveBalance,
+	}
+	if err = message.Validate(); err != nil {
+		t.Fatalf("invalid deposit message: %v", err)
+	}
+	signingRoot := message.SigningRoot(forkVersion)
+	signature, err := bls.Sign(suite, private, signingRoot[:])
+	if err != nil {
+		t.Fatalf("failed to sign deposit: %v", err)
+	}
+
+	entry, err := NewDepositDataJSON(DepositData{DepositMessage: message, Signature: signature}, forkVersion)
+	if err != nil {
+		t.Fatalf("failed to make deposit data: %v", err)
+	}
+	if entry.NetworkName != "prater" || entry.ForkVersion != "00001020" {
+		t.Errorf("unexpected network %s with fork version %s", entry.NetworkName, entry.ForkVersion)
+	}
+	if err = entry.Verify(); err != nil {
+		t.Errorf("failed to verify deposit data: %v", err)
+	}
+
+	tampered := *entry
+	tampered.Amount = MinDepositAmount
+	if err = tampered.Verify(); err == nil {
+		t.Error("deposit data with another amount is verified")
+	}
+	tampered = *entry
+	tampered.DepositDataRoot = tampered.DepositMessageRoot
+	if err = tampered.Verify(); err == nil {
+		t.Error("deposit data with a wrong root is verified")
+	}
+
+	// the deposit signed for another network
+	otherForkVersion := [4]byte{}
+	if _, err = NewDepositDataJSON(DepositData{DepositMessage: message, Signature: signature},
+		otherForkVersion); err == nil {
+		t.Error("deposit signed for another network is verified")
+	}
+}
+
+func TestNewDepositDataJSON_DepositCLIVector(t *testing.T) {
+	// a Medalla deposit_data.json entry produced by the official deposit CLI, the testnet is not known,
+	// so the entry has no network name
+	const (
+		pubKey                = "a611f309b4a24853e0b04bd70e35fbac887e099b9f81c2fac2bb2cde9f6f58bd37d947be552ec515b1f45d406f61de27"
+		withdrawalCredentials = "003561705197f621bfaa59add59ee066e6f2fe356201d00c610ed5d6cd7fcb83"
+		signature             = "b0a27f2e7684fc1aa6403e2e76dcbcf29568ba02e9076e61b4c926bccec25ec636a1fdc8d08457cf23a1715ea9ee4fe20b030820e2fcf6dee07a3ce5e6ec65a824027f4cb01c143db74b34f5ca54f7e011d84fe89ce55b0e75f39003e2c9afe9"
+		depositMessageRoot    = "12c267fdc80fb07b47770f8fcf5e25ed2280df391d7de224cc6486e925b7d7f9"
+		depositDataRoot       = "3b3c62bcff04d0249209c79a76cea98520932609986c11cb4ff62a4f54b76548"
+	)
+	data := DepositData{
+		DepositMessage: DepositMessage{
+			PubKey:                mustDecodeHex(t, pubKey),
+			WithdrawalCredentials: mustDecodeHex(t, withdrawalCredentials),
+			Amount:                MaxEffectiveBalance,
+		},
+		Signature: mustDecodeHex(t, signature),
+	}
+
+	entry, err := NewDepositDataJSON(data, [4]byte{0x00, 0x00, 0x00, 0x01})
+	if err != nil {
+		t.Fatalf("failed to make deposit data: %v", err)
+	}
+	if entry.DepositMessageRoot != depositMessageRoot {
+		t.Errorf("expected deposit_message_root %s, got %s", depositMessageRoot, entry.DepositMessageRoot)
+	}
+	if entry.DepositDataRoot != depositDataRoot {
+		t.Errorf("expected deposit_data_root %s, got %s", depositDataRoot, entry.DepositDataRoot)
+	}
+
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("failed to marshal deposit data: %v", err)
+	}
+	expected := `{"pubkey":"` + pubKey + `","withdrawal_credentials":"` + withdrawalCredentials +
+		`","amount":32000000000,"signature":"` + signature + `","deposit_message_root":"` + depositMessageRoot +
+		`","deposit_data_root":"` + depositDataRoot + `","fork_version":"00000001",` +
+		`"deposit_cli_version":"` + DepositCLIVersion + `"}`
+	if string(entryJSON) != expected {
+		t.Errorf("unexpected deposit data JSON:\n%s\nexpected:\n%s", entryJSON, expected)
+	}
+
+	// the CLI writes a list of entries
+	var entries []DepositDataJSON
+	if err = json.Unmarshal([]byte("["+expected+"]"), &entries); err != nil {
+		t.Fatalf("failed to unmarshal deposit data: %v", err)
+	}
+	if len(entries) != 1 || entries[0] != *entry {
+		t.Errorf("unexpected unmarshalled deposit data %+v", entries)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", s, err)
+	}
+	return bz
+}
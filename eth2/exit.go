@@ -0,0 +1,117 @@
+package eth2
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// VoluntaryExit is a request of the validator with the index to exit at the epoch
+type VoluntaryExit struct {
+	Epoch          uint64 `json:"epoch,string"`
+	ValidatorIndex uint64 `json:"validator_index,string"`
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the exit
+func (e VoluntaryExit) HashTreeRoot() [32]byte {
+	return merkleize(uint64Root(e.Epoch), uint64Root(e.ValidatorIndex))
+}
+
+// SigningRoot returns the root of the exit which is signed with the validator key. Since Deneb exits are signed
+// with the Capella fork version, so they stay valid forever
+func (e VoluntaryExit) SigningRoot(forkVersion [4]byte, genesisValidatorsRoot [32]byte) [32]byte {
+	return SigningRoot(e.HashTreeRoot(), ComputeDomain(DomainVoluntaryExit, forkVersion, genesisValidatorsRoot))
+}
+
+// BLSToExecutionChange is a change of the BLS withdrawal credentials of the validator with the index
+// to the execution address
+type BLSToExecutionChange struct {
+	ValidatorIndex     uint64 `json:"validator_index,string"`
+	FromBLSPubKey      Bytes  `json:"from_bls_pubkey"`
+	ToExecutionAddress Bytes  `json:"to_execution_address"`
+}
+
+// Validate checks the lengths of the key and the address
+func (c BLSToExecutionChange) Validate() error {
+	if len(c.FromBLSPubKey) != PubKeyLength {
+		return fmt.Errorf("public key must be %d bytes long, got %d", PubKeyLength, len(c.FromBLSPubKey))
+	}
+	if len(c.ToExecutionAddress) != ExecutionAddressLength {
+		return fmt.Errorf("execution address must be %d bytes long, got %d", ExecutionAddressLength,
+			len(c.ToExecutionAddress))
+	}
+	return nil
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the change
+func (c BLSToExecutionChange) HashTreeRoot() [32]byte {
+	return merkleize(uint64Root(c.ValidatorIndex), bytesRoot(c.FromBLSPubKey), chunk(c.ToExecutionAddress))
+}
+
+// SigningRoot returns the root of the change which is signed with the withdrawal key, the domain is computed
+// with the genesis fork version of the network, so the change is valid across forks
+func (c BLSToExecutionChange) SigningRoot(genesisForkVersion [4]byte, genesisValidatorsRoot [32]byte) [32]byte {
+	return SigningRoot(c.HashTreeRoot(), ComputeDomain(DomainBLSToExecutionChange, genesisForkVersion,
+		genesisValidatorsRoot))
+}
+
+// VoluntaryExitJSON is a voluntary exit in the format of the beacon node API
+type VoluntaryExitJSON struct {
+	Epoch          string `json:"epoch"`
+	ValidatorIndex string `json:"validator_index"`
+}
+
+// SignedVoluntaryExitJSON is the body of the voluntary exit submission to the beacon node API
+type SignedVoluntaryExitJSON struct {
+	Message   VoluntaryExitJSON `json:"message"`
+	Signature string            `json:"signature"`
+}
+
+// NewSignedVoluntaryExitJSON verifies the signature of the exit against the validator key
+// and returns the exit in the format of the beacon node API
+func NewSignedVoluntaryExitJSON(exit VoluntaryExit, pubKey []byte, signature []byte, forkVersion [4]byte,
+	genesisValidatorsRoot [32]byte) (*SignedVoluntaryExitJSON, error) {
+	if err := VerifySignature(pubKey, exit.SigningRoot(forkVersion, genesisValidatorsRoot), signature); err != nil {
+		return nil, err
+	}
+	return &SignedVoluntaryExitJSON{
+		Message: VoluntaryExitJSON{
+			Epoch:          strconv.FormatUint(exit.Epoch, 10),
+			ValidatorIndex: strconv.FormatUint(exit.ValidatorIndex, 10),
+		},
+		Signature: encodeHex(signature),
+	}, nil
+}
+
+// BLSToExecutionChangeJSON is a change of withdrawal credentials in the format of the beacon node API
+type BLSToExecutionChangeJSON struct {
+	ValidatorIndex     string `json:"validator_index"`
+	FromBLSPubKey      string `json:"from_bls_pubkey"`
+	ToExecutionAddress string `json:"to_execution_address"`
+}
+
+// SignedBLSToExecutionChangeJSON is a signed change of withdrawal credentials in the format of the beacon node API
+type SignedBLSToExecutionChangeJSON struct {
+	Message   BLSToExecutionChangeJSON `json:"message"`
+	Signature string                   `json:"signature"`
+}
+
+// NewSignedBLSToExecutionChangeJSON verifies the signature of the change against its withdrawal key
+// and returns the change in the format of the beacon node API
+func NewSignedBLSToExecutionChangeJSON(change BLSToExecutionChange, signature []byte, genesisForkVersion [4]byte,
+	genesisValidatorsRoot [32]byte) (*SignedBLSToExecutionChangeJSON, error) {
+	if err := change.Validate(); err != nil {
+		return nil, err
+	}
+	signingRoot := change.SigningRoot(genesisForkVersion, genesisValidatorsRoot)
+	if err := VerifySignature(change.FromBLSPubKey, signingRoot, signature); err != nil {
+		return nil, err
+	}
+	return &SignedBLSToExecutionChangeJSON{
+		Message: BLSToExecutionChangeJSON{
+			ValidatorIndex:     strconv.FormatUint(change.ValidatorIndex, 10),
+			FromBLSPubKey:      encodeHex(change.FromBLSPubKey),
+			ToExecutionAddress: encodeHex(change.ToExecutionAddress),
+		},
+		Signature: encodeHex(signature),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package eth2
+
+import (
+	"testing"
+
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/sign/bls"
+)
+
+func TestNewSignedVoluntaryExitJSON(t *testing.T) {
+	suite := bls12381.NewBLS12381Suite(nil).(pairing.Suite)
+	private, public := bls.NewKeyPair(suite, suite.RandomStream())
+	pubKey, err := public.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	genesisValidatorsRoot, err := ParseRoot("0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95")
+	if err != nil {
+		t.Fatalf("failed to parse root: %v", err)
+	}
+	capellaForkVersion := [4]byte{0x03, 0x00, 0x00, 0x00}
+
+	exit := VoluntaryExit{Epoch: 194048, ValidatorIndex: 42}
+	if exit.HashTreeRoot() != hashPair(uint64Root(exit.Epoch), uint64Root(exit.ValidatorIndex)) {
+		t.Error("unexpected root of the exit")
+	}
+	signingRoot := exit.SigningRoot(capellaForkVersion, genesisValidatorsRoot)
+	signature, err := bls.Sign(suite, private, signingRoot[:])
+	if err != nil {
+		t.Fatalf("failed to sign exit: %v", err)
+	}
+
+	signed, err := NewSignedVoluntaryExitJSON(exit, pubKey, signature, capellaForkVersion, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatalf("failed to make signed exit: %v", err)
+	}
+	if signed.Message.Epoch != "194048" || signed.Message.ValidatorIndex != "42" || signed.Signature[:2] != "0x" {
+		t.Errorf("unexpected signed exit %+v", signed)
+	}
+
+	if _, err = NewSignedVoluntaryExitJSON(exit, pubKey, signature, capellaForkVersion, [32]byte{}); err == nil {
+		t.Error("exit signed for another network is verified")
+	}
+	exit.ValidatorIndex++
+	if _, err = NewSignedVoluntaryExitJSON(exit, pubKey, signature, capellaForkVersion, genesisValidatorsRoot); err == nil {
+		t.Error("exit of another validator is verified")
+	}
+}
+
+func TestNewSignedBLSToExecutionChangeJSON(t *testing.T) {
+	suite := bls12381.NewBLS12381Suite(nil).(pairing.Suite)
+	private, public := bls.NewKeyPair(suite, suite.RandomStream())
+	pubKey, err := public.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+
+	change := BLSToExecutionChange{
+		ValidatorIndex:     42,
+		FromBLSPubKey:      pubKey,
+		ToExecutionAddress: make([]byte, ExecutionAddressLength),
+	}
+	signingRoot := change.SigningRoot([4]byte{}, [32]byte{1})
+	signature, err := bls.Sign(suite, private, signingRoot[:])
+	if err != nil {
+		t.Fatalf("failed to sign change: %v", err)
+	}
+
+	signed, err := NewSignedBLSToExecutionChangeJSON(change, signature, [4]byte{}, [32]byte{1})
+	if err != nil {
+		t.Fatalf("failed to make signed change: %v", err)
+	}
+	if signed.Message.ToExecutionAddress != "0x0000000000000000000000000000000000000000" {
+		t.Errorf("unexpected execution address %s", signed.Message.ToExecutionAddress)
+	}
+
+	// the exit and the change of the same validator are signed in different domains
+	exitRoot := VoluntaryExit{ValidatorIndex: 42}.SigningRoot([4]byte{}, [32]byte{1})
+	exitSignature, err := bls.Sign(suite, private, exitRoot[:])
+	if err != nil {
+		t.Fatalf("failed to sign exit: %v", err)
+	}
+	if _, err = NewSignedBLSToExecutionChangeJSON(change, exitSignature, [4]byte{}, [32]byte{1}); err == nil {
+		t.Error("signature of another domain is verified")
+	}
+
+	change.ToExecutionAddress = change.ToExecutionAddress[1:]
+	if _, err = NewSignedBLSToExecutionChangeJSON(change, signature, [4]byte{}, [32]byte{1}); err == nil {
+		t.Error("change with a short execution address is verified")
+	}
+}
@@ -0,0 +1,71 @@
+package eth2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// InterchangeFormatVersion is the version of the EIP-3076 slashing protection interchange format
+const InterchangeFormatVersion = "5"
+
+// Interchange is the slashing protection history of validator keys in the EIP-3076 interchange format,
+// it's used to move keys between signers without signing slashable messages
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []InterchangeData   `json:"data"`
+}
+
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    Root   `json:"genesis_validators_root"`
+}
+
+// InterchangeData is the history of a validator key
+type InterchangeData struct {
+	PubKey             string              `json:"pubkey"`
+	SignedBlocks       []SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []SignedAttestation `json:"signed_attestations"`
+}
+
+// SignedBlock is a block signed with the key, the signing root is optional
+type SignedBlock struct {
+	Slot        uint64 `json:"slot,string"`
+	SigningRoot *Root  `json:"signing_root,omitempty"`
+}
+
+// SignedAttestation is an attestation signed with the key, the signing root is optional
+type SignedAttestation struct {
+	SourceEpoch uint64 `json:"source_epoch,string"`
+	TargetEpoch uint64 `json:"target_epoch,string"`
+	SigningRoot *Root  `json:"signing_root,omitempty"`
+}
+
+// ReadInterchange decodes the interchange and checks its version and public keys. Public keys are normalized
+// to the lower case hex with the 0x prefix
+func ReadInterchange(r io.Reader) (*Interchange, error) {
+	var interchange Interchange
+	if err := json.NewDecoder(r).Decode(&interchange); err != nil {
+		return nil, fmt.Errorf("failed to decode interchange: %w", err)
+	}
+	if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return nil, fmt.Errorf("unsupported interchange format version %q, expected %q",
+			interchange.Metadata.InterchangeFormatVersion, InterchangeFormatVersion)
+	}
+	for i, data := range interchange.Data {
+		pubKey, err := decodeHex(data.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode public key %s: %w", data.PubKey, err)
+		}
+		if len(pubKey) != PubKeyLength {
+			return nil, fmt.Errorf("public key %s must be %d bytes long", data.PubKey, PubKeyLength)
+		}
+		interchange.Data[i].PubKey = EncodePubKey(pubKey)
+	}
+	return &interchange, nil
+}
+
+// EncodePubKey encodes the public key like in the interchange
+func EncodePubKey(pubKey []byte) string {
+	return encodeHex(pubKey)
+}
@@ -0,0 +1,100 @@
+// Package eth2 builds Ethereum 2.0 beacon chain objects which are signed with the master keys of DKG rounds
+package eth2
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/sign/bls"
+)
+
+const (
+	PubKeyLength           = 48
+	SignatureLength        = 96
+	ExecutionAddressLength = 20
+)
+
+var (
+	// DomainDeposit is the type of the signature domain of deposits
+	DomainDeposit = [4]byte{0x03, 0x00, 0x00, 0x00}
+	// DomainVoluntaryExit is the type of the signature domain of voluntary exits
+	DomainVoluntaryExit = [4]byte{0x04, 0x00, 0x00, 0x00}
+	// DomainBLSToExecutionChange is the type of the signature domain of withdrawal credentials changes
+	DomainBLSToExecutionChange = [4]byte{0x0a, 0x00, 0x00, 0x00}
+)
+
+// ComputeDomain returns the signature domain of the type for the fork of the network with the genesis validators root
+func ComputeDomain(domainType [4]byte, forkVersion [4]byte, genesisValidatorsRoot [32]byte) [32]byte {
+	forkDataRoot := merkleize(chunk(forkVersion[:]), genesisValidatorsRoot)
+
+	var domain [32]byte
+	copy(domain[:], domainType[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return domain
+}
+
+// SigningRoot returns the root of the object in the domain, which is signed instead of the object itself
+func SigningRoot(objectRoot [32]byte, domain [32]byte) [32]byte {
+	return merkleize(objectRoot, domain)
+}
+
+// VerifySignature checks the BLS signature of the signing root against the public key
+func VerifySignature(pubKeyBz []byte, signingRoot [32]byte, signature []byte) error {
+	if len(pubKeyBz) != PubKeyLength {
+		return fmt.Errorf("public key must be %d bytes long, got %d", PubKeyLength, len(pubKeyBz))
+	}
+	if len(signature) != SignatureLength {
+		return fmt.Errorf("signature must be %d bytes long, got %d", SignatureLength, len(signature))
+	}
+
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+	pubKey := blsSuite.Point()
+	if err := pubKey.UnmarshalBinary(pubKeyBz); err != nil {
+		return fmt.Errorf("failed to unmarshal public key: %w", err)
+	}
+	if err := bls.Verify(blsSuite.(pairing.Suite), pubKey, signingRoot[:], signature); err != nil {
+		return fmt.Errorf("signature is invalid: %w", err)
+	}
+	return nil
+}
+
+// ParseForkVersion decodes a hex encoded fork version with an optional 0x prefix
+func ParseForkVersion(s string) ([4]byte, error) {
+	var forkVersion [4]byte
+	forkVersionBz, err := decodeHex(s)
+	if err != nil {
+		return forkVersion, fmt.Errorf("failed to decode fork version: %w", err)
+	}
+	if len(forkVersionBz) != len(forkVersion) {
+		return forkVersion, fmt.Errorf("fork version must be %d bytes long, got %d", len(forkVersion),
+			len(forkVersionBz))
+	}
+	copy(forkVersion[:], forkVersionBz)
+	return forkVersion, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// ParseRoot decodes a hex encoded root with an optional 0x prefix
+func ParseRoot(s string) ([32]byte, error) {
+	var root [32]byte
+	rootBz, err := decodeHex(s)
+	if err != nil {
+		return root, fmt.Errorf("failed to decode root: %w", err)
+	}
+	if len(rootBz) != len(root) {
+		return root, fmt.Errorf("root must be %d bytes long, got %d", len(root), len(rootBz))
+	}
+	copy(root[:], rootBz)
+	return root, nil
+}
+
+// encodeHex encodes the bytes with the 0x prefix used by the beacon node API
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
@@ -0,0 +1,53 @@
+package eth2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// chunk pads the bytes to an SSZ chunk, the bytes must not be longer than 32
+func chunk(b []byte) [32]byte {
+	var c [32]byte
+	copy(c[:], b)
+	return c
+}
+
+func uint64Root(v uint64) [32]byte {
+	var c [32]byte
+	binary.LittleEndian.PutUint64(c[:], v)
+	return c
+}
+
+// bytesRoot returns the hash tree root of a fixed size byte vector
+func bytesRoot(b []byte) [32]byte {
+	var chunks [][32]byte
+	for len(b) > 32 {
+		chunks = append(chunks, chunk(b[:32]))
+		b = b[32:]
+	}
+	chunks = append(chunks, chunk(b))
+	return merkleize(chunks...)
+}
+
+// merkleize returns the root of the binary Merkle tree of the chunks padded with zero chunks to a power of two
+func merkleize(chunks ...[32]byte) [32]byte {
+	// zero is the root of a subtree of zero chunks at the current level
+	var zero [32]byte
+	for len(chunks) > 1 {
+		if len(chunks)%2 != 0 {
+			chunks = append(chunks, zero)
+		}
+		next := make([][32]byte, 0, len(chunks)/2)
+		for i := 0; i < len(chunks); i += 2 {
+			next = append(next, hashPair(chunks[i], chunks[i+1]))
+		}
+		chunks = next
+		zero = hashPair(zero, zero)
+	}
+	return chunks[0]
+}
+
+func hashPair(left, right [32]byte) [32]byte {
+	return sha256.Sum256(bytes.Join([][]byte{left[:], right[:]}, nil))
+}
@@ -7,4 +7,8 @@ const (
 	SignatureProposalConfirmationDeadline = time.Hour * 24 * 7
 	DkgConfirmationDeadline               = time.Hour * 24 * 7
 	SigningConfirmationDeadline           = time.Hour * 24 * 7
+	// Signing proposals above the limit are queued until one of active signings is finished or expired.
+	// Every participant must queue the same proposals while replaying the log, so the limit is a part of
+	// the protocol like the deadlines above and can't be a setting of a node
+	SigningActiveSessionsLimit = 3
 )
@@ -47,6 +47,23 @@ func (e Event) IsEmpty() bool {
 
 type EventRunMode uint8
 
+// ErrEventNotAvailable is returned by Do if the event can't be executed in the current state
+var ErrEventNotAvailable = errors.New("event is not available in the current state")
+
+// eventNotAvailableError keeps the event and the state of the failed Do call
+type eventNotAvailableError struct {
+	event Event
+	state State
+}
+
+func (e *eventNotAvailableError) Error() string {
+	return fmt.Sprintf("cannot execute event \"%s\" for state \"%s\"", e.event, e.state)
+}
+
+func (e *eventNotAvailableError) Unwrap() error {
+	return ErrEventNotAvailable
+}
+
 // Response returns result for processing with clientMocks events
 type Response struct {
 	// Returns machine execution result state
@@ -286,7 +303,8 @@ func (f *FSM) MustCopyWithState(state State) *FSM {
 				exists = true
 			}
 		}
-		if !exists {
+		// final states have no transitions, but a machine can be restored in one of them
+		if !exists && !f.finStates[state] {
 			panic(fmt.Sprintf("cannot set state, not exists  \"%s\" for \"%s\"", state, f.name))
 		}
 		f.currentState = state
@@ -297,7 +315,7 @@ func (f *FSM) MustCopyWithState(state State) *FSM {
 func (f *FSM) Do(event Event, args ...interface{}) (resp *Response, err error) {
 	trEvent, ok := f.transitions[trKey{f.currentState, event}]
 	if !ok {
-		return nil, fmt.Errorf("cannot execute event \"%s\" for state \"%s\"", event, f.currentState)
+		return nil, &eventNotAvailableError{event: event, state: f.currentState}
 	}
 	if trEvent.isInternal {
 		return nil, errors.New("event is internal")
@@ -492,6 +510,14 @@ func (f *FSM) execCallback(event Event, args ...interface{}) (Event, interface{}
 	return callback(event, args...)
 }
 
+// FinStatesList returns the states which have no transitions in the machine
+func (f *FSM) FinStatesList() (states []State) {
+	for state := range f.finStates {
+		states = append(states, state)
+	}
+	return
+}
+
 func (f *FSM) IsFinState(state State) bool {
 	_, exists := f.finStates[state]
 	return exists
@@ -1,6 +1,8 @@
 package fsm
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -314,3 +316,29 @@ func TestFSM_CopyWithState(t *testing.T) {
 	}
 
 }
+
+func TestFSM_CopyWithFinalState(t *testing.T) {
+	testingFSM1 := testingFSM.MustCopyWithState(stateCanceled2)
+
+	if testingFSM1.State() != stateCanceled2 {
+		t.Fatal("expect final state")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expect panic for unknown state")
+		}
+	}()
+	testingFSM.MustCopyWithState(State("state_unknown"))
+}
+
+func TestFSM_Do_EventNotAvailable(t *testing.T) {
+	testingFSM1 := testingFSM.MustCopyWithState(stateInit)
+	_, err := testingFSM1.Do(eventProcess)
+	if !errors.Is(err, ErrEventNotAvailable) {
+		t.Errorf("expected ErrEventNotAvailable, got %v", err)
+	}
+	if err.Error() != fmt.Sprintf("cannot execute event \"%s\" for state \"%s\"", eventProcess, stateInit) {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+}
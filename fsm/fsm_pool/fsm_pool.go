@@ -28,6 +28,8 @@ type MachineProvider interface {
 
 	StatesList() []fsm.State
 
+	FinStatesList() []fsm.State
+
 	IsFinState(state fsm.State) bool
 }
 
@@ -125,6 +127,16 @@ func Init(machines ...MachineProvider) *FSMPool {
 		}
 	}
 
+	// Final states which don't lead to another machine, e.g. cancellations, stay with their machine,
+	// so a dump in such state can be restored
+	for _, machine := range machines {
+		for _, state := range machine.FinStatesList() {
+			if _, exists := p.states[state]; !exists {
+				p.states[state] = machine.Name()
+			}
+		}
+	}
+
 	if p.fsmInitialEvent == "" {
 		panic("machines pool entry event not set")
 	}
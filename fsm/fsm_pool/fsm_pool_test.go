@@ -199,6 +199,8 @@ func TestFSMPool_MachineByState(t *testing.T) {
 		fsm1StateInit,
 		fsm1StateStage1,
 		fsm1StateStage2,
+		fsm1StateCanceledByInternal,
+		fsm1StateCanceled2,
 	}
 
 	for _, state := range fsm1States {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/lidofinance/dc4bc/fsm/fsm"
 	"github.com/lidofinance/dc4bc/fsm/fsm_pool"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
 )
 
 type DumpedMachineProvider interface {
@@ -21,6 +22,8 @@ type DumpedMachineStatePayload struct {
 	SignatureProposalPayload *SignatureConfirmation
 	DKGProposalPayload       *DKGConfirmation
 	SigningProposalPayload   *SigningConfirmation
+	SigningProposals         map[string]*SigningSession
+	SigningProposalsQueue    []requests.SigningProposalStartRequest
 	PubKeys                  map[string]ed25519.PublicKey
 	IDs                      map[string]int
 }
@@ -122,6 +125,43 @@ func (p *DumpedMachineStatePayload) SigningQuorumUpdate(id int, participant *Sig
 	}
 }
 
+// Signing sessions
+
+func (p *DumpedMachineStatePayload) SigningSessionGet(signingID string) (session *SigningSession, exists bool) {
+	if p.SigningProposals != nil {
+		session, exists = p.SigningProposals[signingID]
+	}
+	return
+}
+
+func (p *DumpedMachineStatePayload) SigningSessionUpdate(signingID string, session *SigningSession) {
+	if p.SigningProposals == nil {
+		p.SigningProposals = make(map[string]*SigningSession)
+	}
+	p.SigningProposals[signingID] = session
+}
+
+func (p *DumpedMachineStatePayload) SigningQueueExists(signingID string) bool {
+	for _, request := range p.SigningProposalsQueue {
+		if request.SigningID == signingID {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *DumpedMachineStatePayload) SigningQueuePush(request requests.SigningProposalStartRequest) {
+	p.SigningProposalsQueue = append(p.SigningProposalsQueue, request)
+}
+
+func (p *DumpedMachineStatePayload) SigningQueuePop() (request requests.SigningProposalStartRequest, exists bool) {
+	if len(p.SigningProposalsQueue) == 0 {
+		return
+	}
+	request, p.SigningProposalsQueue = p.SigningProposalsQueue[0], p.SigningProposalsQueue[1:]
+	return request, true
+}
+
 func (p *DumpedMachineStatePayload) SetPubKeyUsername(username string, pubKey ed25519.PublicKey) {
 	if p.PubKeys == nil {
 		p.PubKeys = make(map[string]ed25519.PublicKey)
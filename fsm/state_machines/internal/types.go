@@ -5,6 +5,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/lidofinance/dc4bc/fsm/fsm"
 	"github.com/lidofinance/dc4bc/fsm/types/requests"
 )
 
@@ -37,10 +38,13 @@ func (s ConfirmationParticipantStatus) String() string {
 }
 
 type SignatureConfirmation struct {
-	Quorum    SignatureProposalQuorum
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	ExpiresAt time.Time
+	Quorum      SignatureProposalQuorum
+	Initiator   string
+	AbortedBy   string
+	AbortReason string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ExpiresAt   time.Time
 }
 
 type SignatureProposalParticipant struct {
@@ -51,6 +55,7 @@ type SignatureProposalParticipant struct {
 	// For validation user confirmation: sign(InvitationSecret, PubKey) => user
 	InvitationSecret string
 	Status           ConfirmationParticipantStatus
+	DeclineReason    string
 	Threshold        int
 	UpdatedAt        time.Time
 }
@@ -63,6 +68,10 @@ func (sigP SignatureProposalParticipant) GetUsername() string {
 	return sigP.Username
 }
 
+func (sigP SignatureProposalParticipant) GetDeclineReason() string {
+	return sigP.DeclineReason
+}
+
 func (c *SignatureConfirmation) IsExpired() bool {
 	return c.ExpiresAt.Before(c.UpdatedAt)
 }
@@ -201,7 +210,10 @@ type SigningConfirmation struct {
 	Quorum           SigningProposalQuorum
 	RecoveredKey     []byte
 	SrcPayload       []byte
+	SigningObject    []byte
 	EncryptedPayload []byte
+	AbortedBy        string
+	AbortReason      string
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	ExpiresAt        time.Time
@@ -211,6 +223,13 @@ func (c *SigningConfirmation) IsExpired() bool {
 	return c.ExpiresAt.Before(c.UpdatedAt)
 }
 
+// SigningSession is a signing procedure identified by SigningId,
+// sessions of a DKG round change their states independently of each other
+type SigningSession struct {
+	State   fsm.State
+	Payload *SigningConfirmation
+}
+
 type SigningProposalQuorum map[int]*SigningProposalParticipant
 
 func (q SigningProposalQuorum) GetOrderedParticipants() []*SigningProposalParticipant {
@@ -250,6 +269,8 @@ func (s SigningParticipantStatus) String() string {
 		str = "SigningAwaitConfirmation"
 	case SigningConfirmed:
 		str = "SigningConfirmed"
+	case SigningDeclined:
+		str = "SigningDeclined"
 	case SigningAwaitPartialSigns:
 		str = "SigningAwaitPartialSigns"
 	case SigningPartialSignsConfirmed:
@@ -267,6 +288,7 @@ type SigningProposalParticipant struct {
 	Username      string
 	Status        SigningParticipantStatus
 	PartialSign   []byte
+	DeclineReason string
 	Error         *requests.FSMError
 	UpdatedAt     time.Time
 }
@@ -278,3 +300,7 @@ func (signingP SigningProposalParticipant) GetStatus() ParticipantStatus {
 func (signingP SigningProposalParticipant) GetUsername() string {
 	return signingP.Username
 }
+
+func (signingP SigningProposalParticipant) GetDeclineReason() string {
+	return signingP.DeclineReason
+}
@@ -7,15 +7,29 @@ import (
 	"fmt"
 	"github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
 	"strings"
+	"time"
 
 	"github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
 
+	"github.com/lidofinance/dc4bc/fsm/config"
 	"github.com/lidofinance/dc4bc/fsm/fsm"
 	"github.com/lidofinance/dc4bc/fsm/fsm_pool"
 	"github.com/lidofinance/dc4bc/fsm/state_machines/internal"
 	"github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
 )
 
+// signingSessionEvents are applied to a signing session selected by the SigningId of a request,
+// so several signings may be processed concurrently within one DKG round
+var signingSessionEvents = map[fsm.Event]bool{
+	signing_proposal_fsm.EventSigningStart:               true,
+	signing_proposal_fsm.EventConfirmSigningConfirmation: true,
+	signing_proposal_fsm.EventDeclineSigningConfirmation: true,
+	signing_proposal_fsm.EventSigningPartialSignReceived: true,
+	signing_proposal_fsm.EventSigningPartialSignError:    true,
+	signing_proposal_fsm.EventAbortSigning:               true,
+}
+
 // Is machine state scope dump will be locked?
 type FSMDump struct {
 	TransactionId string
@@ -82,6 +96,8 @@ func FromDump(data []byte) (*FSMInstance, error) {
 		return nil, fmt.Errorf("cannot read machine dump: %w", err)
 	}
 
+	i.dump.migrateLegacySigning()
+
 	machine, err := fsmPoolProvider.MachineByState(i.dump.State)
 	if err != nil {
 		return nil, err
@@ -92,6 +108,26 @@ func FromDump(data []byte) (*FSMInstance, error) {
 	return i, err
 }
 
+// migrateLegacySigning converts a dump saved before signings became sessions: the round machine itself was
+// in the state of its only signing then, and it could never start another signing. The signing is moved
+// to a session with its own state and the round returns to the idle state
+func (d *FSMDump) migrateLegacySigning() {
+	if !signing_proposal_fsm.IsSessionState(d.State) {
+		return
+	}
+
+	if payload := d.Payload.SigningProposalPayload; payload != nil && payload.SigningId != "" {
+		if _, exists := d.Payload.SigningSessionGet(payload.SigningId); !exists {
+			d.Payload.SigningSessionUpdate(payload.SigningId, &internal.SigningSession{
+				State:   d.State,
+				Payload: payload,
+			})
+		}
+	}
+	d.Payload.SigningProposalPayload = nil
+	d.State = signing_proposal_fsm.StateSigningIdle
+}
+
 func (i *FSMInstance) GetPubKeyByUsername(username string) (ed25519.PublicKey, error) {
 	if i.dump == nil {
 		return nil, errors.New("dump not initialized")
@@ -100,12 +136,50 @@ func (i *FSMInstance) GetPubKeyByUsername(username string) (ed25519.PublicKey, e
 	return i.dump.Payload.GetPubKeyByUsername(username)
 }
 
-func (i *FSMInstance) SigningQuorumGetParticipant(id int) (*internal.SigningProposalParticipant, error) {
+func (i *FSMInstance) SigningQuorumGetParticipant(signingID string, id int) (*internal.SigningProposalParticipant, error) {
 	if i.dump == nil {
 		return nil, errors.New("dump not initialized")
 	}
 
-	return i.dump.Payload.SigningQuorumGet(id), nil
+	session, ok := i.dump.Payload.SigningSessionGet(signingID)
+	if !ok || session.Payload == nil {
+		return nil, fmt.Errorf("signing with {SigningId} = {\"%s\"} not found", signingID)
+	}
+
+	participant, ok := session.Payload.Quorum[id]
+	if !ok {
+		return nil, errors.New("{ParticipantId} not exist in quorum")
+	}
+
+	return participant, nil
+}
+
+// SigningState returns the state of the signing session with the given ID
+func (i *FSMInstance) SigningState(signingID string) (fsm.State, error) {
+	if i.dump == nil {
+		return "", errors.New("dump not initialized")
+	}
+
+	session, ok := i.dump.Payload.SigningSessionGet(signingID)
+	if !ok {
+		return "", fmt.Errorf("signing with {SigningId} = {\"%s\"} not found", signingID)
+	}
+
+	return session.State, nil
+}
+
+// SigningSrcPayload returns the payload proposed to sign by the signing session with the given ID
+func (i *FSMInstance) SigningSrcPayload(signingID string) ([]byte, error) {
+	if i.dump == nil {
+		return nil, errors.New("dump not initialized")
+	}
+
+	session, ok := i.dump.Payload.SigningSessionGet(signingID)
+	if !ok || session.Payload == nil {
+		return nil, fmt.Errorf("signing with {SigningId} = {\"%s\"} not found", signingID)
+	}
+
+	return session.Payload.SrcPayload, nil
 }
 
 func (i *FSMInstance) GetIDByUsername(username string) (int, error) {
@@ -123,6 +197,10 @@ func (i *FSMInstance) Do(event fsm.Event, args ...interface{}) (result *fsm.Resp
 		return nil, []byte{}, errors.New("machine is not initialized")
 	}
 
+	if signingSessionEvents[event] && i.machine.State() == signing_proposal_fsm.StateSigningIdle {
+		return i.doSigning(event, true, args...)
+	}
+
 	result, err = i.machine.Do(event, args...)
 
 	// On route errors result will be nil
@@ -138,6 +216,214 @@ func (i *FSMInstance) Do(event fsm.Event, args ...interface{}) (result *fsm.Resp
 	return result, dump, err
 }
 
+// doSigning applies the event to the signing session, the round machine stays in the idle state.
+// A new signing proposal is queued if allowed and there is no free slot for it
+func (i *FSMInstance) doSigning(event fsm.Event, queueing bool, args ...interface{}) (result *fsm.Response, dump []byte,
+	err error) {
+	var dumpErr error
+
+	if len(args) != 1 {
+		return nil, []byte{}, errors.New("{arg0} required {SigningRequest}")
+	}
+
+	signingID, err := GetSigningID(args[0])
+	if err != nil {
+		return nil, []byte{}, err
+	}
+
+	session, exists := i.dump.Payload.SigningSessionGet(signingID)
+	if !exists && i.dump.Payload.SigningQueueExists(signingID) {
+		exists = true
+		if event != signing_proposal_fsm.EventSigningStart {
+			return nil, []byte{}, fmt.Errorf("signing with {SigningId} = {\"%s\"} is queued", signingID)
+		}
+	}
+	switch {
+	case exists && event == signing_proposal_fsm.EventSigningStart:
+		return nil, []byte{}, fmt.Errorf("signing with {SigningId} = {\"%s\"} already exists", signingID)
+	case !exists && event != signing_proposal_fsm.EventSigningStart:
+		return nil, []byte{}, fmt.Errorf("signing with {SigningId} = {\"%s\"} not found", signingID)
+	case !exists && queueing && i.signingQueued(args[0]):
+		return i.queueSigning(args[0])
+	case !exists:
+		session = &internal.SigningSession{State: signing_proposal_fsm.StateSigningIdle}
+	case signing_proposal_fsm.IsFinalState(session.State):
+		// final states have no transitions, so the signing machine can't be set up with them
+		return nil, []byte{}, fmt.Errorf("signing with {SigningId} = {\"%s\"} is finished with state {%s}",
+			signingID, session.State)
+	}
+
+	// the signing machine works with a copy of the round payload which points to the session payload
+	sessionPayload := *i.dump.Payload
+	sessionPayload.SigningProposalPayload = session.Payload
+
+	machine := signing_proposal_fsm.New().WithSetup(session.State, &sessionPayload)
+	result, err = machine.Do(event, args...)
+
+	// On route errors result will be nil
+	if result != nil {
+		// on action errors the response state is empty, so the actual state is taken from the machine
+		if err == nil || exists {
+			session.State = machine.State()
+			session.Payload = sessionPayload.SigningProposalPayload
+			i.dump.Payload.SigningSessionUpdate(signingID, session)
+		}
+
+		dump, dumpErr = i.dump.Marshal()
+		if dumpErr != nil {
+			return result, []byte{}, err
+		}
+	}
+
+	return result, dump, err
+}
+
+// queueSigning saves the signing proposal to the queue, it will be started by StartQueuedSigning
+func (i *FSMInstance) queueSigning(arg interface{}) (result *fsm.Response, dump []byte, err error) {
+	request, ok := arg.(requests.SigningProposalStartRequest)
+	if !ok {
+		return nil, []byte{}, errors.New("cannot cast {arg0} to type {SigningProposalStartRequest}")
+	}
+
+	if err = request.Validate(); err != nil {
+		return nil, []byte{}, err
+	}
+
+	if !i.dump.Payload.DKGQuorumExists(request.ParticipantId) {
+		return nil, []byte{}, errors.New("{ParticipantId} not exist in quorum")
+	}
+
+	i.dump.Payload.SigningQueuePush(request)
+
+	dump, err = i.dump.Marshal()
+	if err != nil {
+		return nil, []byte{}, err
+	}
+
+	return &fsm.Response{State: i.machine.State()}, dump, nil
+}
+
+// signingQueued checks if a new signing proposal must wait in the queue: all slots are taken by active signings,
+// or earlier proposals are already waiting for a slot
+func (i *FSMInstance) signingQueued(arg interface{}) bool {
+	request, ok := arg.(requests.SigningProposalStartRequest)
+	if !ok {
+		return false
+	}
+	return len(i.dump.Payload.SigningProposalsQueue) != 0 ||
+		i.activeSigningsCount(request.CreatedAt) >= config.SigningActiveSessionsLimit
+}
+
+// QueuedSigningError is returned for a queued signing proposal which failed to start, the proposal
+// is removed from the queue
+type QueuedSigningError struct {
+	SigningID string
+	Err       error
+}
+
+func (e *QueuedSigningError) Error() string {
+	return fmt.Sprintf("failed to start queued signing %s: %v", e.SigningID, e.Err)
+}
+
+func (e *QueuedSigningError) Unwrap() error {
+	return e.Err
+}
+
+// StartQueuedSigning starts the first queued signing proposal if there are less active signings than the limit.
+// The time must be taken from the request which freed the slot, so every participant starts the signing
+// with the same deadlines. Result is nil if no signing was started, the proposals which failed to start
+// are returned as rejected
+func (i *FSMInstance) StartQueuedSigning(createdAt time.Time) (result *fsm.Response, dump []byte,
+	rejected []QueuedSigningError, err error) {
+	if i.machine == nil {
+		return nil, []byte{}, nil, errors.New("machine is not initialized")
+	}
+
+	if i.machine.State() != signing_proposal_fsm.StateSigningIdle {
+		return nil, []byte{}, nil, nil
+	}
+
+	for i.activeSigningsCount(createdAt) < config.SigningActiveSessionsLimit {
+		request, ok := i.dump.Payload.SigningQueuePop()
+		if !ok {
+			break
+		}
+		// deadlines of the signing are counted from the moment it is started
+		request.CreatedAt = createdAt
+
+		result, dump, err = i.doSigning(signing_proposal_fsm.EventSigningStart, false, request)
+		if err == nil {
+			return result, dump, rejected, nil
+		}
+		rejected = append(rejected, QueuedSigningError{SigningID: request.SigningID, Err: err})
+	}
+
+	dump, err = i.dump.Marshal()
+	if err != nil {
+		return nil, []byte{}, nil, err
+	}
+
+	return nil, dump, rejected, nil
+}
+
+// activeSigningsCount returns the number of signings which are not finished yet. A signing that has passed its
+// deadline is not counted: it only gets a timeout state on the next message, which may never come
+func (i *FSMInstance) activeSigningsCount(now time.Time) int {
+	var count int
+	for _, session := range i.dump.Payload.SigningProposals {
+		if signing_proposal_fsm.IsFinalState(session.State) {
+			continue
+		}
+		if session.Payload != nil && session.Payload.ExpiresAt.Before(now) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// GetRequestCreatedAt returns the time a signing request was created at
+func GetRequestCreatedAt(request interface{}) (time.Time, error) {
+	switch req := request.(type) {
+	case requests.SigningProposalStartRequest:
+		return req.CreatedAt, nil
+	case requests.SigningProposalParticipantRequest:
+		return req.CreatedAt, nil
+	case requests.SigningProposalPartialSignRequest:
+		return req.CreatedAt, nil
+	case requests.SigningProposalConfirmationErrorRequest:
+		return req.CreatedAt, nil
+	case requests.SigningProposalAbortRequest:
+		return req.CreatedAt, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot get {CreatedAt} from request of type {%T}", request)
+}
+
+// GetSigningID returns the ID of a signing session the request relates to
+func GetSigningID(request interface{}) (string, error) {
+	var signingID string
+	switch req := request.(type) {
+	case requests.SigningProposalStartRequest:
+		signingID = req.SigningID
+	case requests.SigningProposalParticipantRequest:
+		signingID = req.SigningId
+	case requests.SigningProposalPartialSignRequest:
+		signingID = req.SigningId
+	case requests.SigningProposalConfirmationErrorRequest:
+		signingID = req.SigningId
+	case requests.SigningProposalAbortRequest:
+		signingID = req.SigningId
+	default:
+		return "", fmt.Errorf("cannot get {SigningId} from request of type {%T}", request)
+	}
+
+	if signingID == "" {
+		return "", errors.New("{SigningId} cannot be empty")
+	}
+
+	return signingID, nil
+}
+
 func (i *FSMInstance) InitDump(dkgID string) error {
 	if i.dump != nil {
 		return errors.New("dump already initialized")
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 
 	sif "github.com/lidofinance/dc4bc/fsm/state_machines/signing_proposal_fsm"
 
+	"github.com/lidofinance/dc4bc/fsm/config"
 	"github.com/lidofinance/dc4bc/fsm/fsm"
 	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
 	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
@@ -177,6 +179,8 @@ func Test_SignatureProposal_EventInitProposal_Positive(t *testing.T) {
 	}
 	testParticipantsListRequest.Participants = request
 	testParticipantsListRequest.SigningThreshold = threshold
+	// participant IDs follow the order of the list, so the initiator gets ID 0
+	testParticipantsListRequest.Initiator = request[0].Username
 
 	fsmResponse, testFSMDump[spf.StateAwaitParticipantsConfirmations], err = testFSMInstance.Do(spf.EventInitProposal, testParticipantsListRequest)
 
@@ -278,6 +282,7 @@ func Test_SignatureProposal_EventConfirmSignatureProposal_Canceled_Participant(t
 
 	fsmResponse, testFSMDumpLocal, err := testFSMInstance.Do(spf.EventDeclineProposal, requests.SignatureProposalParticipantRequest{
 		ParticipantId: 0,
+		Reason:        "cannot participate",
 		CreatedAt:     time.Now(),
 	})
 	require.NoError(t, err)
@@ -289,6 +294,54 @@ func Test_SignatureProposal_EventConfirmSignatureProposal_Canceled_Participant(t
 	compareFSMResponseNotNil(t, fsmResponse)
 
 	compareState(t, spf.StateValidationCanceledByParticipant, fsmResponse.State)
+
+	require.Equal(t, "cannot participate",
+		testFSMInstance.FSMDump().Payload.SignatureProposalPayload.Quorum[0].DeclineReason)
+}
+
+func Test_SignatureProposal_EventAbortProposal(t *testing.T) {
+	testFSMInstance, err := FromDump(testFSMDump[spf.StateAwaitParticipantsConfirmations])
+
+	compareErrNil(t, err)
+
+	compareFSMInstanceNotNil(t, testFSMInstance)
+
+	_, _, err = testFSMInstance.Do(spf.EventAbortProposal, requests.SignatureProposalAbortRequest{
+		ParticipantId: 0,
+		CreatedAt:     time.Now(),
+	})
+
+	if err == nil {
+		t.Fatalf("expected error for empty {Reason}")
+	}
+
+	_, _, err = testFSMInstance.Do(spf.EventAbortProposal, requests.SignatureProposalAbortRequest{
+		ParticipantId: 1,
+		Reason:        "wrong participants list",
+		CreatedAt:     time.Now(),
+	})
+
+	if err == nil {
+		t.Fatalf("expected error for abort by a participant other than the initiator")
+	}
+
+	fsmResponse, testFSMDumpLocal, err := testFSMInstance.Do(spf.EventAbortProposal, requests.SignatureProposalAbortRequest{
+		ParticipantId: 0,
+		Reason:        "wrong participants list",
+		CreatedAt:     time.Now(),
+	})
+
+	compareErrNil(t, err)
+
+	compareDumpNotZero(t, testFSMDumpLocal)
+
+	compareFSMResponseNotNil(t, fsmResponse)
+
+	compareState(t, spf.StateValidationCanceledByParticipant, fsmResponse.State)
+
+	payload := testFSMInstance.FSMDump().Payload.SignatureProposalPayload
+	require.Equal(t, testIdMapParticipants[0].Username, payload.AbortedBy)
+	require.Equal(t, "wrong participants list", payload.AbortReason)
 }
 
 func Test_SignatureProposal_EventConfirmSignatureProposal_Canceled_Timeout(t *testing.T) {
@@ -967,7 +1020,7 @@ func Test_SigningProposal_EventConfirmSigningConfirmation_Positive(t *testing.T)
 
 		compareFSMInstanceNotNil(t, testFSMInstance)
 
-		inState, _ := testFSMInstance.State()
+		inState, _ := testFSMInstance.SigningState(testSigningId)
 		compareState(t, sif.StateSigningAwaitConfirmations, inState)
 
 		fsmResponse, testFSMDumpLocal, err = testFSMInstance.Do(sif.EventConfirmSigningConfirmation, requests.SigningProposalParticipantRequest{
@@ -1027,7 +1080,7 @@ func Test_SigningProposal_EventDeclineProposal_Canceled_Participants(t *testing.
 
 	compareFSMInstanceNotNil(t, testFSMInstance)
 
-	inState, _ := testFSMInstance.State()
+	inState, _ := testFSMInstance.SigningState(testSigningId)
 	compareState(t, sif.StateSigningAwaitConfirmations, inState)
 
 	declinedParticipantsCount := 0
@@ -1046,7 +1099,7 @@ func Test_SigningProposal_EventDeclineProposal_Canceled_Participants(t *testing.
 
 		compareFSMInstanceNotNil(t, testFSMInstance)
 
-		inState, _ := testFSMInstance.State()
+		inState, _ := testFSMInstance.SigningState(testSigningId)
 		compareState(t, sif.StateSigningAwaitConfirmations, inState)
 
 		fsmResponse, testFSMDumpLocal, err = testFSMInstance.Do(sif.EventDeclineSigningConfirmation, requests.SigningProposalParticipantRequest{
@@ -1072,6 +1125,58 @@ func Test_SigningProposal_EventDeclineProposal_Canceled_Participants(t *testing.
 	compareState(t, sif.StateSigningConfirmationsAwaitCancelledByParticipant, fsmResponse.State)
 }
 
+func Test_SigningProposal_EventAbortSigning(t *testing.T) {
+	testFSMInstance, err := FromDump(testFSMDump[sif.StateSigningAwaitConfirmations])
+
+	compareErrNil(t, err)
+
+	compareFSMInstanceNotNil(t, testFSMInstance)
+
+	for participantId := range testIdMapParticipants {
+		if participantId == testSigningInitiator {
+			continue
+		}
+
+		_, _, err = testFSMInstance.Do(sif.EventAbortSigning, requests.SigningProposalAbortRequest{
+			SigningId:     testSigningId,
+			ParticipantId: participantId,
+			Reason:        "wrong data",
+			CreatedAt:     time.Now(),
+		})
+
+		if err == nil {
+			t.Fatalf("expected error for abort by not an initiator")
+		}
+		break
+	}
+
+	fsmResponse, testFSMDumpLocal, err := testFSMInstance.Do(sif.EventAbortSigning, requests.SigningProposalAbortRequest{
+		SigningId:     testSigningId,
+		ParticipantId: testSigningInitiator,
+		Reason:        "wrong data",
+		CreatedAt:     time.Now(),
+	})
+
+	compareErrNil(t, err)
+
+	compareDumpNotZero(t, testFSMDumpLocal)
+
+	compareFSMResponseNotNil(t, fsmResponse)
+
+	compareState(t, sif.StateSigningAbortedByInitiator, fsmResponse.State)
+
+	testFSMInstance, err = FromDump(testFSMDumpLocal)
+
+	compareErrNil(t, err)
+
+	inState, _ := testFSMInstance.SigningState(testSigningId)
+	compareState(t, sif.StateSigningAbortedByInitiator, inState)
+
+	session := testFSMInstance.FSMDump().Payload.SigningProposals[testSigningId]
+	require.Equal(t, testIdMapParticipants[testSigningInitiator].Username, session.Payload.AbortedBy)
+	require.Equal(t, "wrong data", session.Payload.AbortReason)
+}
+
 func Test_SigningProposal_EventConfirmSignatureProposal_Canceled_Timeout(t *testing.T) {
 	testFSMInstance, err := FromDump(testFSMDump[sif.StateSigningAwaitConfirmations])
 
@@ -1079,7 +1184,7 @@ func Test_SigningProposal_EventConfirmSignatureProposal_Canceled_Timeout(t *test
 
 	compareFSMInstanceNotNil(t, testFSMInstance)
 
-	inState, _ := testFSMInstance.State()
+	inState, _ := testFSMInstance.SigningState(testSigningId)
 	compareState(t, sif.StateSigningAwaitConfirmations, inState)
 
 	fsmResponse, testFSMDumpLocal, err := testFSMInstance.Do(sif.EventConfirmSigningConfirmation, requests.SigningProposalParticipantRequest{
@@ -1122,7 +1227,7 @@ func Test_SigningProposal_EventSigningPartialKeyReceived_Positive(t *testing.T)
 
 		compareFSMInstanceNotNil(t, testFSMInstance)
 
-		inState, _ := testFSMInstance.State()
+		inState, _ := testFSMInstance.SigningState(testSigningId)
 		compareState(t, sif.StateSigningAwaitPartialSigns, inState)
 
 		fsmResponse, testFSMDumpLocal, err = testFSMInstance.Do(sif.EventSigningPartialSignReceived, requests.SigningProposalPartialSignRequest{
@@ -1183,7 +1288,7 @@ func Test_SigningProposal_EventPartialKeysReceived_Failed_Participants(t *testin
 
 	compareFSMInstanceNotNil(t, testFSMInstance)
 
-	inState, _ := testFSMInstance.State()
+	inState, _ := testFSMInstance.SigningState(testSigningId)
 	compareState(t, sif.StateSigningAwaitPartialSigns, inState)
 
 	failedParticipantsCount := 0
@@ -1198,10 +1303,11 @@ func Test_SigningProposal_EventPartialKeysReceived_Failed_Participants(t *testin
 
 		compareFSMInstanceNotNil(t, testFSMInstance)
 
-		inState, _ := testFSMInstance.State()
+		inState, _ := testFSMInstance.SigningState(testSigningId)
 		compareState(t, sif.StateSigningAwaitPartialSigns, inState)
 
-		fsmResponse, testFSMDumpLocal, err = testFSMInstance.Do(sif.EventSigningPartialSignError, requests.SignatureProposalConfirmationErrorRequest{
+		fsmResponse, testFSMDumpLocal, err = testFSMInstance.Do(sif.EventSigningPartialSignError, requests.SigningProposalConfirmationErrorRequest{
+			SigningId:     testSigningId,
 			Error:         requests.NewFSMError(errors.New("some error")),
 			ParticipantId: participantId,
 			CreatedAt:     time.Now(),
@@ -1224,32 +1330,318 @@ func Test_SigningProposal_EventPartialKeysReceived_Failed_Participants(t *testin
 	compareState(t, sif.StateSigningPartialSignsAwaitCancelledByError, fsmResponse.State)
 }
 
-func Test_DkgProposal_EventSigningRestart_Positive(t *testing.T) {
-	var (
-		fsmResponse      *fsm.Response
-		testFSMDumpLocal []byte
-	)
+func Test_SigningProposal_ConcurrentSessions(t *testing.T) {
+	testFSMInstance, err := FromDump(testFSMDump[sif.StateSigningAwaitPartialSigns])
+
+	compareErrNil(t, err)
+
+	compareFSMInstanceNotNil(t, testFSMInstance)
+
+	// a session with the same ID cannot be started twice
+	_, _, err = testFSMInstance.Do(sif.EventSigningStart, requests.SigningProposalStartRequest{
+		SigningID:     testSigningId,
+		ParticipantId: testSigningInitiator,
+		SrcPayload:    testSigningPayload,
+		CreatedAt:     time.Now(),
+	})
+
+	if err == nil {
+		t.Fatalf("expected error for duplicated {SigningId}")
+	}
+
+	fsmResponse, testFSMDumpLocal, err := testFSMInstance.Do(sif.EventSigningStart, requests.SigningProposalStartRequest{
+		SigningID:     "test-signing-id-2",
+		ParticipantId: testSigningInitiator,
+		SrcPayload:    []byte("another message to sign"),
+		CreatedAt:     time.Now(),
+	})
+
+	compareErrNil(t, err)
+
+	compareFSMResponseNotNil(t, fsmResponse)
+
+	compareState(t, sif.StateSigningAwaitConfirmations, fsmResponse.State)
+
+	compareDumpNotZero(t, testFSMDumpLocal)
+
+	testFSMInstance, err = FromDump(testFSMDumpLocal)
+
+	compareErrNil(t, err)
+
+	// the round machine stays idle while sessions are processed
+	inState, _ := testFSMInstance.State()
+	compareState(t, sif.StateSigningIdle, inState)
+
+	inState, _ = testFSMInstance.SigningState(testSigningId)
+	compareState(t, sif.StateSigningAwaitPartialSigns, inState)
+
+	inState, _ = testFSMInstance.SigningState("test-signing-id-2")
+	compareState(t, sif.StateSigningAwaitConfirmations, inState)
+
+	_, _, err = testFSMInstance.Do(sif.EventConfirmSigningConfirmation, requests.SigningProposalParticipantRequest{
+		SigningId:     "unknown-signing-id",
+		ParticipantId: 0,
+		CreatedAt:     time.Now(),
+	})
+
+	if err == nil {
+		t.Fatalf("expected error for unknown {SigningId}")
+	}
+}
+
+// legacyDump converts a dump to the format saved before signing sessions: the round machine was in the state
+// of the signing and the signing payload was a part of the round payload
+func legacyDump(t *testing.T, data []byte, signingID string) []byte {
+	dump := &FSMDump{}
+	compareErrNil(t, dump.Unmarshal(data))
+
+	session, ok := dump.Payload.SigningSessionGet(signingID)
+	if !ok {
+		t.Fatalf("expected signing session %s in dump", signingID)
+	}
+	dump.State = session.State
+	dump.Payload.SigningProposalPayload = session.Payload
+	dump.Payload.SigningProposals = nil
 
-	testFSMInstance, err := FromDump(testFSMDump[sif.StateSigningPartialSignsCollected])
+	legacy, err := dump.Marshal()
+	compareErrNil(t, err)
+	return legacy
+}
+
+func Test_SigningProposal_LegacyDumpMigration(t *testing.T) {
+	// a finished signing used to be restarted by EventSigningRestart, now the round is idle right away
+	testFSMInstance, err := FromDump(legacyDump(t, testFSMDump[sif.StateSigningPartialSignsCollected], testSigningId))
 
 	compareErrNil(t, err)
 
 	compareFSMInstanceNotNil(t, testFSMInstance)
 
 	inState, _ := testFSMInstance.State()
+	compareState(t, sif.StateSigningIdle, inState)
+
+	inState, _ = testFSMInstance.SigningState(testSigningId)
 	compareState(t, sif.StateSigningPartialSignsCollected, inState)
 
-	fsmResponse, testFSMDumpLocal, err = testFSMInstance.Do(sif.EventSigningRestart, requests.DefaultRequest{
-		CreatedAt: time.Now(),
+	// late messages of a finished signing are refused
+	_, _, err = testFSMInstance.Do(sif.EventSigningPartialSignReceived, requests.SigningProposalPartialSignRequest{
+		SigningId:     testSigningId,
+		ParticipantId: testSigningInitiator,
+		PartialSign:   testIdMapParticipants[testSigningInitiator].DkgPartialKey,
+		CreatedAt:     time.Now(),
+	})
+
+	if err == nil {
+		t.Fatalf("expected error for finished signing")
+	}
+
+	fsmResponse, _, err := testFSMInstance.Do(sif.EventSigningStart, requests.SigningProposalStartRequest{
+		SigningID:     "test-signing-id-2",
+		ParticipantId: testSigningInitiator,
+		SrcPayload:    testSigningPayload,
+		CreatedAt:     time.Now(),
 	})
 
 	compareErrNil(t, err)
 
 	compareFSMResponseNotNil(t, fsmResponse)
 
-	compareState(t, sif.StateSigningIdle, fsmResponse.State)
+	compareState(t, sif.StateSigningAwaitConfirmations, fsmResponse.State)
+
+	// a signing in progress goes on as a session, and other signings can be started next to it
+	testFSMInstance, err = FromDump(legacyDump(t, testFSMDump[sif.StateSigningAwaitPartialSigns], testSigningId))
+
+	compareErrNil(t, err)
+
+	inState, _ = testFSMInstance.State()
+	compareState(t, sif.StateSigningIdle, inState)
+
+	if testFSMInstance.FSMDump().Payload.SigningProposalPayload != nil {
+		t.Fatalf("expected legacy signing payload to be moved to the session")
+	}
+
+	inState, _ = testFSMInstance.SigningState(testSigningId)
+	compareState(t, sif.StateSigningAwaitPartialSigns, inState)
+
+	fsmResponse, _, err = testFSMInstance.Do(sif.EventSigningPartialSignReceived, requests.SigningProposalPartialSignRequest{
+		SigningId:     testSigningId,
+		ParticipantId: testSigningInitiator,
+		PartialSign:   testIdMapParticipants[testSigningInitiator].DkgPartialKey,
+		CreatedAt:     time.Now(),
+	})
+
+	compareErrNil(t, err)
+
+	compareFSMResponseNotNil(t, fsmResponse)
+
+	compareState(t, sif.StateSigningAwaitPartialSigns, fsmResponse.State)
+
+	fsmResponse, _, err = testFSMInstance.Do(sif.EventSigningStart, requests.SigningProposalStartRequest{
+		SigningID:     "test-signing-id-2",
+		ParticipantId: testSigningInitiator,
+		SrcPayload:    testSigningPayload,
+		CreatedAt:     time.Now(),
+	})
+
+	compareErrNil(t, err)
+
+	compareState(t, sif.StateSigningAwaitConfirmations, fsmResponse.State)
+}
+
+func Test_SigningProposal_Queue(t *testing.T) {
+	testFSMInstance, err := FromDump(testFSMDump[sif.StateSigningIdle])
+
+	compareErrNil(t, err)
+
+	compareFSMInstanceNotNil(t, testFSMInstance)
+
+	for idx := 0; idx <= config.SigningActiveSessionsLimit; idx++ {
+		fsmResponse, _, err := testFSMInstance.Do(sif.EventSigningStart, requests.SigningProposalStartRequest{
+			SigningID:     fmt.Sprintf("queued-signing-id-%d", idx),
+			ParticipantId: testSigningInitiator,
+			SrcPayload:    testSigningPayload,
+			CreatedAt:     time.Now(),
+		})
+
+		compareErrNil(t, err)
+
+		compareFSMResponseNotNil(t, fsmResponse)
+
+		if idx < config.SigningActiveSessionsLimit {
+			compareState(t, sif.StateSigningAwaitConfirmations, fsmResponse.State)
+		} else {
+			compareState(t, sif.StateSigningIdle, fsmResponse.State)
+		}
+	}
+
+	queuedSigningId := fmt.Sprintf("queued-signing-id-%d", config.SigningActiveSessionsLimit)
+	if len(testFSMInstance.FSMDump().Payload.SigningProposalsQueue) != 1 {
+		t.Fatalf("expected one queued signing proposal")
+	}
+
+	if _, err = testFSMInstance.SigningState(queuedSigningId); err == nil {
+		t.Fatalf("expected queued signing not to be started")
+	}
+
+	// all slots are busy
+	fsmResponse, _, _, err := testFSMInstance.StartQueuedSigning(time.Now())
+
+	compareErrNil(t, err)
+
+	if fsmResponse != nil {
+		t.Fatalf("expected nil response while all signing slots are busy")
+	}
+
+	// cancel the first signing to free a slot
+	for participantId := range testIdMapParticipants {
+		if participantId == testSigningInitiator {
+			continue
+		}
+
+		fsmResponse, _, err = testFSMInstance.Do(sif.EventDeclineSigningConfirmation, requests.SigningProposalParticipantRequest{
+			SigningId:     "queued-signing-id-0",
+			ParticipantId: participantId,
+			CreatedAt:     time.Now(),
+		})
+
+		compareErrNil(t, err)
+
+		if fsmResponse.State == sif.StateSigningConfirmationsAwaitCancelledByParticipant {
+			break
+		}
+	}
+
+	compareState(t, sif.StateSigningConfirmationsAwaitCancelledByParticipant, fsmResponse.State)
+
+	fsmResponse, testFSMDumpLocal, rejected, err := testFSMInstance.StartQueuedSigning(time.Now())
+
+	compareErrNil(t, err)
+
+	if len(rejected) != 0 {
+		t.Fatalf("expected no rejected signing proposals")
+	}
+
+	compareErrNil(t, err)
+
+	compareFSMResponseNotNil(t, fsmResponse)
+
+	compareState(t, sif.StateSigningAwaitConfirmations, fsmResponse.State)
 
 	compareDumpNotZero(t, testFSMDumpLocal)
+
+	testFSMInstance, err = FromDump(testFSMDumpLocal)
+
+	compareErrNil(t, err)
+
+	inState, _ := testFSMInstance.SigningState(queuedSigningId)
+	compareState(t, sif.StateSigningAwaitConfirmations, inState)
+
+	if len(testFSMInstance.FSMDump().Payload.SigningProposalsQueue) != 0 {
+		t.Fatalf("expected empty signing proposals queue")
+	}
+}
+
+func Test_SigningProposal_QueueExpiredSessions(t *testing.T) {
+	testFSMInstance, err := FromDump(testFSMDump[sif.StateSigningIdle])
+
+	compareErrNil(t, err)
+
+	compareFSMInstanceNotNil(t, testFSMInstance)
+
+	startedAt := time.Now()
+	for idx := 0; idx <= config.SigningActiveSessionsLimit; idx++ {
+		_, _, err = testFSMInstance.Do(sif.EventSigningStart, requests.SigningProposalStartRequest{
+			SigningID:     fmt.Sprintf("stalled-signing-id-%d", idx),
+			ParticipantId: testSigningInitiator,
+			SrcPayload:    testSigningPayload,
+			CreatedAt:     startedAt,
+		})
+
+		compareErrNil(t, err)
+	}
+
+	// a proposal that fails to start is rejected instead of being dropped silently
+	testFSMInstance.FSMDump().Payload.SigningQueuePush(requests.SigningProposalStartRequest{
+		SigningID:     "invalid-signing-id",
+		ParticipantId: testSigningInitiator,
+		CreatedAt:     startedAt,
+	})
+
+	// stalled signings never get another message, but they don't keep their slots after the deadline
+	expiredAt := startedAt.Add(config.SigningConfirmationDeadline + time.Hour)
+	fsmResponse, _, rejected, err := testFSMInstance.StartQueuedSigning(expiredAt)
+
+	compareErrNil(t, err)
+
+	compareFSMResponseNotNil(t, fsmResponse)
+
+	compareState(t, sif.StateSigningAwaitConfirmations, fsmResponse.State)
+
+	queuedSigningId := fmt.Sprintf("stalled-signing-id-%d", config.SigningActiveSessionsLimit)
+	session, ok := testFSMInstance.FSMDump().Payload.SigningSessionGet(queuedSigningId)
+	if !ok {
+		t.Fatalf("expected queued signing to be started")
+	}
+
+	// deadlines depend on the request which freed the slot only, so every participant gets the same state
+	if !session.Payload.ExpiresAt.Equal(expiredAt.Add(config.SigningConfirmationDeadline)) {
+		t.Fatalf("expected deadline to be counted from the time of the request which freed the slot")
+	}
+
+	fsmResponse, _, rejected, err = testFSMInstance.StartQueuedSigning(expiredAt)
+
+	compareErrNil(t, err)
+
+	if fsmResponse != nil {
+		t.Fatalf("expected no signing to be started")
+	}
+
+	if len(rejected) != 1 || rejected[0].SigningID != "invalid-signing-id" || rejected[0].Err == nil {
+		t.Fatalf("expected invalid signing proposal to be rejected, got %v", rejected)
+	}
+
+	if len(testFSMInstance.FSMDump().Payload.SigningProposalsQueue) != 0 {
+		t.Fatalf("expected empty signing proposals queue")
+	}
 }
 
 func Test_Parallel(t *testing.T) {
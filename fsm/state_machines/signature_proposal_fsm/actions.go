@@ -35,6 +35,7 @@ func (m *SignatureProposalFSM) actionInitSignatureProposal(inEvent fsm.Event, ar
 
 	m.payload.SignatureProposalPayload = &internal.SignatureConfirmation{
 		Quorum:    make(internal.SignatureProposalQuorum),
+		Initiator: request.Initiator,
 		CreatedAt: request.CreatedAt,
 		ExpiresAt: request.CreatedAt.Add(config.SignatureProposalConfirmationDeadline),
 	}
@@ -120,6 +121,7 @@ func (m *SignatureProposalFSM) actionProposalResponseByParticipant(inEvent fsm.E
 		signatureProposalParticipant.Status = internal.SigConfirmationConfirmed
 	case EventDeclineProposal:
 		signatureProposalParticipant.Status = internal.SigConfirmationDeclined
+		signatureProposalParticipant.DeclineReason = request.Reason
 	default:
 		err = fmt.Errorf("unsupported event for action {inEvent} = {\"%s\"}", inEvent)
 		return
@@ -133,6 +135,43 @@ func (m *SignatureProposalFSM) actionProposalResponseByParticipant(inEvent fsm.E
 	return
 }
 
+func (m *SignatureProposalFSM) actionAbortProposal(inEvent fsm.Event, args ...interface{}) (outEvent fsm.Event, response interface{}, err error) {
+	m.payloadMu.Lock()
+	defer m.payloadMu.Unlock()
+
+	if len(args) != 1 {
+		err = errors.New("{arg0} required {SignatureProposalAbortRequest}")
+		return
+	}
+
+	request, ok := args[0].(requests.SignatureProposalAbortRequest)
+
+	if !ok {
+		err = errors.New("cannot cast {arg0} to type {SignatureProposalAbortRequest}")
+		return
+	}
+
+	if err = request.Validate(); err != nil {
+		return
+	}
+
+	if !m.payload.SigQuorumExists(request.ParticipantId) {
+		err = errors.New("{ParticipantId} not exist in quorum")
+		return
+	}
+
+	if m.payload.SigQuorumGet(request.ParticipantId).Username != m.payload.SignatureProposalPayload.Initiator {
+		err = errors.New("only the initiator can abort the DKG proposal")
+		return
+	}
+
+	m.payload.SignatureProposalPayload.AbortedBy = m.payload.SigQuorumGet(request.ParticipantId).Username
+	m.payload.SignatureProposalPayload.AbortReason = request.Reason
+	m.payload.SignatureProposalPayload.UpdatedAt = request.CreatedAt
+
+	return
+}
+
 func (m *SignatureProposalFSM) actionValidateSignatureProposal(fsm.Event, ...interface{}) (outEvent fsm.Event, response interface{}, err error) {
 	var (
 		isContainsDecline bool
@@ -23,6 +23,7 @@ const (
 	EventInitProposal                       = fsm.Event("event_sig_proposal_init")
 	EventConfirmSignatureProposal           = fsm.Event("event_sig_proposal_confirm_by_participant")
 	EventDeclineProposal                    = fsm.Event("event_sig_proposal_decline_by_participant")
+	EventAbortProposal                      = fsm.Event("event_sig_proposal_abort_by_participant")
 	eventAutoValidateProposalInternal       = fsm.Event("event_sig_proposal_validate")
 	eventSetProposalValidatedInternal       = fsm.Event("event_sig_proposal_set_validated")
 	eventSetValidationCanceledByTimeout     = fsm.Event("event_sig_proposal_canceled_timeout")
@@ -58,6 +59,8 @@ func New() internal.DumpedMachineProvider {
 			// Now set for external emitting.
 			{Name: EventDeclineProposal, SrcState: []fsm.State{StateAwaitParticipantsConfirmations}, DstState: StateAwaitParticipantsConfirmations},
 			{Name: eventSetValidationCanceledByParticipant, SrcState: []fsm.State{StateAwaitParticipantsConfirmations}, DstState: StateValidationCanceledByParticipant, IsInternal: true},
+			// Only the initiator can abort the proposal, other participants decline it instead
+			{Name: EventAbortProposal, SrcState: []fsm.State{StateAwaitParticipantsConfirmations}, DstState: StateValidationCanceledByParticipant},
 
 			{Name: eventAutoValidateProposalInternal, SrcState: []fsm.State{StateAwaitParticipantsConfirmations}, DstState: StateAwaitParticipantsConfirmations, IsInternal: true, IsAuto: true},
 
@@ -74,6 +77,7 @@ func New() internal.DumpedMachineProvider {
 			EventInitProposal:                 machine.actionInitSignatureProposal,
 			EventConfirmSignatureProposal:     machine.actionProposalResponseByParticipant,
 			EventDeclineProposal:              machine.actionProposalResponseByParticipant,
+			EventAbortProposal:                machine.actionAbortProposal,
 			eventAutoValidateProposalInternal: machine.actionValidateSignatureProposal,
 		},
 	)
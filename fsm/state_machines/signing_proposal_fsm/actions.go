@@ -31,11 +31,8 @@ func (m *SigningProposalFSM) actionInitSigningProposal(inEvent fsm.Event, args .
 		return
 	}
 
-	m.payload.SigningProposalPayload = &internal.SigningConfirmation{
-		Quorum:    make(internal.SigningProposalQuorum),
-		CreatedAt: request.CreatedAt,
-		ExpiresAt: request.CreatedAt.Add(config.SigningConfirmationDeadline),
-	}
+	// signings are processed in separate sessions, see SigningSession
+	m.payload.SigningProposals = make(map[string]*internal.SigningSession)
 
 	return
 }
@@ -60,12 +57,22 @@ func (m *SigningProposalFSM) actionStartSigningProposal(inEvent fsm.Event, args
 		return
 	}
 
-	m.payload.SigningProposalPayload.SigningId = request.SigningID
-
-	m.payload.SigningProposalPayload.InitiatorId = request.ParticipantId
-	m.payload.SigningProposalPayload.SrcPayload = request.SrcPayload
+	if !m.payload.DKGQuorumExists(request.ParticipantId) {
+		err = errors.New("{ParticipantId} not exist in quorum")
+		return
+	}
 
-	m.payload.SigningProposalPayload.Quorum = make(internal.SigningProposalQuorum)
+	// every signing has its own quorum and deadline
+	m.payload.SigningProposalPayload = &internal.SigningConfirmation{
+		SigningId:     request.SigningID,
+		InitiatorId:   request.ParticipantId,
+		SrcPayload:    request.SrcPayload,
+		SigningObject: request.SigningObject,
+		Quorum:        make(internal.SigningProposalQuorum),
+		CreatedAt:     request.CreatedAt,
+		UpdatedAt:     request.CreatedAt,
+		ExpiresAt:     request.CreatedAt.Add(config.SigningConfirmationDeadline),
+	}
 
 	// Initialize new quorum
 	for _, dkgEntry := range m.payload.DKGProposalPayload.Quorum.GetOrderedParticipants() {
@@ -77,14 +84,14 @@ func (m *SigningProposalFSM) actionStartSigningProposal(inEvent fsm.Event, args
 	}
 
 	m.payload.SigningProposalPayload.Quorum[request.ParticipantId].Status = internal.SigningConfirmed
-	m.payload.SigningProposalPayload.CreatedAt = request.CreatedAt
 
 	// Make response
 	responseData := responses.SigningProposalParticipantInvitationsResponse{
-		SigningId:    m.payload.SigningProposalPayload.SigningId,
-		InitiatorId:  m.payload.SigningProposalPayload.InitiatorId,
-		SrcPayload:   m.payload.SigningProposalPayload.SrcPayload,
-		Participants: make([]*responses.SigningProposalParticipantInvitationEntry, 0),
+		SigningId:     m.payload.SigningProposalPayload.SigningId,
+		InitiatorId:   m.payload.SigningProposalPayload.InitiatorId,
+		SrcPayload:    m.payload.SigningProposalPayload.SrcPayload,
+		SigningObject: m.payload.SigningProposalPayload.SigningObject,
+		Participants:  make([]*responses.SigningProposalParticipantInvitationEntry, 0),
 	}
 
 	for _, participant := range m.payload.SigningProposalPayload.Quorum.GetOrderedParticipants() {
@@ -136,6 +143,7 @@ func (m *SigningProposalFSM) actionProposalResponseByParticipant(inEvent fsm.Eve
 		signingProposalParticipant.Status = internal.SigningConfirmed
 	case EventDeclineSigningConfirmation:
 		signingProposalParticipant.Status = internal.SigningDeclined
+		signingProposalParticipant.DeclineReason = request.Reason
 	default:
 		err = fmt.Errorf("unsupported event for action {inEvent} = {\"%s\"}", inEvent)
 		return
@@ -186,9 +194,10 @@ func (m *SigningProposalFSM) actionValidateSigningProposalConfirmations(inEvent
 
 	// Make response
 	responseData := responses.SigningPartialSignsParticipantInvitationsResponse{
-		SigningId:   m.payload.SigningProposalPayload.SigningId,
-		InitiatorId: m.payload.SigningProposalPayload.InitiatorId,
-		SrcPayload:  m.payload.SigningProposalPayload.SrcPayload,
+		SigningId:     m.payload.SigningProposalPayload.SigningId,
+		InitiatorId:   m.payload.SigningProposalPayload.InitiatorId,
+		SrcPayload:    m.payload.SigningProposalPayload.SrcPayload,
+		SigningObject: m.payload.SigningProposalPayload.SigningObject,
 	}
 
 	response = responseData
@@ -233,7 +242,7 @@ func (m *SigningProposalFSM) actionPartialSignConfirmationReceived(inEvent fsm.E
 	signingProposalParticipant.Status = internal.SigningPartialSignsConfirmed
 
 	signingProposalParticipant.UpdatedAt = request.CreatedAt
-	m.payload.SignatureProposalPayload.UpdatedAt = request.CreatedAt
+	m.payload.SigningProposalPayload.UpdatedAt = request.CreatedAt
 
 	m.payload.SigningQuorumUpdate(request.ParticipantId, signingProposalParticipant)
 
@@ -278,6 +287,7 @@ func (m *SigningProposalFSM) actionValidateSigningPartialSignsAwaitConfirmations
 	// Response
 	responseData := responses.SigningProcessParticipantResponse{
 		SigningId:    m.payload.SigningProposalPayload.SigningId,
+		InitiatorId:  m.payload.SigningProposalPayload.InitiatorId,
 		SrcPayload:   m.payload.SigningProposalPayload.SrcPayload,
 		Participants: make([]*responses.SigningProcessParticipantEntry, 0),
 	}
@@ -300,7 +310,34 @@ func (m *SigningProposalFSM) actionValidateSigningPartialSignsAwaitConfirmations
 	return
 }
 
-func (m *SigningProposalFSM) actionSigningRestart(inEvent fsm.Event, args ...interface{}) (outEvent fsm.Event, response interface{}, err error) {
+func (m *SigningProposalFSM) actionAbortSigning(inEvent fsm.Event, args ...interface{}) (outEvent fsm.Event, response interface{}, err error) {
+	m.payloadMu.Lock()
+	defer m.payloadMu.Unlock()
+
+	if len(args) != 1 {
+		err = errors.New("{arg0} required {SigningProposalAbortRequest}")
+		return
+	}
+
+	request, ok := args[0].(requests.SigningProposalAbortRequest)
+
+	if !ok {
+		err = errors.New("cannot cast {arg0} to type {SigningProposalAbortRequest}")
+		return
+	}
+
+	if err = request.Validate(); err != nil {
+		return
+	}
+
+	if request.ParticipantId != m.payload.SigningProposalPayload.InitiatorId {
+		err = errors.New("only the initiator can abort the signing")
+		return
+	}
+
+	m.payload.SigningProposalPayload.AbortedBy = m.payload.SigningQuorumGet(request.ParticipantId).Username
+	m.payload.SigningProposalPayload.AbortReason = request.Reason
+	m.payload.SigningProposalPayload.UpdatedAt = request.CreatedAt
 
 	return
 }
@@ -311,14 +348,14 @@ func (m *SigningProposalFSM) actionConfirmationError(inEvent fsm.Event, args ...
 	defer m.payloadMu.Unlock()
 
 	if len(args) != 1 {
-		err = errors.New("{arg0} required {SignatureProposalConfirmationErrorRequest}")
+		err = errors.New("{arg0} required {SigningProposalConfirmationErrorRequest}")
 		return
 	}
 
-	request, ok := args[0].(requests.SignatureProposalConfirmationErrorRequest)
+	request, ok := args[0].(requests.SigningProposalConfirmationErrorRequest)
 
 	if !ok {
-		err = errors.New("cannot cast {arg0} to type {SignatureProposalConfirmationErrorRequest}")
+		err = errors.New("cannot cast {arg0} to type {SigningProposalConfirmationErrorRequest}")
 		return
 	}
 
@@ -361,7 +398,7 @@ func (m *SigningProposalFSM) actionConfirmationError(inEvent fsm.Event, args ...
 	signingProposalParticipant.Error = request.Error
 
 	signingProposalParticipant.UpdatedAt = request.CreatedAt
-	m.payload.SignatureProposalPayload.UpdatedAt = request.CreatedAt
+	m.payload.SigningProposalPayload.UpdatedAt = request.CreatedAt
 
 	m.payload.SigningQuorumUpdate(request.ParticipantId, signingProposalParticipant)
 	return
@@ -3,6 +3,8 @@ package signing_proposal_fsm
 import (
 	"crypto/rand"
 	"encoding/base64"
+
+	"github.com/lidofinance/dc4bc/fsm/fsm"
 )
 
 const (
@@ -18,3 +20,22 @@ func generateSigningId() (string, error) {
 
 	return base64.URLEncoding.EncodeToString(b), err
 }
+
+// IsSessionState returns true if the state belongs to a signing session rather than to the DKG round
+func IsSessionState(state fsm.State) bool {
+	return state == StateSigningAwaitConfirmations || state == StateSigningAwaitPartialSigns || IsFinalState(state)
+}
+
+// IsFinalState returns true if a signing cannot change its state anymore
+func IsFinalState(state fsm.State) bool {
+	switch state {
+	case StateSigningPartialSignsCollected,
+		StateSigningConfirmationsAwaitCancelledByTimeout,
+		StateSigningConfirmationsAwaitCancelledByParticipant,
+		StateSigningPartialSignsAwaitCancelledByTimeout,
+		StateSigningPartialSignsAwaitCancelledByError,
+		StateSigningAbortedByInitiator:
+		return true
+	}
+	return false
+}
@@ -28,6 +28,8 @@ const (
 
 	StateSigningPartialSignsCollected = fsm.State("state_signing_partial_signs_collected")
 
+	StateSigningAbortedByInitiator = fsm.State("state_signing_aborted_by_initiator")
+
 	// Events
 
 	EventSigningInit                                    = fsm.Event("event_signing_init")
@@ -48,7 +50,8 @@ const (
 	eventAutoSigningValidatePartialSignInternal = fsm.Event("event_signing_partial_signs_await_validate")
 
 	eventSigningPartialSignsConfirmedInternal = fsm.Event("event_signing_partial_signs_confirmed_internal")
-	EventSigningRestart                       = fsm.Event("event_signing_restart")
+
+	EventAbortSigning = fsm.Event("event_signing_abort_by_initiator")
 )
 
 type SigningProposalFSM struct {
@@ -94,7 +97,8 @@ func New() internal.DumpedMachineProvider {
 
 			{Name: eventSigningPartialSignsConfirmedInternal, SrcState: []fsm.State{StateSigningAwaitPartialSigns}, DstState: StateSigningPartialSignsCollected, IsInternal: true},
 
-			{Name: EventSigningRestart, SrcState: []fsm.State{StateSigningPartialSignsCollected, StateSigningPartialSignsAwaitCancelledByError, StateSigningPartialSignsAwaitCancelledByTimeout, StateSigningConfirmationsAwaitCancelledByTimeout}, DstState: StateSigningIdle},
+			// Aborted
+			{Name: EventAbortSigning, SrcState: []fsm.State{StateSigningAwaitConfirmations, StateSigningAwaitPartialSigns}, DstState: StateSigningAbortedByInitiator},
 		},
 		fsm.Callbacks{
 			EventSigningInit:                            machine.actionInitSigningProposal,
@@ -105,7 +109,7 @@ func New() internal.DumpedMachineProvider {
 			EventSigningPartialSignReceived:             machine.actionPartialSignConfirmationReceived,
 			eventAutoSigningValidatePartialSignInternal: machine.actionValidateSigningPartialSignsAwaitConfirmations,
 			EventSigningPartialSignError:                machine.actionConfirmationError,
-			EventSigningRestart:                         machine.actionSigningRestart,
+			EventAbortSigning:                           machine.actionAbortSigning,
 		},
 	)
 
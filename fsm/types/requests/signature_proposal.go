@@ -10,6 +10,8 @@ type SignatureProposalParticipantsListRequest struct {
 	Participants     []*SignatureProposalParticipantsEntry
 	SigningThreshold int
 	CreatedAt        time.Time
+	// Initiator is the username of the sender of the proposal, only the initiator can abort it
+	Initiator string
 }
 
 type SignatureProposalParticipantsEntry struct {
@@ -23,6 +25,16 @@ type SignatureProposalParticipantsEntry struct {
 // 		   "event_sig_proposal_decline_by_participant"
 type SignatureProposalParticipantRequest struct {
 	ParticipantId int
+	// Reason is an optional explanation of a decline
+	Reason    string
+	CreatedAt time.Time
+}
+
+// States: "state_sig_proposal_await_participants_confirmations"
+// Events: "event_sig_proposal_abort_by_participant"
+type SignatureProposalAbortRequest struct {
+	ParticipantId int
+	Reason        string
 	CreatedAt     time.Time
 }
 
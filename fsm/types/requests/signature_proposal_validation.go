@@ -79,3 +79,19 @@ func (r *SignatureProposalConfirmationErrorRequest) Validate() error {
 
 	return nil
 }
+
+func (r *SignatureProposalAbortRequest) Validate() error {
+	if r.ParticipantId < 0 {
+		return errors.New("{ParticipantId} cannot be a negative number")
+	}
+
+	if r.Reason == "" {
+		return errors.New("{Reason} cannot be empty")
+	}
+
+	if r.CreatedAt.IsZero() {
+		return errors.New("{CreatedAt} is not set")
+	}
+
+	return nil
+}
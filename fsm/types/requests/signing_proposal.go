@@ -8,6 +8,9 @@ type SigningProposalStartRequest struct {
 	SigningID     string
 	ParticipantId int
 	SrcPayload    []byte
+	// SigningObject is an optional typed object which SrcPayload is the signing root of, e.g. an Eth2 block,
+	// signers check the root against it before signing
+	SigningObject []byte
 	CreatedAt     time.Time
 }
 
@@ -17,6 +20,18 @@ type SigningProposalStartRequest struct {
 type SigningProposalParticipantRequest struct {
 	SigningId     string
 	ParticipantId int
+	// Reason is an optional explanation of a decline
+	Reason    string
+	CreatedAt time.Time
+}
+
+// States: "state_signing_await_confirmations"
+//		   "state_signing_await_partial_signs"
+// Events: "event_signing_abort_by_initiator"
+type SigningProposalAbortRequest struct {
+	SigningId     string
+	ParticipantId int
+	Reason        string
 	CreatedAt     time.Time
 }
 
@@ -28,3 +43,12 @@ type SigningProposalPartialSignRequest struct {
 	PartialSign   []byte
 	CreatedAt     time.Time
 }
+
+// States: "state_signing_await_partial_keys"
+// Events: "event_signing_partial_sign_error_received"
+type SigningProposalConfirmationErrorRequest struct {
+	SigningId     string
+	ParticipantId int
+	Error         *FSMError
+	CreatedAt     time.Time
+}
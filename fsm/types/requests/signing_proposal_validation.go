@@ -3,6 +3,10 @@ package requests
 import "errors"
 
 func (r *SigningProposalStartRequest) Validate() error {
+	if r.SigningID == "" {
+		return errors.New("{SigningID} cannot be empty")
+	}
+
 	if r.ParticipantId < 0 {
 		return errors.New("{ParticipantId} cannot be a negative number")
 	}
@@ -53,3 +57,43 @@ func (r *SigningProposalPartialSignRequest) Validate() error {
 
 	return nil
 }
+
+func (r *SigningProposalConfirmationErrorRequest) Validate() error {
+	if r.SigningId == "" {
+		return errors.New("{SigningId} cannot be empty")
+	}
+
+	if r.ParticipantId < 0 {
+		return errors.New("{ParticipantId} cannot be a negative number")
+	}
+
+	if r.Error == nil {
+		return errors.New("{Error} cannot be a nil")
+	}
+
+	if r.CreatedAt.IsZero() {
+		return errors.New("{CreatedAt} is not set")
+	}
+
+	return nil
+}
+
+func (r *SigningProposalAbortRequest) Validate() error {
+	if r.SigningId == "" {
+		return errors.New("{SigningId} cannot be empty")
+	}
+
+	if r.ParticipantId < 0 {
+		return errors.New("{ParticipantId} cannot be a negative number")
+	}
+
+	if r.Reason == "" {
+		return errors.New("{Reason} cannot be empty")
+	}
+
+	if r.CreatedAt.IsZero() {
+		return errors.New("{CreatedAt} is not set")
+	}
+
+	return nil
+}
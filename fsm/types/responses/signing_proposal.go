@@ -7,7 +7,8 @@ type SigningProposalParticipantInvitationsResponse struct {
 	InitiatorId  int
 	Participants []*SigningProposalParticipantInvitationEntry
 	// Source message for signing
-	SrcPayload []byte
+	SrcPayload    []byte
+	SigningObject []byte
 }
 
 type SigningProposalParticipantInvitationEntry struct {
@@ -19,9 +20,10 @@ type SigningProposalParticipantInvitationEntry struct {
 // Event:  "event_signing_proposal_confirm_by_participant"
 // States: "state_signing_await_partial_keys"
 type SigningPartialSignsParticipantInvitationsResponse struct {
-	SigningId   string
-	InitiatorId int
-	SrcPayload  []byte
+	SigningId     string
+	InitiatorId   int
+	SrcPayload    []byte
+	SigningObject []byte
 }
 
 // Event:  ""
@@ -41,6 +43,7 @@ type SigningProposalParticipantStatusEntry struct {
 // States: "state_signing_partial_signatures_collected"
 type SigningProcessParticipantResponse struct {
 	SigningId    string
+	InitiatorId  int
 	SrcPayload   []byte
 	Participants []*SigningProcessParticipantEntry
 }
@@ -6,8 +6,10 @@ package clientMocks
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	client "github.com/lidofinance/dc4bc/client"
 	types "github.com/lidofinance/dc4bc/client/types"
 	state_machines "github.com/lidofinance/dc4bc/fsm/state_machines"
+	storage "github.com/lidofinance/dc4bc/storage"
 	reflect "reflect"
 )
 
@@ -209,3 +211,292 @@ func (mr *MockStateMockRecorder) GetSignatures(dkgID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSignatures", reflect.TypeOf((*MockState)(nil).GetSignatures), dkgID)
 }
+
+// SavePubPolyCommitments mocks base method
+func (m *MockState) SavePubPolyCommitments(dkgRoundID string, commitments [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePubPolyCommitments", dkgRoundID, commitments)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePubPolyCommitments indicates an expected call of SavePubPolyCommitments
+func (mr *MockStateMockRecorder) SavePubPolyCommitments(dkgRoundID, commitments interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePubPolyCommitments", reflect.TypeOf((*MockState)(nil).SavePubPolyCommitments), dkgRoundID, commitments)
+}
+
+// LoadPubPolyCommitments mocks base method
+func (m *MockState) LoadPubPolyCommitments(dkgRoundID string) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadPubPolyCommitments", dkgRoundID)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadPubPolyCommitments indicates an expected call of LoadPubPolyCommitments
+func (mr *MockStateMockRecorder) LoadPubPolyCommitments(dkgRoundID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPubPolyCommitments", reflect.TypeOf((*MockState)(nil).LoadPubPolyCommitments), dkgRoundID)
+}
+
+// PutWebhookDelivery mocks base method
+func (m *MockState) PutWebhookDelivery(delivery types.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutWebhookDelivery indicates an expected call of PutWebhookDelivery
+func (mr *MockStateMockRecorder) PutWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutWebhookDelivery", reflect.TypeOf((*MockState)(nil).PutWebhookDelivery), delivery)
+}
+
+// DeleteWebhookDelivery mocks base method
+func (m *MockState) DeleteWebhookDelivery(deliveryID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookDelivery", deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookDelivery indicates an expected call of DeleteWebhookDelivery
+func (mr *MockStateMockRecorder) DeleteWebhookDelivery(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookDelivery", reflect.TypeOf((*MockState)(nil).DeleteWebhookDelivery), deliveryID)
+}
+
+// GetWebhookDeliveries mocks base method
+func (m *MockState) GetWebhookDeliveries() ([]types.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries")
+	ret0, _ := ret[0].([]types.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries
+func (mr *MockStateMockRecorder) GetWebhookDeliveries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockState)(nil).GetWebhookDeliveries))
+}
+
+// PutDeadLetter mocks base method
+func (m *MockState) PutDeadLetter(letter types.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutDeadLetter", letter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutDeadLetter indicates an expected call of PutDeadLetter
+func (mr *MockStateMockRecorder) PutDeadLetter(letter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeadLetter", reflect.TypeOf((*MockState)(nil).PutDeadLetter), letter)
+}
+
+// DeleteDeadLetter mocks base method
+func (m *MockState) DeleteDeadLetter(messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter
+func (mr *MockStateMockRecorder) DeleteDeadLetter(messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockState)(nil).DeleteDeadLetter), messageID)
+}
+
+// GetDeadLetters mocks base method
+func (m *MockState) GetDeadLetters() ([]types.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetters")
+	ret0, _ := ret[0].([]types.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetters indicates an expected call of GetDeadLetters
+func (mr *MockStateMockRecorder) GetDeadLetters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetters", reflect.TypeOf((*MockState)(nil).GetDeadLetters))
+}
+
+// SaveHeadHash mocks base method
+func (m *MockState) SaveHeadHash(offset uint64, hash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveHeadHash", offset, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveHeadHash indicates an expected call of SaveHeadHash
+func (mr *MockStateMockRecorder) SaveHeadHash(offset, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHeadHash", reflect.TypeOf((*MockState)(nil).SaveHeadHash), offset, hash)
+}
+
+// GetHeadHash mocks base method
+func (m *MockState) GetHeadHash(offset uint64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeadHash", offset)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeadHash indicates an expected call of GetHeadHash
+func (mr *MockStateMockRecorder) GetHeadHash(offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeadHash", reflect.TypeOf((*MockState)(nil).GetHeadHash), offset)
+}
+
+// PutLogCheckpoint mocks base method
+func (m *MockState) PutLogCheckpoint(checkpoint types.LogCheckpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutLogCheckpoint", checkpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutLogCheckpoint indicates an expected call of PutLogCheckpoint
+func (mr *MockStateMockRecorder) PutLogCheckpoint(checkpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutLogCheckpoint", reflect.TypeOf((*MockState)(nil).PutLogCheckpoint), checkpoint)
+}
+
+// GetLogCheckpoints mocks base method
+func (m *MockState) GetLogCheckpoints() ([]types.LogCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogCheckpoints")
+	ret0, _ := ret[0].([]types.LogCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogCheckpoints indicates an expected call of GetLogCheckpoints
+func (mr *MockStateMockRecorder) GetLogCheckpoints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogCheckpoints", reflect.TypeOf((*MockState)(nil).GetLogCheckpoints))
+}
+
+// PutStepMessage mocks base method
+func (m *MockState) PutStepMessage(dkgRoundID, username, step string, message storage.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutStepMessage", dkgRoundID, username, step, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutStepMessage indicates an expected call of PutStepMessage
+func (mr *MockStateMockRecorder) PutStepMessage(dkgRoundID, username, step, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutStepMessage", reflect.TypeOf((*MockState)(nil).PutStepMessage), dkgRoundID, username, step, message)
+}
+
+// GetStepMessage mocks base method
+func (m *MockState) GetStepMessage(dkgRoundID, username, step string) (*storage.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStepMessage", dkgRoundID, username, step)
+	ret0, _ := ret[0].(*storage.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStepMessage indicates an expected call of GetStepMessage
+func (mr *MockStateMockRecorder) GetStepMessage(dkgRoundID, username, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStepMessage", reflect.TypeOf((*MockState)(nil).GetStepMessage), dkgRoundID, username, step)
+}
+
+// PutEquivocation mocks base method
+func (m *MockState) PutEquivocation(equivocation types.Equivocation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutEquivocation", equivocation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutEquivocation indicates an expected call of PutEquivocation
+func (mr *MockStateMockRecorder) PutEquivocation(equivocation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutEquivocation", reflect.TypeOf((*MockState)(nil).PutEquivocation), equivocation)
+}
+
+// GetEquivocation mocks base method
+func (m *MockState) GetEquivocation(dkgRoundID, username, step string) (*types.Equivocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEquivocation", dkgRoundID, username, step)
+	ret0, _ := ret[0].(*types.Equivocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEquivocation indicates an expected call of GetEquivocation
+func (mr *MockStateMockRecorder) GetEquivocation(dkgRoundID, username, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEquivocation", reflect.TypeOf((*MockState)(nil).GetEquivocation), dkgRoundID, username, step)
+}
+
+// GetEquivocations mocks base method
+func (m *MockState) GetEquivocations() ([]types.Equivocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEquivocations")
+	ret0, _ := ret[0].([]types.Equivocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEquivocations indicates an expected call of GetEquivocations
+func (mr *MockStateMockRecorder) GetEquivocations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEquivocations", reflect.TypeOf((*MockState)(nil).GetEquivocations))
+}
+
+// PutFSMTransition mocks base method
+func (m *MockState) PutFSMTransition(transition types.FSMTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutFSMTransition", transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutFSMTransition indicates an expected call of PutFSMTransition
+func (mr *MockStateMockRecorder) PutFSMTransition(transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutFSMTransition", reflect.TypeOf((*MockState)(nil).PutFSMTransition), transition)
+}
+
+// GetFSMTransitions mocks base method
+func (m *MockState) GetFSMTransitions(dkgRoundID string) ([]types.FSMTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFSMTransitions", dkgRoundID)
+	ret0, _ := ret[0].([]types.FSMTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFSMTransitions indicates an expected call of GetFSMTransitions
+func (mr *MockStateMockRecorder) GetFSMTransitions(dkgRoundID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFSMTransitions", reflect.TypeOf((*MockState)(nil).GetFSMTransitions), dkgRoundID)
+}
+
+// Atomic mocks base method
+func (m *MockState) Atomic(fn func(client.State) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Atomic", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Atomic indicates an expected call of Atomic
+func (mr *MockStateMockRecorder) Atomic(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Atomic", reflect.TypeOf((*MockState)(nil).Atomic), fn)
+}
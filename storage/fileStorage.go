@@ -98,7 +98,6 @@ func (fs *FileStorage) GetMessages(offset uint64) ([]Message, error) {
 		msgs []Message
 		err  error
 		row  []byte
-		data Message
 	)
 	if _, err = fs.dataFile.Seek(0, 0); err != nil {
 		return nil, fmt.Errorf("failed to seek a offset to the start of a data file: %v", err)
@@ -110,6 +109,8 @@ func (fs *FileStorage) GetMessages(offset uint64) ([]Message, error) {
 			continue
 		}
 
+		// a new message for every row, otherwise messages share the Data and Signature buffers
+		var data Message
 		row = scanner.Bytes()
 		if err = json.Unmarshal(row, &data); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal a message %s: %v", string(row), err)
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// GenesisHeadHash is the head hash of the empty append-only log
+var GenesisHeadHash = make([]byte, sha256.Size)
+
+// Committed returns true if the message commits to a prefix of the append-only log
+func (m *Message) Committed() bool {
+	return len(m.PrevHash) != 0
+}
+
+// Hash returns the SHA-256 hash of all fields of the message, every field is prefixed with its length,
+// so different messages can't have the same encoding
+func (m *Message) Hash() []byte {
+	h := sha256.New()
+	writeField := func(field []byte) {
+		var size [8]byte
+		binary.BigEndian.PutUint64(size[:], uint64(len(field)))
+		h.Write(size[:])
+		h.Write(field)
+	}
+	writeUint := func(value uint64) {
+		var bz [8]byte
+		binary.BigEndian.PutUint64(bz[:], value)
+		writeField(bz[:])
+	}
+
+	writeField([]byte(m.ID))
+	writeField([]byte(m.DkgRoundID))
+	writeUint(m.Offset)
+	writeField([]byte(m.Event))
+	writeField(m.Data)
+	writeField(m.Signature)
+	writeField([]byte(m.SenderAddr))
+	writeField([]byte(m.RecipientAddr))
+	writeUint(m.PrevOffset)
+	writeField(m.PrevHash)
+
+	return h.Sum(nil)
+}
+
+// NextHeadHash returns the head hash of the append-only log after the message is appended to the log
+// with the given head hash
+func NextHeadHash(head []byte, m Message) []byte {
+	h := sha256.New()
+	h.Write(head)
+	h.Write(m.Hash())
+	return h.Sum(nil)
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+)
+
+func TestMessage_Hash(t *testing.T) {
+	msg := Message{
+		ID:         "id",
+		DkgRoundID: "dkg_round_id",
+		Offset:     1,
+		Event:      "event",
+		Data:       randomBytes(10),
+		Signature:  randomBytes(10),
+		SenderAddr: "alice",
+		PrevOffset: 1,
+		PrevHash:   GenesisHeadHash,
+	}
+	hash := msg.Hash()
+
+	moved := msg
+	moved.Offset = 2
+	if bytes.Equal(hash, moved.Hash()) {
+		t.Error("messages with different offsets have the same hash")
+	}
+
+	// the same bytes split between fields differently
+	shifted := msg
+	shifted.ID = "idd"
+	shifted.DkgRoundID = "kg_round_id"
+	if bytes.Equal(hash, shifted.Hash()) {
+		t.Error("messages with different fields have the same hash")
+	}
+
+	head := NextHeadHash(GenesisHeadHash, msg)
+	if bytes.Equal(head, NextHeadHash(GenesisHeadHash, moved)) {
+		t.Error("different messages lead to the same head hash")
+	}
+	if bytes.Equal(head, NextHeadHash(head, msg)) {
+		t.Error("different log prefixes lead to the same head hash")
+	}
+}
+
+func TestMessage_VerifyCommitment(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := Message{
+		Data:       []byte("data"),
+		PrevOffset: 3,
+		PrevHash:   NextHeadHash(GenesisHeadHash, Message{}),
+	}
+	msg.Signature = ed25519.Sign(privKey, msg.Bytes())
+	if !msg.Verify(pubKey) {
+		t.Fatal("failed to verify committed message")
+	}
+
+	rewritten := msg
+	rewritten.PrevOffset = 4
+	if rewritten.Verify(pubKey) {
+		t.Error("commitment can be changed without the sender")
+	}
+
+	stripped := msg
+	stripped.PrevOffset, stripped.PrevHash = 0, nil
+	if stripped.Verify(pubKey) {
+		t.Error("commitment can be removed without the sender")
+	}
+}
@@ -180,7 +180,6 @@ func (s *KafkaStorage) getMessages(offset uint64) ([]Message, error) {
 		return nil, fmt.Errorf("failed to ReadLag: %w", err)
 	}
 	var (
-		message  Message
 		messages []Message
 		i        int64
 	)
@@ -190,6 +189,8 @@ func (s *KafkaStorage) getMessages(offset uint64) ([]Message, error) {
 			break
 		}
 
+		// a new message for every record, otherwise omitted fields are kept from the previous one
+		var message Message
 		if err = json.Unmarshal(kafkaMessage.Value, &message); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal a message %s: %v",
 				string(kafkaMessage.Value), err)
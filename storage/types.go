@@ -3,6 +3,7 @@ package storage
 import (
 	"bytes"
 	"crypto/ed25519"
+	"encoding/binary"
 )
 
 type Message struct {
@@ -14,11 +15,23 @@ type Message struct {
 	Signature     []byte `json:"signature"`
 	SenderAddr    string `json:"sender"`
 	RecipientAddr string `json:"recipient"`
+	// PrevOffset and PrevHash commit the message to the prefix of the append-only log the sender has seen:
+	// PrevHash is the head hash of the first PrevOffset messages of the log
+	PrevOffset uint64 `json:"prev_offset,omitempty"`
+	PrevHash   []byte `json:"prev_hash,omitempty"`
 }
 
+// Bytes returns the signed part of the message. The commitment to the log prefix is signed too and
+// it's followed by the length of the data, so if the commitment is cut off, the data gets trailing bytes
+// and can't be decoded
 func (m *Message) Bytes() []byte {
 	buf := bytes.NewBuffer(nil)
 	buf.Write(m.Data)
+	if m.Committed() {
+		buf.Write(m.PrevHash)
+		_ = binary.Write(buf, binary.BigEndian, m.PrevOffset)
+		_ = binary.Write(buf, binary.BigEndian, uint64(len(m.Data)))
+	}
 
 	return buf.Bytes()
 }
@@ -0,0 +1,262 @@
+package transcript
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/sign/bls"
+	"github.com/lidofinance/dc4bc/client/types"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// ArchiveVersion is the version of the ceremony archive format written by NewArchive
+const ArchiveVersion = 1
+
+// Archive is a signed transcript of a DKG ceremony
+type Archive struct {
+	Version int `json:"version"`
+	// Ceremony is the JSON encoded Ceremony, it's kept as is, so the signature is verified over the same bytes.
+	// The signed bytes are the compact JSON, so the archive may be indented
+	Ceremony json.RawMessage `json:"ceremony"`
+	// Signer is a participant of the DKG round who has exported the archive
+	Signer string `json:"signer"`
+	// Signature is the ed25519 signature of the compact Ceremony JSON made with the key of the Signer from the roster
+	Signature []byte `json:"signature"`
+}
+
+// Ceremony is everything a node knows about a DKG round
+type Ceremony struct {
+	DKGRoundID   string        `json:"dkg_round_id"`
+	ExportedAt   time.Time     `json:"exported_at"`
+	Threshold    int           `json:"threshold"`
+	Participants []Participant `json:"participants"`
+	// Messages are all messages of the DKG round in the order of the log
+	Messages []storage.Message `json:"messages"`
+	// StateHistory is the transitions of the DKG round FSM made by the node of the Signer
+	StateHistory []types.FSMTransition `json:"state_history"`
+	State        fsm.State             `json:"state"`
+	// MasterPubKey is empty if the DKG is not finished
+	MasterPubKey []byte    `json:"master_pub_key,omitempty"`
+	Signings     []Signing `json:"signings"`
+}
+
+// Signing is a signing of the DKG round, Signature is empty if the signature has not been reconstructed
+type Signing struct {
+	SigningID string `json:"signing_id"`
+	Payload   []byte `json:"payload"`
+	Signature []byte `json:"signature,omitempty"`
+	// ReconstructedBy are the participants who have broadcast the same valid signature
+	ReconstructedBy []string `json:"reconstructed_by"`
+}
+
+// ArchiveReport is a machine-readable result of the verification of a ceremony archive
+type ArchiveReport struct {
+	Valid          bool            `json:"valid"`
+	Version        int             `json:"version"`
+	DKGRoundID     string          `json:"dkg_round_id"`
+	Signer         string          `json:"signer"`
+	SignatureValid bool            `json:"signature_valid"`
+	Round          *RoundReport    `json:"round,omitempty"`
+	Signings       []SigningReport `json:"signings"`
+	Errors         []string        `json:"errors"`
+}
+
+// SigningReport is a result of the verification of a reconstructed signature against the master public key
+type SigningReport struct {
+	SigningID string `json:"signing_id"`
+	Valid     bool   `json:"valid"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NewArchive encodes the ceremony and signs it on behalf of the signer
+func NewArchive(ceremony Ceremony, signer string, sign func([]byte) ([]byte, error)) (*Archive, error) {
+	ceremonyBz, err := json.Marshal(ceremony)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ceremony: %w", err)
+	}
+	signature, err := sign(ceremonyBz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign ceremony: %w", err)
+	}
+
+	return &Archive{
+		Version:   ArchiveVersion,
+		Ceremony:  ceremonyBz,
+		Signer:    signer,
+		Signature: signature,
+	}, nil
+}
+
+// ReadArchive reads a JSON encoded ceremony archive
+func ReadArchive(r io.Reader) (*Archive, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	var archive Archive
+	if err = json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal archive: %w", err)
+	}
+	return &archive, nil
+}
+
+// VerifyArchive checks the signature of the archive against the roster of the DKG round, verifies the messages
+// of the round with VerifyRound, checks the state history against the messages and the reconstructed
+// signatures against the master public key
+func VerifyArchive(archive *Archive) *ArchiveReport {
+	report := &ArchiveReport{
+		Version:  archive.Version,
+		Signer:   archive.Signer,
+		Signings: []SigningReport{},
+		Errors:   []string{},
+	}
+	if archive.Version != ArchiveVersion {
+		report.Errors = append(report.Errors, fmt.Sprintf("unsupported archive version %d", archive.Version))
+		return report
+	}
+
+	var ceremony Ceremony
+	if err := json.Unmarshal(archive.Ceremony, &ceremony); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to unmarshal ceremony: %v", err))
+		return report
+	}
+	report.DKGRoundID = ceremony.DKGRoundID
+
+	for _, message := range ceremony.Messages {
+		if message.DkgRoundID != ceremony.DKGRoundID {
+			report.Errors = append(report.Errors, fmt.Sprintf("message %s belongs to DKG round %s", message.ID,
+				message.DkgRoundID))
+		}
+	}
+
+	participants, err := archiveRoster(ceremony)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	var signed bytes.Buffer
+	if err = json.Compact(&signed, archive.Ceremony); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to compact ceremony: %v", err))
+	}
+	for _, participant := range participants {
+		if participant.Username == archive.Signer {
+			report.SignatureValid = ed25519.Verify(participant.PubKey, signed.Bytes(), archive.Signature)
+		}
+	}
+	if !report.SignatureValid {
+		report.Errors = append(report.Errors, fmt.Sprintf("archive is not signed by participant %s",
+			archive.Signer))
+	}
+
+	round := VerifyRound(ceremony.Messages, ceremony.DKGRoundID)
+	report.Round = &round
+	if !bytes.Equal(round.MasterPubKey, ceremony.MasterPubKey) {
+		report.Errors = append(report.Errors, fmt.Sprintf("master public key %x does not match the commits, "+
+			"expected %x", ceremony.MasterPubKey, round.MasterPubKey))
+	}
+
+	report.Errors = append(report.Errors, verifyStateHistory(ceremony)...)
+
+	for _, signing := range ceremony.Signings {
+		signingReport := SigningReport{SigningID: signing.SigningID}
+		if err = verifySigning(round.MasterPubKey, signing); err != nil {
+			signingReport.Error = err.Error()
+		}
+		signingReport.Valid = err == nil
+		report.Signings = append(report.Signings, signingReport)
+	}
+
+	report.Valid = len(report.Errors) == 0 && round.Valid
+	for _, signing := range report.Signings {
+		report.Valid = report.Valid && signing.Valid
+	}
+	return report
+}
+
+// archiveRoster returns the roster of the DKG proposal and checks that the archive has the same one
+func archiveRoster(ceremony Ceremony) ([]Participant, error) {
+	for _, message := range ceremony.Messages {
+		if fsm.Event(message.Event) != spf.EventInitProposal {
+			continue
+		}
+		participants, threshold, err := Roster(message)
+		if err != nil {
+			return nil, err
+		}
+		if threshold != ceremony.Threshold {
+			return participants, fmt.Errorf("threshold %d does not match the DKG proposal threshold %d",
+				ceremony.Threshold, threshold)
+		}
+		participantsBz, _ := json.Marshal(participants)
+		archiveParticipantsBz, _ := json.Marshal(ceremony.Participants)
+		if !bytes.Equal(participantsBz, archiveParticipantsBz) {
+			return participants, errors.New("participants do not match the DKG proposal")
+		}
+		return participants, nil
+	}
+	return nil, errors.New("DKG proposal is not found")
+}
+
+// verifyStateHistory checks that the transitions follow each other and are caused by the messages of the archive
+func verifyStateHistory(ceremony Ceremony) []string {
+	var errs []string
+	messages := make(map[string]storage.Message, len(ceremony.Messages))
+	for _, message := range ceremony.Messages {
+		messages[message.ID] = message
+	}
+
+	var state fsm.State
+	for idx, transition := range ceremony.StateHistory {
+		if idx > 0 && transition.From != state {
+			errs = append(errs, fmt.Sprintf("transition %d starts from state %s, expected %s", idx,
+				transition.From, state))
+		}
+		state = transition.To
+
+		message, ok := messages[transition.MessageID]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Sprintf("message %s of transition %d is not found", transition.MessageID, idx))
+		case message.Offset != transition.Offset || message.SenderAddr != transition.Sender:
+			errs = append(errs, fmt.Sprintf("transition %d does not match message %s", idx, message.ID))
+		case !transition.Auto && fsm.Event(message.Event) != transition.Event:
+			errs = append(errs, fmt.Sprintf("transition %d is made by event %s, message %s has event %s", idx,
+				transition.Event, message.ID, message.Event))
+		}
+	}
+	if len(ceremony.StateHistory) != 0 && state != ceremony.State {
+		errs = append(errs, fmt.Sprintf("state history ends with state %s, the archive has state %s", state,
+			ceremony.State))
+	}
+	return errs
+}
+
+// verifySigning checks the reconstructed signature against the master public key
+func verifySigning(masterPubKeyBz []byte, signing Signing) error {
+	if len(signing.Signature) == 0 {
+		return errors.New("signature is not reconstructed")
+	}
+	if masterPubKeyBz == nil {
+		return errors.New("master public key is unknown")
+	}
+
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+	masterPubKey := blsSuite.Point()
+	if err := masterPubKey.UnmarshalBinary(masterPubKeyBz); err != nil {
+		return fmt.Errorf("failed to unmarshal master public key: %w", err)
+	}
+	if err := bls.Verify(blsSuite.(pairing.Suite), masterPubKey, signing.Payload, signing.Signature); err != nil {
+		return fmt.Errorf("signature is invalid: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package transcript
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/corestario/kyber/pairing"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/sign/bls"
+	"github.com/lidofinance/dc4bc/client/types"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+)
+
+func newTestArchive(t *testing.T) (*testLog, Ceremony) {
+	l := newTestLog(t, 3)
+	masterPubKey, masterSecret := l.appendDKG()
+
+	participants, threshold, err := Roster(l.messages[0])
+	if err != nil {
+		t.Fatalf("failed to get roster: %v", err)
+	}
+	payload := []byte("payload")
+	signature, err := bls.Sign(bls12381.NewBLS12381Suite(nil).(pairing.Suite), masterSecret, payload)
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+
+	ceremony := Ceremony{
+		DKGRoundID:   testDKGRoundID,
+		ExportedAt:   time.Now(),
+		Threshold:    threshold,
+		Participants: participants,
+		Messages:     l.messages,
+		StateHistory: []types.FSMTransition{{
+			DKGRoundID: testDKGRoundID,
+			MessageID:  l.messages[0].ID,
+			Sender:     l.messages[0].SenderAddr,
+			Event:      spf.EventInitProposal,
+			To:         spf.StateAwaitParticipantsConfirmations,
+		}},
+		State:        spf.StateAwaitParticipantsConfirmations,
+		MasterPubKey: masterPubKey,
+		Signings: []Signing{{
+			SigningID:       "signing_id",
+			Payload:         payload,
+			Signature:       signature,
+			ReconstructedBy: []string{username(0)},
+		}},
+	}
+	return l, ceremony
+}
+
+func (l *testLog) sign(participantID int) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		return ed25519.Sign(l.keys[participantID], data), nil
+	}
+}
+
+func TestVerifyArchive(t *testing.T) {
+	l, ceremony := newTestArchive(t)
+	archive, err := NewArchive(ceremony, username(1), l.sign(1))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+
+	archiveBz, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal archive: %v", err)
+	}
+	var indented Archive
+	if err = json.Unmarshal(archiveBz, &indented); err != nil {
+		t.Fatalf("failed to unmarshal archive: %v", err)
+	}
+
+	report := VerifyArchive(&indented)
+	if !report.Valid || !report.SignatureValid || len(report.Signings) != 1 || !report.Signings[0].Valid {
+		t.Fatalf("expected a valid archive, got %+v", report)
+	}
+}
+
+func TestVerifyArchive_Invalid(t *testing.T) {
+	for name, tamper := range map[string]func(l *testLog, ceremony *Ceremony){
+		"forged signing": func(l *testLog, ceremony *Ceremony) {
+			ceremony.Signings[0].Payload = []byte("other payload")
+		},
+		"wrong master key": func(l *testLog, ceremony *Ceremony) {
+			ceremony.MasterPubKey = ceremony.Signings[0].Signature
+		},
+		"foreign roster": func(l *testLog, ceremony *Ceremony) {
+			ceremony.Participants = ceremony.Participants[1:]
+		},
+		"history of other messages": func(l *testLog, ceremony *Ceremony) {
+			ceremony.StateHistory[0].MessageID = "other_message"
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			l, ceremony := newTestArchive(t)
+			tamper(l, &ceremony)
+			archive, err := NewArchive(ceremony, username(0), l.sign(0))
+			if err != nil {
+				t.Fatalf("failed to create archive: %v", err)
+			}
+			if report := VerifyArchive(archive); report.Valid {
+				t.Errorf("expected an invalid archive, got %+v", report)
+			}
+		})
+	}
+
+	t.Run("signed by other key", func(t *testing.T) {
+		l, ceremony := newTestArchive(t)
+		archive, err := NewArchive(ceremony, username(0), l.sign(1))
+		if err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		}
+		if report := VerifyArchive(archive); report.Valid || report.SignatureValid {
+			t.Errorf("expected an invalid signature, got %+v", report)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Package transcript verifies the transcripts of DKG ceremonies offline, without trusting any node:
+// the append-only log exported from the message board and the signed ceremony archives
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// ReadLog reads the messages of an append-only log from a FileStorage data file, which has a JSON message
+// on every line, or from a JSON array of messages. Messages without offsets, e.g. dumped by a Kafka consumer,
+// get offsets by their position
+func ReadLog(r io.Reader) ([]storage.Message, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	var messages []storage.Message
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '[' {
+		if err = json.Unmarshal(trimmed, &messages); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal messages: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, len(data)+1)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var message storage.Message
+			if err = json.Unmarshal(line, &message); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal message %d: %w", len(messages), err)
+			}
+			messages = append(messages, message)
+		}
+		if err = scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read log: %w", err)
+		}
+	}
+
+	withOffsets := false
+	for _, message := range messages {
+		withOffsets = withOffsets || message.Offset != 0
+	}
+	if !withOffsets {
+		for idx := range messages {
+			messages[idx].Offset = uint64(idx)
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Offset < messages[j].Offset
+	})
+
+	return messages, nil
+}
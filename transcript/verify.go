@@ -0,0 +1,347 @@
+package transcript
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/corestario/kyber"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/share"
+	"github.com/lidofinance/dc4bc/fsm/fsm"
+	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+// Report is a machine-readable result of the verification of an append-only log
+type Report struct {
+	Valid    bool `json:"valid"`
+	Messages int  `json:"messages"`
+	// LogErrors are gaps in the log and commitments to log prefixes which don't match the log
+	LogErrors []string `json:"log_errors"`
+	// UnverifiedCommitments is the number of commitments to log prefixes which are not in the log
+	UnverifiedCommitments int           `json:"unverified_commitments"`
+	Rounds                []RoundReport `json:"rounds"`
+}
+
+// RoundReport is a result of the verification of a DKG round
+type RoundReport struct {
+	DKGRoundID   string              `json:"dkg_round_id"`
+	Valid        bool                `json:"valid"`
+	Threshold    int                 `json:"threshold"`
+	Messages     int                 `json:"messages"`
+	Participants []ParticipantReport `json:"participants"`
+	// MasterPubKey is the distributed public key recomputed from the commits broadcast by participants
+	MasterPubKey    []byte         `json:"master_pub_key,omitempty"`
+	InvalidMessages []MessageError `json:"invalid_messages"`
+	Errors          []string       `json:"errors"`
+}
+
+// ParticipantReport describes what a participant of the DKG round has broadcast
+type ParticipantReport struct {
+	ParticipantID int    `json:"participant_id"`
+	Username      string `json:"username"`
+	PubKey        []byte `json:"pub_key"`
+	// Messages is the number of messages of the participant with valid signatures
+	Messages  int    `json:"messages"`
+	Commits   bool   `json:"commits"`
+	MasterKey []byte `json:"master_key,omitempty"`
+	// MasterKeyMatches is set if the master key confirmed by the participant is the recomputed MasterPubKey
+	MasterKeyMatches bool `json:"master_key_matches"`
+}
+
+// MessageError describes a message of the DKG round which failed the verification
+type MessageError struct {
+	ID     string `json:"id"`
+	Offset uint64 `json:"offset"`
+	Event  string `json:"event"`
+	Sender string `json:"sender"`
+	Error  string `json:"error"`
+}
+
+// Participant is an entry of the roster of a DKG round, its ID is the position in the DKG proposal
+type Participant struct {
+	ID        int               `json:"participant_id"`
+	Username  string            `json:"username"`
+	PubKey    ed25519.PublicKey `json:"pub_key"`
+	DkgPubKey []byte            `json:"dkg_pub_key"`
+}
+
+// Roster returns the participants and the threshold of the DKG round proposed by the message
+func Roster(proposal storage.Message) ([]Participant, int, error) {
+	if fsm.Event(proposal.Event) != spf.EventInitProposal {
+		return nil, 0, fmt.Errorf("message %s is not a DKG proposal", proposal.ID)
+	}
+	var request requests.SignatureProposalParticipantsListRequest
+	if err := json.Unmarshal(proposal.Data, &request); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal DKG proposal: %w", err)
+	}
+
+	participants := make([]Participant, 0, len(request.Participants))
+	for idx, entry := range request.Participants {
+		participants = append(participants, Participant{
+			ID:        idx,
+			Username:  entry.Username,
+			PubKey:    entry.PubKey,
+			DkgPubKey: entry.DkgPubKey,
+		})
+	}
+	return participants, request.SigningThreshold, nil
+}
+
+// VerifyLog verifies the hash chain of the log and the DKG rounds with the given IDs,
+// all DKG rounds proposed in the log are verified if no IDs are given
+func VerifyLog(messages []storage.Message, dkgRoundIDs ...string) *Report {
+	report := &Report{
+		Messages:  len(messages),
+		LogErrors: []string{},
+		Rounds:    []RoundReport{},
+	}
+	report.LogErrors, report.UnverifiedCommitments = VerifyChain(messages)
+
+	if len(dkgRoundIDs) == 0 {
+		for _, message := range messages {
+			if fsm.Event(message.Event) == spf.EventInitProposal {
+				dkgRoundIDs = append(dkgRoundIDs, message.DkgRoundID)
+			}
+		}
+	}
+	if len(dkgRoundIDs) == 0 {
+		report.LogErrors = append(report.LogErrors, "no DKG rounds are proposed in the log")
+	}
+	report.Valid = len(report.LogErrors) == 0
+	for _, dkgRoundID := range dkgRoundIDs {
+		round := VerifyRound(messages, dkgRoundID)
+		report.Valid = report.Valid && round.Valid
+		report.Rounds = append(report.Rounds, round)
+	}
+
+	return report
+}
+
+// VerifyChain checks that the offsets of the messages have no gaps and that the messages commit to the head hashes
+// of the log. Head hashes are known only if the log starts from the first message, commitments to unknown
+// head hashes are counted
+func VerifyChain(messages []storage.Message) (logErrors []string, unverified int) {
+	logErrors = []string{}
+	heads := map[uint64][]byte{}
+	var head []byte
+	if len(messages) != 0 && messages[0].Offset == 0 {
+		head = storage.GenesisHeadHash
+		heads[0] = head
+	}
+
+	for idx, message := range messages {
+		if idx > 0 && message.Offset != messages[idx-1].Offset+1 {
+			logErrors = append(logErrors, fmt.Sprintf("expected message with offset %d, got %d",
+				messages[idx-1].Offset+1, message.Offset))
+			head = nil
+		}
+
+		if message.Committed() {
+			known, ok := heads[message.PrevOffset]
+			switch {
+			case message.PrevOffset > message.Offset:
+				logErrors = append(logErrors, fmt.Sprintf("message %s with offset %d commits to %d messages",
+					message.ID, message.Offset, message.PrevOffset))
+			case !ok:
+				unverified++
+			case !bytes.Equal(known, message.PrevHash):
+				logErrors = append(logErrors, fmt.Sprintf(
+					"message %s from %s commits to head hash %x of the first %d messages, the log has %x",
+					message.ID, message.SenderAddr, message.PrevHash, message.PrevOffset, known))
+			}
+		}
+
+		if head != nil {
+			head = storage.NextHeadHash(head, message)
+			heads[message.Offset+1] = head
+		}
+	}
+
+	return logErrors, unverified
+}
+
+// VerifyRound checks the signatures of all messages of the DKG round against its roster, recomputes the master
+// public key from the broadcast commits and checks that every participant has confirmed the same key
+func VerifyRound(messages []storage.Message, dkgRoundID string) RoundReport {
+	report := RoundReport{
+		DKGRoundID:      dkgRoundID,
+		Participants:    []ParticipantReport{},
+		InvalidMessages: []MessageError{},
+		Errors:          []string{},
+	}
+
+	var roundMessages []storage.Message
+	for _, message := range messages {
+		if message.DkgRoundID == dkgRoundID {
+			roundMessages = append(roundMessages, message)
+		}
+	}
+	report.Messages = len(roundMessages)
+
+	var (
+		participants []Participant
+		err          error
+	)
+	for _, message := range roundMessages {
+		if fsm.Event(message.Event) == spf.EventInitProposal {
+			participants, report.Threshold, err = Roster(message)
+			break
+		}
+	}
+	if err != nil || participants == nil {
+		if err == nil {
+			err = errors.New("DKG proposal is not found")
+		}
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+
+	byUsername := map[string]int{}
+	for _, participant := range participants {
+		byUsername[participant.Username] = participant.ID
+		report.Participants = append(report.Participants, ParticipantReport{
+			ParticipantID: participant.ID,
+			Username:      participant.Username,
+			PubKey:        participant.PubKey,
+		})
+	}
+
+	commits := make(map[int][]byte)
+	masterKeys := make(map[int][]byte)
+	for _, message := range roundMessages {
+		participantID, err := verifyMessage(message, participants, byUsername)
+		if err == nil {
+			switch fsm.Event(message.Event) {
+			case dpf.EventDKGCommitConfirmationReceived:
+				var request requests.DKGProposalCommitConfirmationRequest
+				if err = decodeRequest(message, participantID, &request, &request.ParticipantId); err == nil {
+					err = keepFirst(commits, participantID, request.Commit, "commits")
+				}
+			case dpf.EventDKGMasterKeyConfirmationReceived:
+				var request requests.DKGProposalMasterKeyConfirmationRequest
+				if err = decodeRequest(message, participantID, &request, &request.ParticipantId); err == nil {
+					err = keepFirst(masterKeys, participantID, request.MasterKey, "master keys")
+				}
+			}
+		}
+		if err != nil {
+			report.InvalidMessages = append(report.InvalidMessages, MessageError{
+				ID:     message.ID,
+				Offset: message.Offset,
+				Event:  message.Event,
+				Sender: message.SenderAddr,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		report.Participants[participantID].Messages++
+	}
+
+	masterPubKey, err := masterPubKeyFromCommits(participants, commits)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	} else if report.MasterPubKey, err = masterPubKey.MarshalBinary(); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to marshal master public key: %v", err))
+	}
+
+	for idx := range report.Participants {
+		participant := &report.Participants[idx]
+		participant.Commits = commits[participant.ParticipantID] != nil
+		participant.MasterKey = masterKeys[participant.ParticipantID]
+		if participant.MasterKey == nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("participant %s has not confirmed the master key",
+				participant.Username))
+			continue
+		}
+		participant.MasterKeyMatches = report.MasterPubKey != nil && bytes.Equal(participant.MasterKey,
+			report.MasterPubKey)
+		if report.MasterPubKey != nil && !participant.MasterKeyMatches {
+			report.Errors = append(report.Errors, fmt.Sprintf(
+				"master key of participant %s does not match the commits", participant.Username))
+		}
+	}
+
+	report.Valid = len(report.Errors) == 0 && len(report.InvalidMessages) == 0
+	return report
+}
+
+// verifyMessage checks that the message is signed by a participant of the roster and returns the participant ID
+func verifyMessage(message storage.Message, participants []Participant, byUsername map[string]int) (int, error) {
+	participantID, ok := byUsername[message.SenderAddr]
+	if !ok {
+		return 0, fmt.Errorf("sender %s is not a participant", message.SenderAddr)
+	}
+	if !ed25519.Verify(participants[participantID].PubKey, message.Bytes(), message.Signature) {
+		return 0, errors.New("signature is corrupt")
+	}
+	return participantID, nil
+}
+
+// decodeRequest unmarshals the request of the message and checks that it's sent on behalf of the sender
+func decodeRequest(message storage.Message, participantID int, request interface{}, requestParticipantID *int) error {
+	if err := json.Unmarshal(message.Data, request); err != nil {
+		return fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	if *requestParticipantID != participantID {
+		return fmt.Errorf("request of participant %d is sent by participant %d", *requestParticipantID,
+			participantID)
+	}
+	return nil
+}
+
+// keepFirst saves the value broadcast by the participant, a different value broadcast again is an error
+func keepFirst(values map[int][]byte, participantID int, value []byte, name string) error {
+	if known, ok := values[participantID]; ok {
+		if !bytes.Equal(known, value) {
+			return fmt.Errorf("participant %d has broadcast different %s", participantID, name)
+		}
+		return nil
+	}
+	values[participantID] = value
+	return nil
+}
+
+// masterPubKeyFromCommits sums up the commits of all participants into the public polynomial
+// and returns its constant term
+func masterPubKeyFromCommits(participants []Participant, commits map[int][]byte) (kyber.Point, error) {
+	// the suite is used to work with public DKG data only, so it does not need a seed
+	blsSuite := bls12381.NewBLS12381Suite(nil)
+
+	var commitments []kyber.Point
+	for _, participant := range participants {
+		commitBz, ok := commits[participant.ID]
+		if !ok {
+			return nil, fmt.Errorf("participant %s has not broadcast commits", participant.Username)
+		}
+		var commitsBz [][]byte
+		if err := json.Unmarshal(commitBz, &commitsBz); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal commits of participant %s: %w", participant.Username, err)
+		}
+		if commitments != nil && len(commitsBz) != len(commitments) {
+			return nil, fmt.Errorf("participant %s broadcasted %d commits, expected %d", participant.Username,
+				len(commitsBz), len(commitments))
+		}
+		for idx, commitBz := range commitsBz {
+			commit := blsSuite.Point()
+			if err := commit.UnmarshalBinary(commitBz); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal commit of participant %s: %w", participant.Username, err)
+			}
+			if len(commitments) <= idx {
+				commitments = append(commitments, commit)
+				continue
+			}
+			commitments[idx] = blsSuite.Point().Add(commitments[idx], commit)
+		}
+	}
+	if len(commitments) == 0 {
+		return nil, errors.New("no commits were broadcasted")
+	}
+
+	return share.NewPubPoly(blsSuite, nil, commitments).Commit(), nil
+}
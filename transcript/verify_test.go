@@ -0,0 +1,223 @@
+package transcript
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/corestario/kyber"
+	bls12381 "github.com/corestario/kyber/pairing/bls12381"
+	"github.com/corestario/kyber/share"
+	dpf "github.com/lidofinance/dc4bc/fsm/state_machines/dkg_proposal_fsm"
+	spf "github.com/lidofinance/dc4bc/fsm/state_machines/signature_proposal_fsm"
+	"github.com/lidofinance/dc4bc/fsm/types/requests"
+	"github.com/lidofinance/dc4bc/storage"
+)
+
+const (
+	testDKGRoundID = "dkg_round_id"
+	testThreshold  = 2
+)
+
+type testLog struct {
+	t        *testing.T
+	keys     []ed25519.PrivateKey
+	messages []storage.Message
+	head     []byte
+}
+
+func newTestLog(t *testing.T, participants int) *testLog {
+	l := &testLog{t: t, head: storage.GenesisHeadHash}
+
+	request := requests.SignatureProposalParticipantsListRequest{SigningThreshold: testThreshold}
+	for idx := 0; idx < participants; idx++ {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		l.keys = append(l.keys, priv)
+		request.Participants = append(request.Participants, &requests.SignatureProposalParticipantsEntry{
+			Username: username(idx),
+			PubKey:   pub,
+		})
+	}
+	l.append(0, string(spf.EventInitProposal), request)
+	return l
+}
+
+func username(participantID int) string {
+	return fmt.Sprintf("participant_%d", participantID)
+}
+
+// append signs the request by the participant and commits the message to the current head of the log
+func (l *testLog) append(participantID int, event string, request interface{}) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		l.t.Fatalf("failed to marshal request: %v", err)
+	}
+	message := storage.Message{
+		ID:         fmt.Sprintf("message_%d", len(l.messages)),
+		DkgRoundID: testDKGRoundID,
+		Offset:     uint64(len(l.messages)),
+		Event:      event,
+		Data:       data,
+		SenderAddr: username(participantID),
+		PrevOffset: uint64(len(l.messages)),
+		PrevHash:   l.head,
+	}
+	message.Signature = ed25519.Sign(l.keys[participantID], message.Bytes())
+	l.head = storage.NextHeadHash(l.head, message)
+	l.messages = append(l.messages, message)
+}
+
+// appendDKG appends commits of all participants and confirmations of the master key computed from them,
+// it returns the master public key and the master secret
+func (l *testLog) appendDKG() ([]byte, kyber.Scalar) {
+	suite := bls12381.NewBLS12381Suite(nil)
+	var masterPubKey = suite.Point().Null()
+	var masterSecret = suite.Scalar().Zero()
+	for idx := range l.keys {
+		priPoly := share.NewPriPoly(suite, testThreshold, nil, suite.RandomStream())
+		_, commits := priPoly.Commit(nil).Info()
+		var commitsBz [][]byte
+		for _, commit := range commits {
+			commitBz, err := commit.MarshalBinary()
+			if err != nil {
+				l.t.Fatalf("failed to marshal commit: %v", err)
+			}
+			commitsBz = append(commitsBz, commitBz)
+		}
+		commitsJSON, err := json.Marshal(commitsBz)
+		if err != nil {
+			l.t.Fatalf("failed to marshal commits: %v", err)
+		}
+		l.append(idx, string(dpf.EventDKGCommitConfirmationReceived), requests.DKGProposalCommitConfirmationRequest{
+			ParticipantId: idx,
+			Commit:        commitsJSON,
+		})
+		masterPubKey = suite.Point().Add(masterPubKey, commits[0])
+		masterSecret = suite.Scalar().Add(masterSecret, priPoly.Secret())
+	}
+
+	masterPubKeyBz, err := masterPubKey.MarshalBinary()
+	if err != nil {
+		l.t.Fatalf("failed to marshal master public key: %v", err)
+	}
+	for idx := range l.keys {
+		l.append(idx, string(dpf.EventDKGMasterKeyConfirmationReceived),
+			requests.DKGProposalMasterKeyConfirmationRequest{
+				ParticipantId: idx,
+				MasterKey:     masterPubKeyBz,
+			})
+	}
+	return masterPubKeyBz, masterSecret
+}
+
+func TestVerifyLog(t *testing.T) {
+	l := newTestLog(t, 3)
+	masterPubKey, _ := l.appendDKG()
+
+	report := VerifyLog(l.messages)
+	if !report.Valid || len(report.Rounds) != 1 {
+		t.Fatalf("expected a valid log with one round, got %+v", report)
+	}
+	round := report.Rounds[0]
+	if !bytes.Equal(round.MasterPubKey, masterPubKey) {
+		t.Errorf("expected master public key %x, got %x", masterPubKey, round.MasterPubKey)
+	}
+	for _, participant := range round.Participants {
+		if !participant.Commits || !participant.MasterKeyMatches {
+			t.Errorf("expected verified commits and master key of %s", participant.Username)
+		}
+	}
+	if report.UnverifiedCommitments != 0 {
+		t.Errorf("expected all commitments verified, got %d unverified", report.UnverifiedCommitments)
+	}
+}
+
+func TestVerifyLog_Invalid(t *testing.T) {
+	t.Run("forged signature", func(t *testing.T) {
+		l := newTestLog(t, 3)
+		l.appendDKG()
+		l.messages[2].Data = append([]byte{}, l.messages[1].Data...)
+
+		round := VerifyLog(l.messages).Rounds[0]
+		if round.Valid || len(round.InvalidMessages) == 0 {
+			t.Fatalf("expected the forged message to be reported, got %+v", round)
+		}
+		if round.InvalidMessages[0].ID != l.messages[2].ID {
+			t.Errorf("expected message %s to be invalid, got %s", l.messages[2].ID, round.InvalidMessages[0].ID)
+		}
+	})
+
+	t.Run("wrong master key", func(t *testing.T) {
+		l := newTestLog(t, 3)
+		l.appendDKG()
+		l.messages = l.messages[:len(l.messages)-1]
+		l.head = storage.NextHeadHash(storage.GenesisHeadHash, l.messages[0])
+		for _, message := range l.messages[1:] {
+			l.head = storage.NextHeadHash(l.head, message)
+		}
+		l.append(2, string(dpf.EventDKGMasterKeyConfirmationReceived), requests.DKGProposalMasterKeyConfirmationRequest{
+			ParticipantId: 2,
+			MasterKey:     []byte("wrong master key"),
+		})
+
+		report := VerifyLog(l.messages)
+		if report.Valid || report.Rounds[0].Participants[2].MasterKeyMatches {
+			t.Fatalf("expected the mismatching master key to be reported, got %+v", report.Rounds[0])
+		}
+	})
+
+	t.Run("rewritten log", func(t *testing.T) {
+		l := newTestLog(t, 3)
+		l.appendDKG()
+		l.messages[1].Signature = ed25519.Sign(l.keys[0], []byte("other"))
+
+		report := VerifyLog(l.messages)
+		if report.Valid || len(report.LogErrors) == 0 {
+			t.Fatalf("expected a log error, got %+v", report.LogErrors)
+		}
+	})
+
+	t.Run("gap", func(t *testing.T) {
+		l := newTestLog(t, 3)
+		l.appendDKG()
+		l.messages = append(l.messages[:1], l.messages[2:]...)
+
+		report := VerifyLog(l.messages)
+		if report.Valid || len(report.LogErrors) == 0 {
+			t.Fatalf("expected a log error, got %+v", report.LogErrors)
+		}
+	})
+}
+
+func TestReadLog(t *testing.T) {
+	l := newTestLog(t, 2)
+	l.appendDKG()
+
+	var lines []string
+	for _, message := range l.messages {
+		line, err := json.Marshal(message)
+		if err != nil {
+			t.Fatalf("failed to marshal message: %v", err)
+		}
+		lines = append(lines, string(line))
+	}
+
+	for name, data := range map[string]string{
+		"lines": strings.Join(lines, "\n") + "\n",
+		"array": "[" + strings.Join(lines, ",") + "]",
+	} {
+		messages, err := ReadLog(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: failed to read log: %v", name, err)
+		}
+		if !VerifyLog(messages).Valid {
+			t.Errorf("%s: expected a valid log", name)
+		}
+	}
+}